@@ -0,0 +1,308 @@
+package tview
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The states of the ANSI escape sequence parser.
+const (
+	ansiText = iota
+	ansiEscape
+	ansiControlSequence
+	ansiOperatingSystemCommand
+	ansiOperatingSystemCommandEscape
+	ansiCharacterSet
+)
+
+// ansiColors are the names of the 16 basic ANSI colors, in the order of their
+// codes.
+var ansiColors = []string{
+	"black", "maroon", "green", "olive", "navy", "purple", "teal", "silver",
+	"gray", "red", "lime", "yellow", "blue", "fuchsia", "aqua", "white",
+}
+
+// openTagPattern matches an opening square bracket at the end of a text which
+// may still become a tag.
+var openTagPattern = regexp.MustCompile(`\[[a-zA-Z0-9_,;: \-\."#]*$`)
+
+// ansi is an io.WriteCloser which translates ANSI escape sequences into color tags.
+type ansi struct {
+	io.Writer
+
+	// The current state of the parser.
+	state int
+
+	// The parameters of the control sequence being parsed.
+	parameters bytes.Buffer
+
+	// Text which was not written yet because it follows an opening square
+	// bracket which may still become a tag.
+	text bytes.Buffer
+
+	// The translated output of the current Write() call.
+	buffer bytes.Buffer
+
+	// The current style: foreground color, background color, and attributes.
+	foreground, background string
+	attributes             map[rune]bool
+}
+
+// ANSIWriter returns an io.WriteCloser which translates ANSI escape sequences
+// written to it into color tags and writes the result to the given writer,
+// typically a TextView with dynamic colors enabled:
+//
+//   textView := tview.NewTextView().SetDynamicColors(true)
+//   writer := tview.ANSIWriter(textView)
+//   cmd := exec.Command("git", "log", "--color")
+//   cmd.Stdout = writer
+//   err := cmd.Run()
+//   writer.Close()
+//
+// Colors (the 16 basic colors, the 256-color palette, and 24-bit colors) and
+// text attributes (bold, dim, italic, underline, blink, and reverse) are
+// translated. All other escape sequences, e.g. cursor movements, are removed.
+// Text which would otherwise be interpreted as tags is escaped (see Escape()).
+//
+// Escape sequences may be split across calls to Write(). Text following an
+// opening square bracket is held back until it is clear whether it forms a
+// tag. Call Close() when no more text will be written (e.g. when the
+// subprocess exited) to write the text which is still held back. The given
+// writer is not closed.
+func ANSIWriter(writer io.Writer) io.WriteCloser {
+	return &ansi{
+		Writer:     writer,
+		foreground: "-",
+		background: "-",
+		attributes: make(map[rune]bool),
+	}
+}
+
+// TranslateANSI replaces ANSI escape sequences in the given text with color
+// tags. See ANSIWriter() for details.
+func TranslateANSI(text string) string {
+	var buffer bytes.Buffer
+	writer := ANSIWriter(&buffer)
+	writer.Write([]byte(text))
+	writer.Close()
+	return buffer.String()
+}
+
+// Write translates the given bytes and writes them to the underlying writer.
+func (a *ansi) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch a.state {
+		case ansiEscape:
+			switch b {
+			case '[':
+				a.state = ansiControlSequence
+				a.parameters.Reset()
+			case ']':
+				a.state = ansiOperatingSystemCommand
+			case '(', ')', '*', '+':
+				a.state = ansiCharacterSet
+			default:
+				a.state = ansiText // Two-character sequences are removed.
+			}
+		case ansiControlSequence:
+			switch {
+			case b >= 0x30 && b <= 0x3f: // Parameter bytes.
+				a.parameters.WriteByte(b)
+			case b >= 0x20 && b <= 0x2f: // Intermediate bytes.
+			case b >= 0x40 && b <= 0x7e: // Final byte.
+				if b == 'm' {
+					a.selectGraphicRendition(a.parameters.String())
+				}
+				a.state = ansiText
+			default:
+				a.state = ansiText // Invalid sequence.
+			}
+		case ansiOperatingSystemCommand:
+			switch b {
+			case 0x07: // BEL
+				a.state = ansiText
+			case 0x1b: // ESC
+				a.state = ansiOperatingSystemCommandEscape
+			}
+		case ansiOperatingSystemCommandEscape, ansiCharacterSet:
+			a.state = ansiText
+		default:
+			if b == 0x1b {
+				a.state = ansiEscape
+			} else {
+				a.text.WriteByte(b)
+			}
+		}
+	}
+
+	a.flushText(false)
+	_, err := a.Writer.Write(a.buffer.Bytes())
+	a.buffer.Reset()
+	return len(p), err
+}
+
+// Close writes the text which is still held back because it follows an
+// opening square bracket to the underlying writer, escaped, as no tag can
+// follow anymore. The writer may still be used afterwards.
+func (a *ansi) Close() error {
+	a.flushText(true)
+	_, err := a.Writer.Write(a.buffer.Bytes())
+	a.buffer.Reset()
+	return err
+}
+
+// flushText moves the text collected so far to the output, escaping it. If
+// "all" is false, text following an opening square bracket which may still
+// become a tag is kept.
+func (a *ansi) flushText(all bool) {
+	text := a.text.String()
+	a.text.Reset()
+	if !all {
+		if location := openTagPattern.FindStringIndex(text); location != nil {
+			a.text.WriteString(text[location[0]:])
+			text = text[:location[0]]
+		}
+	}
+	a.buffer.WriteString(Escape(text))
+}
+
+// selectGraphicRendition applies the parameters of an SGR control sequence
+// ("ESC [ ... m") to the current style and writes a color tag for the new
+// style.
+func (a *ansi) selectGraphicRendition(parameters string) {
+	a.flushText(true)
+
+	fields := strings.Split(parameters, ";")
+	for index := 0; index < len(fields); index++ {
+		code, _ := strconv.Atoi(fields[index]) // Empty fields are 0.
+		switch {
+		case code == 0:
+			a.foreground, a.background = "-", "-"
+			a.attributes = make(map[rune]bool)
+		case code == 1:
+			a.attributes['b'] = true
+		case code == 2:
+			a.attributes['d'] = true
+		case code == 3:
+			a.attributes['i'] = true
+		case code == 4:
+			a.attributes['u'] = true
+		case code == 5 || code == 6:
+			a.attributes['l'] = true
+		case code == 7:
+			a.attributes['r'] = true
+		case code == 22:
+			delete(a.attributes, 'b')
+			delete(a.attributes, 'd')
+		case code == 23:
+			delete(a.attributes, 'i')
+		case code == 24:
+			delete(a.attributes, 'u')
+		case code == 25:
+			delete(a.attributes, 'l')
+		case code == 27:
+			delete(a.attributes, 'r')
+		case code >= 30 && code <= 37:
+			a.foreground = ansiColors[code-30]
+		case code >= 90 && code <= 97:
+			a.foreground = ansiColors[code-90+8]
+		case code == 39:
+			a.foreground = "-"
+		case code >= 40 && code <= 47:
+			a.background = ansiColors[code-40]
+		case code >= 100 && code <= 107:
+			a.background = ansiColors[code-100+8]
+		case code == 49:
+			a.background = "-"
+		case code == 38 || code == 48:
+			color, consumed := parseExtendedColor(fields[index+1:])
+			index += consumed
+			if color == "" {
+				break
+			}
+			if code == 38 {
+				a.foreground = color
+			} else {
+				a.background = color
+			}
+		}
+	}
+
+	attributes := ""
+	for _, letter := range "bdilru" {
+		if a.attributes[letter] {
+			attributes += string(letter)
+		}
+	}
+	if attributes == "" {
+		attributes = "-"
+	}
+	fmt.Fprintf(&a.buffer, "[%s:%s:%s]", a.foreground, a.background, attributes)
+}
+
+// parseExtendedColor parses the fields following an SGR code 38 or 48, i.e.
+// "5;n" for the 256-color palette or "2;r;g;b" for 24-bit colors. It returns
+// the color's name or hex code (an empty string if the fields are invalid) and
+// the number of fields consumed.
+func parseExtendedColor(fields []string) (string, int) {
+	if len(fields) == 0 {
+		return "", 0
+	}
+	values := make([]int, 0, 4)
+	for _, field := range fields {
+		value, _ := strconv.Atoi(field)
+		values = append(values, value)
+	}
+
+	switch values[0] {
+	case 5:
+		if len(values) < 2 {
+			return "", len(values)
+		}
+		index := values[1]
+		switch {
+		case index < 0 || index > 255:
+			return "", 2
+		case index < 16:
+			return ansiColors[index], 2
+		case index < 232:
+			// 6x6x6 color cube.
+			index -= 16
+			level := func(l int) int {
+				if l == 0 {
+					return 0
+				}
+				return 55 + 40*l
+			}
+			return hexColor(level(index/36), level(index/6%6), level(index%6)), 2
+		default:
+			// Grayscale ramp.
+			gray := 8 + 10*(index-232)
+			return hexColor(gray, gray, gray), 2
+		}
+	case 2:
+		if len(values) < 4 {
+			return "", len(values)
+		}
+		return hexColor(values[1], values[2], values[3]), 4
+	}
+	return "", 1
+}
+
+// hexColor returns the hex code of the given RGB color for use in color tags.
+func hexColor(r, g, b int) string {
+	clamp := func(value int) int {
+		if value < 0 {
+			return 0
+		}
+		if value > 255 {
+			return 255
+		}
+		return value
+	}
+	return fmt.Sprintf("#%02x%02x%02x", clamp(r), clamp(g), clamp(b))
+}
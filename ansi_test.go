@@ -0,0 +1,47 @@
+package tview
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTranslateANSI(t *testing.T) {
+	for text, want := range map[string]string{
+		"\x1b[1;31mred\x1b[0m plain":            "[maroon:-:b]red[-:-:-] plain",
+		"\x1b[38;5;196mX\x1b[48;2;1;2;3mY":      "[#ff0000:-:-]X[#ff0000:#010203:-]Y",
+		"\x1b[94;4mU\x1b[24m":                   "[blue:-:u]U[blue:-:-]",
+		"a\x1b[2Kb\x1b[10;5Hc\x1b]0;title\x07d": "abcd",
+		"[red] and [a, b]":                      "[red[] and [a, b[]",
+		"progress [42":                          "progress [42",
+	} {
+		if translated := TranslateANSI(text); translated != want {
+			t.Errorf("TranslateANSI(%q) = %q, want %q", text, translated, want)
+		}
+	}
+}
+
+func TestANSIWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := ANSIWriter(&buffer)
+	for _, chunk := range []string{"\x1b[3", "2mgr", "een\x1b[0m [re", "d] x\n"} {
+		writer.Write([]byte(chunk))
+	}
+	if text, want := buffer.String(), "[green:-:-]green[-:-:-] [red[] x\n"; text != want {
+		t.Errorf("writer wrote %q, want %q", text, want)
+	}
+}
+
+func TestANSIWriterClose(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := ANSIWriter(&buffer)
+	writer.Write([]byte("Continue? [y"))
+	if text := buffer.String(); text != "Continue? " {
+		t.Errorf("writer wrote %q before Close(), want the bracket to be held back", text)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if text, want := buffer.String(), "Continue? [y"; text != want {
+		t.Errorf("writer wrote %q after Close(), want %q", text, want)
+	}
+}
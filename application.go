@@ -2,10 +2,45 @@ package tview
 
 import (
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell"
 )
 
+// The size of the event/update queues. Posting to a full queue blocks until
+// the event loop has caught up.
+const queueSize = 100
+
+// DoubleClickInterval specifies the maximum time between two clicks at the
+// same position to register a double click rather than two single clicks.
+var DoubleClickInterval = 500 * time.Millisecond
+
+// MouseAction indicates one of the actions the mouse is logically doing. The
+// Application derives these actions from the raw tcell mouse events.
+type MouseAction int16
+
+// Available mouse actions.
+const (
+	MouseMove MouseAction = iota
+	MouseLeftDown
+	MouseLeftUp
+	MouseLeftClick
+	MouseLeftDoubleClick
+	MouseLeftDrag
+	MouseMiddleDown
+	MouseMiddleUp
+	MouseMiddleClick
+	MouseMiddleDoubleClick
+	MouseRightDown
+	MouseRightUp
+	MouseRightClick
+	MouseRightDoubleClick
+	MouseScrollUp
+	MouseScrollDown
+	MouseScrollLeft
+	MouseScrollRight
+)
+
 // Application represents the top node of an application.
 //
 // It is not strictly required to use this class as none of the other classes
@@ -30,11 +65,56 @@ type Application struct {
 	// event to be forwarded to the default input handler (nil if nothing should
 	// be forwarded).
 	inputCapture func(event tcell.Event) tcell.Event
+
+	// Whether or not mouse events are reported by the screen.
+	enableMouse bool
+
+	// The primitive which receives all mouse events until it releases the
+	// capture. If nil, mouse events are passed to the root primitive.
+	mouseCapture Primitive
+
+	// The buttons pressed during the last mouse event.
+	lastMouseButtons tcell.ButtonMask
+
+	// The last mouse position and the position where a button was last pressed.
+	lastMouseX, lastMouseY, mouseDownX, mouseDownY int
+
+	// The time, the button and the position of the last click, used to detect
+	// double clicks.
+	lastMouseClick                   time.Time
+	lastMouseClickButton             tcell.ButtonMask
+	lastMouseClickX, lastMouseClickY int
+
+	// An optional keymap which handles key events before they are passed to
+	// the focus manager and the focused primitive.
+	keymap *Keymap
+
+	// An optional focus manager which handles key events before they are
+	// passed to the focused primitive.
+	focusManager *FocusManager
+
+	// The theme set with SetTheme(), if any.
+	theme *Theme
+
+	// Events received from the screen, waiting to be processed by the event
+	// loop.
+	events chan tcell.Event
+
+	// Functions queued from other goroutines, waiting to be executed by the
+	// event loop.
+	updates chan func()
+
+	// Closed when Run() returns so that nobody waits for the event loop
+	// anymore.
+	done chan struct{}
 }
 
 // NewApplication creates and returns a new application.
 func NewApplication() *Application {
-	return &Application{}
+	return &Application{
+		events:  make(chan tcell.Event, queueSize),
+		updates: make(chan func(), queueSize),
+	}
 }
 
 // SetInputCapture sets a function which captures all key events before they are
@@ -54,6 +134,109 @@ func (a *Application) SetInputCapture(capture func(event tcell.Event) tcell.Even
 	return a
 }
 
+// EnableMouse sets whether or not the screen reports mouse events. Mouse
+// events are then dispatched to the primitives' mouse handlers (see
+// Primitive.MouseHandler()). Mouse support is disabled by default.
+func (a *Application) EnableMouse(enable bool) *Application {
+	a.Lock()
+	defer a.Unlock()
+
+	a.enableMouse = enable
+	if a.screen != nil {
+		if enable {
+			a.screen.EnableMouse()
+		} else {
+			a.screen.DisableMouse()
+		}
+	}
+	return a
+}
+
+// SetKeymap installs a keymap whose key bindings are handled before key
+// events are passed on to the focus manager and the focused primitive (see
+// Keymap). Provide nil to remove it again.
+func (a *Application) SetKeymap(keymap *Keymap) *Application {
+	a.Lock()
+	defer a.Unlock()
+
+	if keymap != nil {
+		keymap.app = a
+		if a.theme != nil {
+			keymap.helpBox.ApplyTheme(a.theme)
+		}
+	}
+	a.keymap = keymap
+	return a
+}
+
+// SetFocusManager installs a focus manager which moves the focus between the
+// primitives of the application's primitive tree with the keyboard (see
+// FocusManager). Provide nil to remove it again.
+func (a *Application) SetFocusManager(manager *FocusManager) *Application {
+	a.Lock()
+	defer a.Unlock()
+
+	if manager != nil {
+		manager.app = a
+	}
+	a.focusManager = manager
+	return a
+}
+
+// SetTheme applies the given theme to all primitives of the application's
+// primitive tree. Subtrees whose root primitive has its own theme (see
+// Box.SetTheme()) receive that theme instead. The theme is applied again to
+// new root primitives set with SetRoot(). Primitives added to the tree in
+// other ways can be themed with their ApplyTheme() function. The package's
+// Styles, which new primitives are created with, are not changed.
+//
+// Colors and attributes set on individual primitives are overwritten. The theme
+// must not be nil, a nil theme is ignored. While the application is running,
+// call this function from QueueUpdateDraw() to redraw the screen with the new
+// theme:
+//
+//   app.QueueUpdateDraw(func() {
+//     app.SetTheme(theme)
+//   })
+func (a *Application) SetTheme(theme *Theme) *Application {
+	if theme == nil {
+		return a
+	}
+	a.Lock()
+	a.theme = theme
+	root, keymap := a.root, a.keymap
+	a.Unlock()
+
+	applyTheme(root, theme)
+	if keymap != nil {
+		keymap.helpBox.ApplyTheme(theme)
+	}
+	return a
+}
+
+// GetTheme returns the theme set with SetTheme() or nil if none was set.
+func (a *Application) GetTheme() *Theme {
+	a.RLock()
+	defer a.RUnlock()
+
+	return a.theme
+}
+
+// SetScreen sets the screen on which the application is run. For normal use,
+// this function is not needed as Run() creates a screen for the terminal. It
+// can be used to run the application on a different screen, e.g. on a
+// tcell.SimulationScreen in tests (see the tviewtest package).
+//
+// The screen is initialized by Run(). This function must therefore be called
+// before Run().
+func (a *Application) SetScreen(screen tcell.Screen) *Application {
+	a.Lock()
+	defer a.Unlock()
+
+	a.screen = screen
+	return a
+}
+
 func (a *Application) Screen() tcell.Screen {
 	// a.RLock()
 	// defer a.RUnlock()
@@ -63,20 +246,34 @@ func (a *Application) Screen() tcell.Screen {
 
 // Run starts the application and thus the event loop. This function returns
 // when Stop() was called.
+//
+// The event loop waits for screen events as well as for functions posted with
+// QueueUpdate() or QueueUpdateDraw(). All event handlers and queued functions
+// are executed one after another on the goroutine which called Run().
 func (a *Application) Run() error {
 	var err error
+	a.Lock()
+	done := make(chan struct{})
+	a.done = done
+	a.Unlock()
+	defer close(done)
 	// a.Lock()
 
-	// Make a screen.
-	a.screen, err = tcell.NewScreen()
-	if err != nil {
-		// a.Unlock()
-		return err
+	// Make a screen if none was provided.
+	if a.screen == nil {
+		a.screen, err = tcell.NewScreen()
+		if err != nil {
+			// a.Unlock()
+			return err
+		}
 	}
 	if err = a.screen.Init(); err != nil {
 		// a.Unlock()
 		return err
 	}
+	if a.enableMouse {
+		a.screen.EnableMouse()
+	}
 	// a.Unlock()
 
 	// We catch panics to clean up because they mess up the terminal.
@@ -94,18 +291,41 @@ func (a *Application) Run() error {
 	a.Draw()
 	a.SetFocus(a.root)
 
+	// Poll screen events in the background and hand them to the event loop.
+	go func() {
+		for {
+			a.RLock()
+			screen := a.screen
+			a.RUnlock()
+			if screen == nil {
+				a.postEvent(nil, done)
+				return
+			}
+
+			// Wait for next event. A nil event means the screen was finalized.
+			event := screen.PollEvent()
+			if !a.postEvent(event, done) || event == nil {
+				return
+			}
+		}
+	}()
+
 	// Start event loop.
 	for {
-
-		a.RLock()
-		screen := a.screen
-		a.RUnlock()
-		if screen == nil {
-			break
+		// Pending events take precedence over queued updates. An update queued
+		// after an event will therefore see the effects of that event.
+		var event tcell.Event
+		select {
+		case event = <-a.events:
+		default:
+			select {
+			case event = <-a.events:
+			case update := <-a.updates:
+				update()
+				continue
+			}
 		}
 
-		// Wait for next event.
-		event := screen.PollEvent()
 		if event == nil {
 			a.Stop()
 			break // The screen was finalized.
@@ -118,18 +338,31 @@ func (a *Application) Run() error {
 		if ic != nil {
 			event = ic(event)
 			if event == nil {
-				break // Don't forward event.
+				continue // Don't forward event.
 			}
 		}
 
-		// switch evt := event.(type) {
-		switch event.(type) {
+		switch evt := event.(type) {
 		case *tcell.EventKey:
 
 			// a.RLock()
 			p := a.focus
+			keymap := a.keymap
+			fm := a.focusManager
 			// a.RUnlock()
 
+			// Key bindings come first.
+			if keymap != nil && keymap.HandleKey(evt) {
+				a.Draw()
+				continue
+			}
+
+			// The focus manager may move the focus instead.
+			if fm != nil && fm.HandleKey(evt) {
+				a.Draw()
+				continue
+			}
+
 			// Pass other key events to the currently focused primitive.
 			if p != nil {
 				if handler := p.InputHandler(); handler != nil {
@@ -140,6 +373,11 @@ func (a *Application) Run() error {
 				}
 			}
 
+		case *tcell.EventMouse:
+			if a.fireMouseActions(evt) {
+				a.Draw()
+			}
+
 		case *tcell.EventResize:
 			// a.Lock()
 			screen := a.screen
@@ -156,6 +394,166 @@ func (a *Application) Run() error {
 	return nil
 }
 
+// QueueEvent posts an event to the application's event loop as if it had been
+// received from the screen. Events are processed in the order in which they
+// are queued, before any pending updates (see QueueUpdate()).
+//
+// A nil event stops the event loop. Like QueueUpdate(), this function must not
+// be called from the event loop itself. The event is discarded if Run() has
+// already returned.
+func (a *Application) QueueEvent(event tcell.Event) *Application {
+	a.postEvent(event, a.doneChannel())
+	return a
+}
+
+// postEvent hands an event to the event loop, waiting while the queue is full.
+// It returns false, discarding the event, if the given channel was closed
+// because the event loop ended.
+func (a *Application) postEvent(event tcell.Event, done <-chan struct{}) bool {
+	select {
+	case a.events <- event:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// doneChannel returns the channel which is closed when Run() returns. It is nil
+// before Run() was called.
+func (a *Application) doneChannel() <-chan struct{} {
+	a.RLock()
+	defer a.RUnlock()
+	return a.done
+}
+
+// fireMouseActions derives mouse actions from the given tcell mouse event and
+// forwards them to the primitive capturing the mouse or, if there is none, to
+// the root primitive. Returns true if any of the actions were consumed.
+func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed bool) {
+	x, y := event.Position()
+	buttons := event.Buttons()
+
+	fire := func(action MouseAction) {
+		target := a.mouseCapture
+		if target == nil {
+			target = a.root
+		}
+		if target == nil {
+			return
+		}
+		handler := target.MouseHandler()
+		if handler == nil {
+			return
+		}
+		actionConsumed, capture := handler(action, event, func(p Primitive) {
+			a.SetFocus(p)
+		})
+		a.mouseCapture = capture
+		if actionConsumed {
+			consumed = true
+		}
+	}
+
+	// Movement. A press at a new position is not a drag.
+	if x != a.lastMouseX || y != a.lastMouseY {
+		if buttons&a.lastMouseButtons&tcell.Button1 != 0 {
+			fire(MouseLeftDrag)
+		} else {
+			fire(MouseMove)
+		}
+		a.lastMouseX, a.lastMouseY = x, y
+	}
+
+	// Button presses and releases. Button1 is the left button, Button2 the
+	// right button, and Button3 the middle button.
+	for _, b := range []struct {
+		button                       tcell.ButtonMask
+		down, up, click, doubleClick MouseAction
+	}{
+		{tcell.Button1, MouseLeftDown, MouseLeftUp, MouseLeftClick, MouseLeftDoubleClick},
+		{tcell.Button3, MouseMiddleDown, MouseMiddleUp, MouseMiddleClick, MouseMiddleDoubleClick},
+		{tcell.Button2, MouseRightDown, MouseRightUp, MouseRightClick, MouseRightDoubleClick},
+	} {
+		if buttons&b.button != 0 && a.lastMouseButtons&b.button == 0 {
+			a.mouseDownX, a.mouseDownY = x, y
+			fire(b.down)
+		} else if buttons&b.button == 0 && a.lastMouseButtons&b.button != 0 {
+			fire(b.up)
+
+			// Only a release at the position of the press is a click.
+			if x == a.mouseDownX && y == a.mouseDownY {
+				if a.lastMouseClickButton == b.button && x == a.lastMouseClickX && y == a.lastMouseClickY &&
+					time.Since(a.lastMouseClick) <= DoubleClickInterval {
+					fire(b.doubleClick)
+					a.lastMouseClick = time.Time{}
+				} else {
+					fire(b.click)
+					a.lastMouseClick = time.Now()
+					a.lastMouseClickButton = b.button
+					a.lastMouseClickX, a.lastMouseClickY = x, y
+				}
+			}
+		}
+	}
+
+	// Scroll wheel. These are reported with every event.
+	for _, w := range []struct {
+		button tcell.ButtonMask
+		action MouseAction
+	}{
+		{tcell.WheelUp, MouseScrollUp},
+		{tcell.WheelDown, MouseScrollDown},
+		{tcell.WheelLeft, MouseScrollLeft},
+		{tcell.WheelRight, MouseScrollRight},
+	} {
+		if buttons&w.button != 0 {
+			fire(w.action)
+		}
+	}
+
+	a.lastMouseButtons = buttons
+	return
+}
+
+// QueueUpdate posts a function to the application's event loop, where it is
+// executed after all pending events and updates have been processed. This is
+// the only safe way to modify primitives from a goroutine other than the one
+// running Run(), e.g.:
+//
+//   go func() {
+//     for line := range lines {
+//       app.QueueUpdate(func() {
+//         table.SetCellSimple(row, 0, line)
+//       })
+//     }
+//   }()
+//
+// This function blocks while the queue is full. Functions queued before Run()
+// is called are executed once the event loop starts. Functions queued after
+// Run() returned are discarded.
+//
+// It must not be called from the event loop itself (e.g. from an input handler
+// or another queued function): the event loop cannot empty the queue while it
+// waits for this function, which therefore blocks forever if the queue is
+// full. Modify the primitives directly there instead.
+func (a *Application) QueueUpdate(f func()) *Application {
+	select {
+	case a.updates <- f:
+	case <-a.doneChannel():
+	}
+	return a
+}
+
+// QueueUpdateDraw works like QueueUpdate() except that it redraws the screen
+// after the function has been executed.
+func (a *Application) QueueUpdateDraw(f func()) *Application {
+	a.QueueUpdate(func() {
+		f()
+		a.Draw()
+	})
+	return a
+}
+
 // Stop stops the application, causing Run() to return.
 func (a *Application) Stop() error {
 	a.Lock()
@@ -188,12 +586,42 @@ func (a *Application) Draw() *Application {
 	// Draw all primitives.
 	a.root.Draw(a.screen)
 
+	// Draw the key bindings help on top.
+	if a.keymap != nil {
+		a.keymap.drawHelp(a.screen, a.root, a.focus, a.theme)
+	}
+
 	// Sync screen.
 	a.screen.Show()
 
 	return a
 }
 
+// FindById returns the primitive with the given Id() in the application's
+// primitive tree or nil if there is no such primitive. See also FindById().
+func (a *Application) FindById(id string) Primitive {
+	return FindById(a.GetRoot(), id)
+}
+
+// FindByName returns the first primitive with the given Name() in the
+// application's primitive tree or nil if there is no such primitive. See also
+// FindByName().
+func (a *Application) FindByName(name string) Primitive {
+	return FindByName(a.GetRoot(), name)
+}
+
+// FindByType returns all primitives in the application's primitive tree which
+// have the same type as the given example. See also FindByType().
+func (a *Application) FindByType(example Primitive) []Primitive {
+	return FindByType(a.GetRoot(), example)
+}
+
+// Parent returns the container which directly contains the given primitive in
+// the application's primitive tree or nil if there is none. See also Parent().
+func (a *Application) Parent(p Primitive) Primitive {
+	return Parent(a.GetRoot(), p)
+}
+
 // SetRoot sets the root primitive for this application. This function must be
 // called or nothing will be displayed when the application starts.
 //
@@ -205,6 +633,10 @@ func (a *Application) SetRoot(root Primitive, autoSize bool) *Application {
 	a.rootAutoSize = autoSize
 	// a.Unlock()
 
+	if theme := a.GetTheme(); theme != nil {
+		applyTheme(root, theme)
+	}
+
 	// a.RLock()
 	if a.screen != nil {
 		a.screen.Clear()
@@ -230,17 +662,14 @@ func (a *Application) ResizeToFullScreen(p Primitive) *Application {
 // to that primitive. Callers must ensure that the primitive will handle key
 // events.
 //
-// Blur() will be called on the previously focused primitive. Focus() will be
-// called on the new primitive.
+// Focus() will be called on the new primitive. Blur() will then be called on
+// the previously focused primitive, unless the new primitive handed the focus
+// back to it, so that it can tell whether the focus is still within it.
 func (a *Application) SetFocus(p Primitive) *Application {
 	// a.RLock()
 	f := a.focus
 	// a.RUnlock()
 
-	if f != nil {
-		f.Blur()
-	}
-
 	// a.Lock()
 	a.focus = p
 	// a.Unlock()
@@ -255,6 +684,10 @@ func (a *Application) SetFocus(p Primitive) *Application {
 		a.SetFocus(p)
 	})
 
+	if f != nil && f != a.focus {
+		f.Blur()
+	}
+
 	return a
 }
 
@@ -266,3 +699,11 @@ func (a *Application) GetFocus() Primitive {
 
 	return a.focus
 }
+
+// GetRoot returns the root primitive of this application.
+func (a *Application) GetRoot() Primitive {
+	a.RLock()
+	defer a.RUnlock()
+
+	return a.root
+}
@@ -0,0 +1,63 @@
+package tview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+// runApplication starts the given application on a simulated screen and
+// returns a channel which receives the return value of Run().
+func runApplication(app *Application) chan error {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	app.SetScreen(screen).SetRoot(NewBox(), true)
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run()
+	}()
+	return done
+}
+
+func TestQueueUpdate(t *testing.T) {
+	app := NewApplication()
+	done := runApplication(app)
+
+	executed := make(chan bool)
+	app.QueueUpdate(func() {
+		executed <- true
+	})
+	select {
+	case <-executed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued function was not executed")
+	}
+
+	app.Stop()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueueUpdateAfterRun(t *testing.T) {
+	app := NewApplication()
+	done := runApplication(app)
+	app.QueueUpdate(func() {})
+	app.Stop()
+	<-done
+
+	// More functions than the queue can hold must not block.
+	returned := make(chan bool)
+	go func() {
+		for index := 0; index <= queueSize; index++ {
+			app.QueueUpdate(func() {})
+		}
+		app.QueueEvent(nil)
+		returned <- true
+	}()
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("QueueUpdate() blocked after Run() returned")
+	}
+}
@@ -0,0 +1,153 @@
+package tview_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// fruits suggests the fruits starting with the entered text.
+func fruits(ctx context.Context, text string, suggest func(entries []string)) {
+	var entries []string
+	if text != "" {
+		for _, fruit := range []string{"apple", "apricot", "banana"} {
+			if strings.HasPrefix(fruit, text) {
+				entries = append(entries, fruit)
+			}
+		}
+	}
+	suggest(entries)
+}
+
+// autocompleteForm returns a form with an input field which suggests fruits,
+// followed by a second input field, and a harness running it.
+func autocompleteForm() (*tviewtest.Harness, *tview.InputField) {
+	input := tview.NewInputField().
+		SetLabel("Fruit").
+		SetFieldWidth(10).
+		SetAutocompleteFunc(fruits)
+	form := tview.NewForm().
+		AddFormItem(input).
+		AddInputField("Other", "", 10, nil, nil)
+	return tviewtest.New(form, 30, 8), input
+}
+
+func TestAutocompleteSuggestions(t *testing.T) {
+	h, _ := autocompleteForm()
+	defer h.Stop()
+
+	h.Type("a")
+	text := h.Text()
+	if !strings.Contains(text, "apple") || !strings.Contains(text, "apricot") || strings.Contains(text, "banana") {
+		t.Errorf("suggestions for %q not shown:\n%s", "a", text)
+	}
+
+	h.Type("pr")
+	text = h.Text()
+	if strings.Contains(text, "apple") || !strings.Contains(text, "apricot") {
+		t.Errorf("suggestions for %q not shown:\n%s", "apr", text)
+	}
+
+	// No suggestions close the list.
+	h.Type("x")
+	if text := h.Text(); strings.Contains(text, "apricot") {
+		t.Errorf("suggestions still shown for %q:\n%s", "aprx", text)
+	}
+}
+
+func TestAutocompleteStaleSuggestions(t *testing.T) {
+	var (
+		contexts []context.Context
+		suggests []func(entries []string)
+	)
+	input := tview.NewInputField().SetAutocompleteFunc(func(ctx context.Context, text string, suggest func(entries []string)) {
+		contexts = append(contexts, ctx)
+		suggests = append(suggests, suggest)
+	})
+	h := tviewtest.New(input, 20, 5)
+	defer h.Stop()
+
+	h.Type("ab")
+	h.Do(func() {
+		if len(suggests) != 2 || contexts[0].Err() == nil || contexts[1].Err() != nil {
+			t.Fatalf("autocomplete function called %d times, first context not canceled", len(suggests))
+		}
+		suggests[0]([]string{"a1"})
+		suggests[1]([]string{"ab1"})
+	})
+	h.Sync()
+	if text := h.Text(); strings.Contains(text, "a1\n") || !strings.Contains(text, "ab1") {
+		t.Errorf("suggestions for the old text shown:\n%s", text)
+	}
+}
+
+func TestAutocompleteAccept(t *testing.T) {
+	for _, key := range []tcell.Key{tcell.KeyTab, tcell.KeyEnter} {
+		h, input := autocompleteForm()
+
+		h.Type("a")
+		h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+		h.KeyPress(key, 0, tcell.ModNone)
+
+		var text string
+		var focused bool
+		h.Do(func() {
+			text = input.GetText()
+			focused = input.HasFocus()
+		})
+		if text != "apricot" {
+			t.Errorf("key %d: text is %q, want the selected suggestion", key, text)
+		}
+		if !focused {
+			t.Errorf("key %d: input field lost the focus", key)
+		}
+		if screen := h.Text(); strings.Contains(screen, "apple") {
+			t.Errorf("key %d: suggestions still shown:\n%s", key, screen)
+		}
+
+		// Without suggestions, the key moves on to the next form item.
+		h.KeyPress(key, 0, tcell.ModNone)
+		h.Do(func() {
+			focused = input.HasFocus()
+		})
+		if focused {
+			t.Errorf("key %d: focus did not move on without suggestions", key)
+		}
+		h.Stop()
+	}
+}
+
+func TestAutocompleteEscape(t *testing.T) {
+	h, input := autocompleteForm()
+	defer h.Stop()
+
+	h.Type("ap")
+	h.KeyPress(tcell.KeyEscape, 0, tcell.ModNone)
+	var text string
+	h.Do(func() {
+		text = input.GetText()
+	})
+	if text != "ap" {
+		t.Errorf("text is %q after dismissing the suggestions", text)
+	}
+	if screen := h.Text(); strings.Contains(screen, "apple") {
+		t.Errorf("suggestions still shown:\n%s", screen)
+	}
+	var focused bool
+	h.Do(func() {
+		focused = input.HasFocus()
+	})
+	if !focused {
+		t.Error("input field lost the focus when dismissing the suggestions")
+	}
+
+	// The next change suggests entries again.
+	h.Type("r")
+	if screen := h.Text(); !strings.Contains(screen, "apricot") {
+		t.Errorf("no suggestions after typing:\n%s", screen)
+	}
+}
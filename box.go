@@ -24,8 +24,15 @@ type Box struct {
 	// A name for human-readable usage, when needed
 	name string
 
-	// props is a generic properties store
-	props map[string]interface{}
+	// props is a generic properties store for unbound props, propSpecs is the
+	// declared props schema (see props.go).
+	props             map[string]interface{}
+	propSpecs         map[string]PropSpec
+	propSubscriptions []*propSubscription
+
+	// The number of prop change notifications so far, used to find out if a
+	// setter notified subscribers itself.
+	propNotifications int
 
 	// The position of the rect.
 	x, y, width, height int
@@ -43,15 +50,25 @@ type Box struct {
 	// The color of the border.
 	borderColor tcell.Color
 
+	// The text attributes of the border.
+	borderAttributes tcell.AttrMask
+
 	// The title. Only visible if there is a border, too.
 	title string
 
 	// The color of the title.
 	titleColor tcell.Color
 
+	// The text attributes of the title.
+	titleAttributes tcell.AttrMask
+
 	// The alignment of the title.
 	titleAlign int
 
+	// The theme which overrides the application's theme for this box and its
+	// children, if any.
+	theme *Theme
+
 	// Provides a way to find out if this box has focus. We always go through
 	// this interface because it may be overridden by implementing classes.
 	focus Focusable
@@ -59,6 +76,10 @@ type Box struct {
 	// Whether or not this box has focus.
 	hasFocus bool
 
+	// An optional function which is called when this box loses focus. It lets
+	// containers such as Form find out that one of their items was left.
+	blurred func()
+
 	// Whether or not this box is mounted.
 	isMounted bool
 
@@ -66,18 +87,27 @@ type Box struct {
 	// event to be forwarded to the primitive's default input handler (nil if
 	// nothing should be forwarded).
 	inputCapture func(event tcell.Event) tcell.Event
+
+	// An optional capture function which receives a mouse action and event and
+	// returns the action and event to be forwarded to the primitive's default
+	// mouse handler (nil if nothing should be forwarded).
+	mouseCapture func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse)
 }
 
 // NewBox returns a Box without a border.
 func NewBox() *Box {
 	b := &Box{
-		id:              uuid.New().String(),
-		width:           15,
-		height:          10,
-		backgroundColor: Styles.PrimitiveBackgroundColor,
-		borderColor:     Styles.BorderColor,
-		titleColor:      Styles.TitleColor,
-		titleAlign:      AlignCenter,
+		id:               uuid.New().String(),
+		props:            make(map[string]interface{}),
+		propSpecs:        make(map[string]PropSpec),
+		width:            15,
+		height:           10,
+		backgroundColor:  Styles.PrimitiveBackgroundColor,
+		borderColor:      Styles.BorderColor,
+		borderAttributes: Styles.BorderAttributes,
+		titleColor:       Styles.TitleColor,
+		titleAttributes:  Styles.TitleAttributes,
+		titleAlign:       AlignCenter,
 	}
 	b.focus = b
 	return b
@@ -132,6 +162,13 @@ func (b *Box) GetInnerRect() (int, int, int, int) {
 		height - b.paddingTop - b.paddingBottom
 }
 
+// InRect returns true if the given coordinate is within the bounds of the box's
+// rectangle.
+func (b *Box) InRect(x, y int) bool {
+	rectX, rectY, width, height := b.GetRect()
+	return x >= rectX && x < rectX+width && y >= rectY && y < rectY+height
+}
+
 // SetRect sets a new position of the primitive.
 func (b *Box) SetRect(x, y, width, height int) {
 	//b.Lock()
@@ -183,6 +220,54 @@ func (b *Box) SetInputCapture(capture func(event tcell.Event) tcell.Event) *Box
 	return b
 }
 
+// wrapMouseHandler wraps a mouse handler (see MouseHandler()) with the
+// functionality to capture mouse events (see SetMouseCapture()) before passing
+// them on to the provided (default) mouse handler.
+func (b *Box) wrapMouseHandler(mouseHandler func(MouseAction, *tcell.EventMouse, func(p Primitive)) (bool, Primitive)) func(MouseAction, *tcell.EventMouse, func(p Primitive)) (bool, Primitive) {
+	return func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// b.RLock()
+		mc := b.mouseCapture
+		// b.RUnlock()
+		if mc != nil {
+			action, event = mc(action, event)
+		}
+		if event != nil && mouseHandler != nil {
+			consumed, capture = mouseHandler(action, event, setFocus)
+		}
+		return
+	}
+}
+func (b *Box) WrapMouseHandler(mouseHandler func(MouseAction, *tcell.EventMouse, func(p Primitive)) (bool, Primitive)) func(MouseAction, *tcell.EventMouse, func(p Primitive)) (bool, Primitive) {
+	return b.wrapMouseHandler(mouseHandler)
+}
+
+// MouseHandler returns a handler which sets the focus to the box when it is
+// clicked on. Note that this is the Box itself, not any primitive which
+// subclasses from Box. Such primitives should provide their own handler.
+func (b *Box) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return b.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if action == MouseLeftDown && b.InRect(event.Position()) {
+			setFocus(b)
+			consumed = true
+		}
+		return
+	})
+}
+
+// SetMouseCapture installs a function which captures mouse events before they
+// are forwarded to the primitive's default mouse handler. This function can
+// then choose to forward that event (or a different one) by returning it. If
+// a nil event is returned, the default handler will not be called.
+//
+// Providing a nil handler will remove a previously existing handler.
+func (b *Box) SetMouseCapture(capture func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse)) *Box {
+	//b.Lock()
+	//defer b.Unlock()
+
+	b.mouseCapture = capture
+	return b
+}
+
 // SetBackgroundColor sets the box's background color.
 func (b *Box) SetBackgroundColor(color tcell.Color) *Box {
 	//b.Lock()
@@ -211,6 +296,16 @@ func (b *Box) SetBorderColor(color tcell.Color) *Box {
 	return b
 }
 
+// SetBorderAttributes sets the text attributes of the box's border, e.g.
+// tcell.AttrBold.
+func (b *Box) SetBorderAttributes(attributes tcell.AttrMask) *Box {
+	//b.Lock()
+	//defer b.Unlock()
+
+	b.borderAttributes = attributes
+	return b
+}
+
 // SetTitle sets the box's title.
 func (b *Box) SetTitle(title string) *Box {
 	//b.Lock()
@@ -229,6 +324,16 @@ func (b *Box) SetTitleColor(color tcell.Color) *Box {
 	return b
 }
 
+// SetTitleAttributes sets the text attributes of the box's title, e.g.
+// tcell.AttrBold | tcell.AttrUnderline.
+func (b *Box) SetTitleAttributes(attributes tcell.AttrMask) *Box {
+	//b.Lock()
+	//defer b.Unlock()
+
+	b.titleAttributes = attributes
+	return b
+}
+
 // SetTitleAlign sets the alignment of the title, one of AlignLeft, AlignCenter,
 // or AlignRight.
 func (b *Box) SetTitleAlign(align int) *Box {
@@ -239,6 +344,37 @@ func (b *Box) SetTitleAlign(align int) *Box {
 	return b
 }
 
+// SetTheme sets a theme which overrides the application's theme for this box
+// and its children. Provide nil to remove the override. The theme is applied
+// by Application.SetTheme(). Call ApplyTheme() to apply it right away.
+func (b *Box) SetTheme(theme *Theme) *Box {
+	//b.Lock()
+	//defer b.Unlock()
+
+	b.theme = theme
+	return b
+}
+
+// GetTheme returns the theme set with SetTheme() or nil if there is none.
+func (b *Box) GetTheme() *Theme {
+	//b.RLock()
+	//defer b.RUnlock()
+
+	return b.theme
+}
+
+// ApplyTheme sets the box's colors and attributes to those of the given theme.
+func (b *Box) ApplyTheme(theme *Theme) {
+	//b.Lock()
+	//defer b.Unlock()
+
+	b.backgroundColor = theme.PrimitiveBackgroundColor
+	b.borderColor = theme.BorderColor
+	b.titleColor = theme.TitleColor
+	b.borderAttributes = theme.BorderAttributes
+	b.titleAttributes = theme.TitleAttributes
+}
+
 // Draw draws this primitive onto the screen.
 func (b *Box) Draw(screen tcell.Screen) {
 	//b.RLock()
@@ -261,7 +397,7 @@ func (b *Box) Draw(screen tcell.Screen) {
 
 	// Draw border.
 	if b.border && b.width >= 2 && b.height >= 2 {
-		border := background.Foreground(b.borderColor)
+		border := addAttributes(background.Foreground(b.borderColor), b.borderAttributes)
 		var vertical, horizontal, topLeft, topRight, bottomLeft, bottomRight rune
 		if b.focus.HasFocus() {
 			vertical = GraphicsDbVertBar
@@ -293,7 +429,7 @@ func (b *Box) Draw(screen tcell.Screen) {
 
 		// Draw title.
 		if b.title != "" && b.width >= 4 {
-			_, printed := Print(screen, b.title, b.x+1, b.y, b.width-2, b.titleAlign, b.titleColor)
+			_, printed := printWithAttributes(screen, b.title, b.x+1, b.y, b.width-2, b.titleAlign, b.titleColor, b.titleAttributes)
 			if StringWidth(b.title)-printed > 0 && printed > 0 {
 				_, _, style, _ := screen.GetContent(b.x+b.width-2, b.y)
 				fg, _, _ := style.Decompose()
@@ -317,6 +453,14 @@ func (b *Box) Blur() {
 	// defer b.Unlock()
 
 	b.hasFocus = false
+	if b.blurred != nil {
+		b.blurred()
+	}
+}
+
+// setBlurredFunc sets a function which is called when this box loses focus.
+func (b *Box) setBlurredFunc(handler func()) {
+	b.blurred = handler
 }
 
 // HasFocus returns whether or not this primitive has focus.
@@ -344,7 +488,8 @@ func (b *Box) Mount(context map[string]interface{}) error {
 	return nil
 }
 
-// Mount is called when this primitive is mounted (by the router).
+// Refresh is called when this primitive is already mounted and is switched to
+// again with a new context (by the router).
 func (b *Box) Refresh(context map[string]interface{}) error {
 	return nil
 }
@@ -368,36 +513,3 @@ func (b *Box) IsMounted() bool {
 
 // Render is a placeholder here
 func (b *Box) Render() error { return nil }
-
-func (b *Box) GetProp(prop string) (interface{}, bool) {
-	//b.RLock()
-	//defer b.RUnlock()
-
-	value, ok := b.props[prop]
-	return value, ok
-}
-
-func (b *Box) GetProps() map[string]interface{} {
-	//b.RLock()
-	//defer b.RUnlock()
-
-	return b.props
-}
-
-// SetProp is a generic function for setting properties
-func (b *Box) SetProp(prop string, value interface{}) error {
-	//b.Lock()
-	//defer b.Unlock()
-
-	b.props[prop] = value
-	return nil
-}
-
-// SetProps is a generic function for setting properties
-func (b *Box) SetProps(newProps map[string]interface{}) error {
-	//b.Lock()
-	//defer b.Unlock()
-
-	b.props = newProps
-	return nil
-}
@@ -1,6 +1,8 @@
 package tview
 
 import (
+	"reflect"
+
 	"github.com/gdamore/tcell"
 )
 
@@ -39,13 +41,21 @@ type Button struct {
 func NewButton(label string) *Button {
 	box := NewBox().SetBackgroundColor(Styles.ContrastBackgroundColor)
 	box.SetRect(0, 0, StringWidth(label)+4, 1)
-	return &Button{
+	b := &Button{
 		Box:                      box,
 		label:                    label,
 		labelColor:               Styles.PrimaryTextColor,
 		labelColorActivated:      Styles.InverseTextColor,
 		backgroundColorActivated: Styles.PrimaryTextColor,
 	}
+	b.DeclareProps(PropSpec{
+		Name:    "label",
+		Type:    reflect.TypeOf(""),
+		Default: "",
+		Get:     func() interface{} { return b.label },
+		Set:     func(value interface{}) { b.SetLabel(value.(string)) },
+	})
+	return b
 }
 
 func (b *Button) Name() string {
@@ -56,9 +66,14 @@ func (b *Button) SetName(name string) {
 	b.name = name
 }
 
-// SetLabel sets the button text.
+// SetLabel sets the button text. Subscribers of the "label" prop are notified
+// if it changed.
 func (b *Button) SetLabel(label string) *Button {
+	if label == b.label {
+		return b
+	}
 	b.label = label
+	b.notifyProp("label", label)
 	return b
 }
 
@@ -87,6 +102,16 @@ func (b *Button) SetBackgroundColorActivated(color tcell.Color) *Button {
 	return b
 }
 
+// ApplyTheme sets the button's colors and attributes to those of the given
+// theme.
+func (b *Button) ApplyTheme(theme *Theme) {
+	b.Box.ApplyTheme(theme)
+	b.backgroundColor = theme.ContrastBackgroundColor
+	b.labelColor = theme.PrimaryTextColor
+	b.labelColorActivated = theme.InverseTextColor
+	b.backgroundColorActivated = theme.PrimaryTextColor
+}
+
 func (b *Button) OnSubmit() {
 	if b.selected != nil {
 		b.selected()
@@ -121,6 +146,12 @@ func (b *Button) SetBlurFunction(handler func(key tcell.Key)) {
 	b.blur = handler
 }
 
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the button with Tab or Backtab.
+func (b *Button) hasDoneFunc() bool {
+	return b.blur != nil
+}
+
 // Draw draws this primitive onto the screen.
 func (b *Button) Draw(screen tcell.Screen) {
 	// Draw the box.
@@ -168,3 +199,26 @@ func (b *Button) InputHandler() func(tcell.Event, func(Primitive)) {
 		}
 	})
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (b *Button) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return b.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !b.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(b)
+			consumed = true
+		case MouseLeftClick:
+			if b.selected != nil {
+				b.selected()
+			}
+			consumed = true
+		}
+
+		return
+	})
+}
@@ -1,6 +1,8 @@
 package tview
 
 import (
+	"fmt"
+
 	"github.com/gdamore/tcell"
 )
 
@@ -20,6 +22,9 @@ type Checkbox struct {
 	// The label color.
 	labelColor tcell.Color
 
+	// The text attributes of the label.
+	labelAttributes tcell.AttrMask
+
 	// The background color of the input area.
 	fieldBackgroundColor tcell.Color
 
@@ -41,6 +46,7 @@ func NewCheckbox() *Checkbox {
 	return &Checkbox{
 		Box:                  NewBox(),
 		labelColor:           Styles.SecondaryTextColor,
+		labelAttributes:      Styles.LabelAttributes,
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 	}
@@ -52,6 +58,16 @@ func (c *Checkbox) GetValues() map[string]interface{} {
 	}
 }
 
+// SetValue sets the state of the checkbox. The value must be a bool.
+func (c *Checkbox) SetValue(value interface{}) error {
+	checked, ok := value.(bool)
+	if !ok {
+		return fmt.Errorf("expected a bool, got %T", value)
+	}
+	c.SetChecked(checked)
+	return nil
+}
+
 // SetChecked sets the state of the checkbox.
 func (c *Checkbox) SetChecked(checked bool) *Checkbox {
 	c.checked = checked
@@ -80,6 +96,13 @@ func (c *Checkbox) SetLabelColor(color tcell.Color) *Checkbox {
 	return c
 }
 
+// SetLabelAttributes sets the text attributes of the label, e.g.
+// tcell.AttrBold.
+func (c *Checkbox) SetLabelAttributes(attributes tcell.AttrMask) *Checkbox {
+	c.labelAttributes = attributes
+	return c
+}
+
 // SetFieldBackgroundColor sets the background color of the input area.
 func (c *Checkbox) SetFieldBackgroundColor(color tcell.Color) *Checkbox {
 	c.fieldBackgroundColor = color
@@ -92,6 +115,16 @@ func (c *Checkbox) SetFieldTextColor(color tcell.Color) *Checkbox {
 	return c
 }
 
+// ApplyTheme sets the colors and attributes of the checkbox to those of the
+// given theme.
+func (c *Checkbox) ApplyTheme(theme *Theme) {
+	c.Box.ApplyTheme(theme)
+	c.labelColor = theme.SecondaryTextColor
+	c.labelAttributes = theme.LabelAttributes
+	c.fieldBackgroundColor = theme.ContrastBackgroundColor
+	c.fieldTextColor = theme.PrimaryTextColor
+}
+
 // SetFormAttributes sets attributes shared by all form items.
 func (c *Checkbox) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
 	c.label = label
@@ -127,6 +160,12 @@ func (c *Checkbox) SetDoneFunc(handler func(key tcell.Key)) *Checkbox {
 	return c
 }
 
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the checkbox with Tab or Backtab.
+func (c *Checkbox) hasDoneFunc() bool {
+	return c.done != nil
+}
+
 // SetFinishedFunc calls SetDoneFunc().
 func (c *Checkbox) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return c.SetDoneFunc(handler)
@@ -152,7 +191,7 @@ func (c *Checkbox) Draw(screen tcell.Screen) {
 	defer c.RUnlock()
 
 	// Draw label.
-	_, drawnWidth := Print(screen, c.label, x, y, rightLimit-x, AlignLeft, c.labelColor)
+	_, drawnWidth := printWithAttributes(screen, c.label, x, y, rightLimit-x, AlignLeft, c.labelColor, c.labelAttributes)
 	x += drawnWidth
 
 	// Draw checkbox.
@@ -190,3 +229,27 @@ func (c *Checkbox) InputHandler() func(tcell.Event, func(Primitive)) {
 		}
 	})
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *Checkbox) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !c.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(c)
+			consumed = true
+		case MouseLeftClick:
+			c.checked = !c.checked
+			if c.changed != nil {
+				c.changed(c.checked)
+			}
+			consumed = true
+		}
+
+		return
+	})
+}
@@ -0,0 +1,86 @@
+package tview
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Clipboard is implemented by clipboards to which primitives copy text, e.g.
+// the text selected in a TextView.
+type Clipboard interface {
+	// Copy puts the given text on the clipboard.
+	Copy(text string) error
+
+	// Paste returns the text on the clipboard.
+	Paste() (string, error)
+}
+
+// DefaultClipboard is the clipboard used by primitives for which no other
+// clipboard was set. It copies text to the terminal's clipboard using OSC 52
+// escape sequences.
+var DefaultClipboard Clipboard = NewOSC52Clipboard(nil)
+
+// OSC52Clipboard is a Clipboard which copies text to the clipboard of the
+// terminal emulator using OSC 52 escape sequences. This works across SSH
+// connections but requires a terminal which supports (and allows) OSC 52.
+// Terminals do not let applications read their clipboard this way so Paste()
+// always fails.
+type OSC52Clipboard struct {
+	// The writer the escape sequences are written to.
+	writer io.Writer
+}
+
+// NewOSC52Clipboard returns a new OSC 52 clipboard which writes its escape
+// sequences to the given writer. If the writer is nil, the escape sequences
+// are written to the standard output.
+func NewOSC52Clipboard(writer io.Writer) *OSC52Clipboard {
+	return &OSC52Clipboard{writer: writer}
+}
+
+// Copy puts the given text on the terminal's clipboard.
+func (c *OSC52Clipboard) Copy(text string) error {
+	writer := c.writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+	_, err := fmt.Fprintf(writer, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// Paste returns an error as the terminal's clipboard cannot be read.
+func (c *OSC52Clipboard) Paste() (string, error) {
+	return "", errors.New("the terminal's clipboard cannot be read")
+}
+
+// MemoryClipboard is a Clipboard which keeps the copied text in memory. It can
+// be used to exchange text within an application and in tests.
+type MemoryClipboard struct {
+	sync.Mutex
+
+	// The text on the clipboard.
+	text string
+}
+
+// NewMemoryClipboard returns a new, empty in-memory clipboard.
+func NewMemoryClipboard() *MemoryClipboard {
+	return &MemoryClipboard{}
+}
+
+// Copy puts the given text on the clipboard.
+func (c *MemoryClipboard) Copy(text string) error {
+	c.Lock()
+	defer c.Unlock()
+	c.text = text
+	return nil
+}
+
+// Paste returns the text on the clipboard.
+func (c *MemoryClipboard) Paste() (string, error) {
+	c.Lock()
+	defer c.Unlock()
+	return c.text, nil
+}
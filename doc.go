@@ -13,17 +13,20 @@ The package implements the following widgets:
     also be highlighted.
   - List: A navigable text list with optional keyboard shortcuts.
   - InputField: One-line input fields to enter text.
+  - TextArea: Multi-line input fields to edit text.
   - DropDown: Drop-down selection fields.
   - Checkbox: Selectable checkbox for boolean values.
   - Button: Buttons which get activated when the user selects them.
-  - Form: Forms composed of input fields, drop down selections, checkboxes, and
-    buttons.
+  - Form: Forms composed of input fields, text areas, drop down selections,
+    checkboxes, and buttons, with value collection, validation, and submission.
   - Modal: A centered window with a text message and one or more buttons.
   - Flex: A Flexbox based layout manager.
   - Pages: A page based layout manager.
+  - Router: Pages which are switched by path, with parameters and history.
 
 The package also provides Application which is used to poll the event queue and
-draw widgets on screen.
+draw widgets on screen. The loader subpackage builds trees of widgets from
+declarative view descriptions written in YAML or JSON.
 
 Hello World
 
@@ -77,6 +80,35 @@ applies to almost everything from box titles, list text, form item labels, to
 table cells. In a TextView, this functionality has to be switched on explicitly.
 See the TextView documentation for more information.
 
+Color tags may also change the background color and the text attributes. The
+full definition of a color tag is as follows:
+
+  [<foreground>:<background>:<attributes>]
+
+Each of the three fields can be left blank and trailing fields can be left
+out, in which case the field's current value is kept. A dash ("-") resets the
+field to its default value, e.g. the text color of the primitive or the
+background the text is printed on. Attributes are a combination of the
+following letters:
+
+  b: bold
+  d: dim
+  i: italic
+  l: blink
+  r: reverse
+  u: underline
+
+Attribute letters replace all attributes set before. Examples:
+
+  [yellow]Yellow text
+  [yellow:red]Yellow text on red background
+  [:red]Red background, text color unchanged
+  [yellow::u]Yellow text underlined
+  [::bl]Bold, blinking text
+  [::-]Colors unchanged, no attributes
+  [-]Default text color
+  [-:-:-]Reset everything to the defaults
+
 In the rare event that you want to display a string such as "[red]" or
 "[#00ff1a]" without applying its effect, you need to put an opening square
 bracket before the closing square bracket. Examples:
@@ -85,12 +117,43 @@ bracket before the closing square bracket. Examples:
   ["123"[]    will be output as ["123"]
   [#6aff00[[] will be output as [#6aff00[]
 
+The Escape() function does this for arbitrary text.
+
+ANSI escape sequences, e.g. in the output of command line tools, can be
+translated into color tags with TranslateANSI() or with an ANSIWriter() placed
+in front of a TextView. Close the writer when the output ends so that no text
+is held back.
+
 Styles
 
 When primitives are instantiated, they are initialized with colors taken from
 the global Styles variable. You may change this variable to adapt the look and
 feel of the primitives to your preferred style.
 
+Styles is a Theme which covers colors as well as text attributes such as bold
+or underline. Themes can be loaded from JSON files with LoadThemeFile() and
+switched at runtime with Application.SetTheme(), which applies the theme to all
+primitives of the application without changing Styles. Individual primitives
+and their children may keep a different theme which is set with
+Box.SetTheme().
+
+Concurrency
+
+Most primitives are not thread-safe. All of their functions, including event
+handlers, should be called from the goroutine which runs the application's
+event loop (see Application.Run()). If you need to change primitives from a
+different goroutine, e.g. when streaming log lines into a TextView, post the
+change to the event loop with Application.QueueUpdate() or, if the screen
+needs to be refreshed afterwards, Application.QueueUpdateDraw():
+
+  go func() {
+  	for line := range lines {
+  		app.QueueUpdateDraw(func() {
+  			fmt.Fprintln(textView, line)
+  		})
+  	}
+  }()
+
 Unicode Support
 
 This package supports unicode characters including wide characters.
@@ -103,9 +166,68 @@ therefore available for all widgets, too.
 All widgets also implement the Primitive interface. There is also the Focusable
 interface which is used to override functions in subclassing types.
 
+Containers such as Flex, Pages, Form, Frame, and Modal implement the Container
+interface. Walk() visits a tree of primitives, FindById(), FindByName(), and
+FindByType() look up primitives in it, and Parent() returns a primitive's
+container. The Application provides the same functions for its root.
+
 The tview package is based on https://github.com/gdamore/tcell. It uses types
 and constants from that package (e.g. colors and keyboard values).
 
-This package does not process mouse input (yet).
+Keyboard Focus
+
+By default, containers pass the focus on to one of their items and only Form
+moves it between its items with Tab. Install a FocusManager with
+Application.SetFocusManager() to move the focus through any layout with Tab
+and Backtab, and with Alt and the arrow keys based on the primitives' positions.
+Visible Modals trap the focus, other focus scopes can be added with
+FocusManager.PushScope().
+
+Key Bindings
+
+Application-wide shortcuts are registered with a Keymap which is installed with
+Application.SetKeymap(). Bindings may consist of several key combinations
+("chords") and may be scoped to a primitive, in which case they are only active
+while that primitive or one of its descendants has the focus:
+
+  keymap := tview.NewKeymap()
+  keymap.Bind("save", "ctrl-x ctrl-s", "Save the file", save)
+  keymap.BindScoped(list, "top", "g g", "Go to the first item", top)
+  app.SetKeymap(keymap)
+
+Pressing F1 shows an overlay listing the bindings which are currently active.
+
+Props
+
+Primitives may declare typed props with Box.DeclareProps(). SetProp() rejects
+values of undeclared props or of the wrong type and notifies the handlers
+added with SubscribeProp() of changes. Some primitives bind props to their
+state, e.g. "label" of Button, "text" of InputField, and "cells" of Table, so
+that they can be driven by an application's state layer. Their subscribers are
+also notified when that state is changed with the primitives' setters:
+
+  button.SetProp("label", "Save")
+  inputField.SubscribeProp("text", func(prop string, value interface{}) {
+  	state.Name = value.(string)
+  })
+
+Mouse Support
+
+Mouse events are processed once they are enabled with
+Application.EnableMouse(). The application derives mouse actions such as
+clicks, double clicks, drags, and scroll wheel movements from the events
+reported by tcell and passes them on to the root primitive's mouse handler
+(see Primitive.MouseHandler()). Containers such as Flex, Pages, and Form
+forward them to the primitive under the mouse pointer. Clicking a primitive
+gives it the focus. Use Box.SetMouseCapture() to intercept mouse events.
+
+Testing
+
+Applications normally run on a screen created for the terminal. With
+Application.SetScreen(), they can also run on any other tcell.Screen, e.g. a
+tcell.SimulationScreen. The tviewtest subpackage builds on this: it runs a
+primitive on a simulated screen, injects key, mouse, and resize events, and
+returns the resulting screen contents as text and styles for comparison with
+golden files.
 */
 package tview
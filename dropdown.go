@@ -1,6 +1,9 @@
 package tview
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/gdamore/tcell"
 )
 
@@ -37,6 +40,9 @@ type DropDown struct {
 	// The label color.
 	labelColor tcell.Color
 
+	// The text attributes of the label.
+	labelAttributes tcell.AttrMask
+
 	// The background color of the input area.
 	fieldBackgroundColor tcell.Color
 
@@ -66,6 +72,7 @@ func NewDropDown() *DropDown {
 		currentOption:        -1,
 		list:                 list,
 		labelColor:           Styles.SecondaryTextColor,
+		labelAttributes:      Styles.LabelAttributes,
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 	}
@@ -76,10 +83,25 @@ func NewDropDown() *DropDown {
 }
 
 func (d *DropDown) GetValues() map[string]interface{} {
-	_, c := d.GetCurrentOption()
+	var value interface{}
+	if _, option := d.GetCurrentOption(); option != nil {
+		value = option.Value
+	}
 	return map[string]interface{}{
-		d.name: c.Value,
+		d.name: value,
+	}
+}
+
+// SetValue selects the first option whose value equals the given value. An
+// error is returned if there is no such option.
+func (d *DropDown) SetValue(value interface{}) error {
+	for index, option := range d.options {
+		if reflect.DeepEqual(option.Value, value) {
+			d.SetCurrentOption(index)
+			return nil
+		}
 	}
+	return fmt.Errorf("no option with value %v", value)
 }
 
 func (d *DropDown) SetValues(values map[string]interface{}) {
@@ -132,6 +154,13 @@ func (d *DropDown) SetLabelColor(color tcell.Color) *DropDown {
 	return d
 }
 
+// SetLabelAttributes sets the text attributes of the label, e.g.
+// tcell.AttrBold.
+func (d *DropDown) SetLabelAttributes(attributes tcell.AttrMask) *DropDown {
+	d.labelAttributes = attributes
+	return d
+}
+
 // SetFieldBackgroundColor sets the background color of the options area.
 func (d *DropDown) SetFieldBackgroundColor(color tcell.Color) *DropDown {
 	d.fieldBackgroundColor = color
@@ -144,6 +173,20 @@ func (d *DropDown) SetFieldTextColor(color tcell.Color) *DropDown {
 	return d
 }
 
+// ApplyTheme sets the colors and attributes of the drop-down to those of the
+// given theme.
+func (d *DropDown) ApplyTheme(theme *Theme) {
+	d.Box.ApplyTheme(theme)
+	d.labelColor = theme.SecondaryTextColor
+	d.labelAttributes = theme.LabelAttributes
+	d.fieldBackgroundColor = theme.ContrastBackgroundColor
+	d.fieldTextColor = theme.PrimaryTextColor
+	d.list.SetMainTextColor(theme.PrimitiveBackgroundColor).
+		SetSelectedTextColor(theme.PrimitiveBackgroundColor).
+		SetSelectedBackgroundColor(theme.PrimaryTextColor).
+		SetBackgroundColor(theme.MoreContrastBackgroundColor)
+}
+
 // SetFormAttributes sets attributes shared by all form items.
 func (d *DropDown) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
 	d.label = label
@@ -215,6 +258,12 @@ func (d *DropDown) SetDoneFunc(handler func(key tcell.Key)) *DropDown {
 	return d
 }
 
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the drop-down with Tab or Backtab.
+func (d *DropDown) hasDoneFunc() bool {
+	return d.done != nil
+}
+
 // SetFinishedFunc calls SetDoneFunc().
 func (d *DropDown) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return d.SetDoneFunc(handler)
@@ -240,7 +289,7 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	}
 
 	// Draw label.
-	_, drawnWidth := Print(screen, d.label, x, y, rightLimit-x, AlignLeft, d.labelColor)
+	_, drawnWidth := printWithAttributes(screen, d.label, x, y, rightLimit-x, AlignLeft, d.labelColor, d.labelAttributes)
 	x += drawnWidth
 
 	// What's the longest option text?
@@ -305,23 +354,7 @@ func (d *DropDown) InputHandler() func(tcell.Event, func(Primitive)) {
 				if key == tcell.KeyRune && evt.Rune() != ' ' {
 					break
 				}
-				d.open = true
-				d.list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
-					// An option was selected. Close the list again.
-					d.open = false
-					setFocus(d)
-					d.currentOption = index
-
-					// Trigger "selected" event.
-					if d.options[d.currentOption].Selected != nil {
-						d.options[d.currentOption].Selected()
-					}
-				})
-				d.list.SetDoneFunc(func() {
-					d.open = false
-					setFocus(d)
-				})
-				setFocus(d.list)
+				d.openList(setFocus)
 			case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
 				if d.done != nil {
 					d.done(key)
@@ -331,6 +364,68 @@ func (d *DropDown) InputHandler() func(tcell.Event, func(Primitive)) {
 	})
 }
 
+// openList opens the list of options and hands the focus to it.
+func (d *DropDown) openList(setFocus func(p Primitive)) {
+	d.open = true
+	d.list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		// An option was selected. Close the list again.
+		d.open = false
+		setFocus(d)
+		d.currentOption = index
+
+		// Trigger "selected" event.
+		if d.options[d.currentOption].Selected != nil {
+			d.options[d.currentOption].Selected()
+		}
+	})
+	d.list.SetDoneFunc(func() {
+		d.open = false
+		setFocus(d)
+	})
+	setFocus(d.list)
+}
+
+// MouseHandler returns the mouse handler for this primitive. While the list of
+// options is open, the drop-down captures the mouse so clicks outside of it
+// close the list again.
+func (d *DropDown) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		inRect := d.InRect(event.Position())
+
+		if d.open {
+			// Pass mouse events on to the list of options first.
+			if d.list.InRect(event.Position()) {
+				d.list.MouseHandler()(action, event, setFocus)
+			} else if action == MouseLeftClick {
+				// A click outside of the list closes it.
+				d.open = false
+				setFocus(d)
+			}
+			if d.open {
+				capture = d
+			}
+			return true, capture
+		}
+
+		if !inRect {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(d)
+			consumed = true
+		case MouseLeftClick:
+			d.openList(setFocus)
+			consumed = true
+			capture = d
+		}
+
+		return
+	})
+}
+
 // Focus is called by the application when the primitive receives focus.
 func (d *DropDown) Focus(delegate func(p Primitive)) {
 	d.Box.Focus(delegate)
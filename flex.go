@@ -100,6 +100,20 @@ func (f *Flex) AddFlexItem(item FlexItem) *Flex {
 	return f
 }
 
+// Children returns the flex items' primitives.
+func (f *Flex) Children() []Primitive {
+	//f.RLock()
+	//defer f.RUnlock()
+
+	children := make([]Primitive, 0, len(f.items))
+	for _, item := range f.items {
+		if item.Item != nil {
+			children = append(children, item.Item)
+		}
+	}
+	return children
+}
+
 func (f *Flex) GetItems() []FlexItem {
 	//f.RLock()
 	//defer f.RUnlock()
@@ -254,6 +268,34 @@ func (f *Flex) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// MouseHandler returns the mouse handler for this primitive. Mouse events are
+// passed on to the first item which consumes them. Clicking an item gives it
+// the focus.
+func (f *Flex) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !f.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the first item that takes it.
+		for _, item := range f.items {
+			if item.Item == nil {
+				continue
+			}
+			handler := item.Item.MouseHandler()
+			if handler == nil {
+				continue
+			}
+			consumed, capture = handler(action, event, setFocus)
+			if consumed {
+				return
+			}
+		}
+
+		return
+	})
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (f *Flex) HasFocus() bool {
 	//f.RLock()
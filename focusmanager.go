@@ -0,0 +1,378 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+// FocusManager moves the keyboard focus between the primitives of an
+// application's primitive tree. It is installed with
+// Application.SetFocusManager() and then handles the following keys before
+// they reach the focused primitive:
+//
+//   - Tab, Backtab: Move the focus to the next or previous primitive in the
+//     focus chain.
+//   - Arrow keys combined with the directional modifier (Alt by default): Move
+//     the focus to the nearest primitive in that direction on screen.
+//
+// Tab and Backtab are left to the focused primitive if it uses them itself
+// (e.g. an InputField accepting an autocomplete suggestion or a TextArea
+// inserting a tab character) or if it has a handler for them (see e.g.
+// InputField.SetDoneFunc()). Within a Form, the form moves the focus between
+// its items and buttons, validating them as usual, and the focus manager only
+// takes over when Tab is pressed on the form's last element or Backtab on its
+// first one.
+//
+// The focus chain consists of all primitives in the tree which do not contain
+// other primitives (see Container), in the order they are drawn. Hidden pages
+// and plain Boxes (often used as spacers) are skipped. Form items and buttons
+// are focused through their Form so that the form's own navigation continues
+// from there.
+//
+// The focus chain may be restricted to a scope, i.e. the subtree of a
+// primitive, from which the focus cannot escape. The top-most visible Modal
+// automatically becomes the scope. Other scopes can be set with PushScope().
+//
+// If the focused primitive is not part of the primitive tree (e.g. the list of
+// an open DropDown), all keys are passed on to it.
+type FocusManager struct {
+	// The application whose focus is managed.
+	app *Application
+
+	// The modifier which needs to be pressed with arrow keys to move the focus
+	// directionally.
+	directionalModifier tcell.ModMask
+
+	// The stack of explicit focus scopes. The last one is active.
+	scopes []Primitive
+}
+
+// NewFocusManager returns a new focus manager. It needs to be installed with
+// Application.SetFocusManager().
+func NewFocusManager() *FocusManager {
+	return &FocusManager{
+		directionalModifier: tcell.ModAlt,
+	}
+}
+
+// SetDirectionalModifier sets the modifier keys which need to be pressed with
+// the arrow keys to move the focus directionally. Provide tcell.ModNone to
+// use the arrow keys alone (which means they will not reach the focused
+// primitive anymore).
+func (m *FocusManager) SetDirectionalModifier(mod tcell.ModMask) *FocusManager {
+	m.directionalModifier = mod
+	return m
+}
+
+// PushScope restricts the focus chain to the subtree of the given primitive
+// until PopScope() is called. If the focus is currently outside the scope, it
+// is moved to the first primitive of the scope.
+func (m *FocusManager) PushScope(scope Primitive) *FocusManager {
+	m.scopes = append(m.scopes, scope)
+	m.enterScope()
+	return m
+}
+
+// PopScope removes the scope last added with PushScope().
+func (m *FocusManager) PopScope() *FocusManager {
+	if len(m.scopes) > 0 {
+		m.scopes = m.scopes[:len(m.scopes)-1]
+	}
+	return m
+}
+
+// Scope returns the primitive whose subtree currently contains the focus
+// chain. This is the scope last added with PushScope(), if any, else the
+// top-most visible Modal, if any, else the application's root primitive.
+func (m *FocusManager) Scope() Primitive {
+	if len(m.scopes) > 0 {
+		return m.scopes[len(m.scopes)-1]
+	}
+	if m.app == nil {
+		return nil
+	}
+	root := m.app.GetRoot()
+	var modal Primitive
+	walkVisible(root, func(p Primitive) {
+		if _, ok := p.(*Modal); ok {
+			modal = p
+		}
+	})
+	if modal != nil {
+		return modal
+	}
+	return root
+}
+
+// Chain returns the primitives which may receive the focus, in the order in
+// which Tab moves through them.
+func (m *FocusManager) Chain() []Primitive {
+	var chain []Primitive
+	walkVisible(m.Scope(), func(p Primitive) {
+		if _, ok := p.(Container); ok {
+			return
+		}
+		if _, ok := p.(*Box); ok {
+			return // Plain boxes have nothing to focus.
+		}
+		chain = append(chain, p)
+	})
+	return chain
+}
+
+// Next moves the focus to the next primitive in the focus chain, wrapping
+// around at the end. It returns false if the focus was not moved.
+func (m *FocusManager) Next() bool {
+	return m.step(1)
+}
+
+// Previous moves the focus to the previous primitive in the focus chain,
+// wrapping around at the beginning. It returns false if the focus was not
+// moved.
+func (m *FocusManager) Previous() bool {
+	return m.step(-1)
+}
+
+// step moves the focus by the given number of primitives in the focus chain.
+func (m *FocusManager) step(delta int) bool {
+	chain := m.Chain()
+	if len(chain) == 0 {
+		return false
+	}
+	index := m.indexOf(chain, m.app.GetFocus())
+	if index < 0 {
+		// Not in the chain yet. Enter it at the beginning or the end.
+		if delta > 0 {
+			index = -1
+		} else {
+			index = len(chain)
+		}
+	}
+	index = (index + delta + len(chain)) % len(chain)
+	m.focus(chain[index])
+	return true
+}
+
+// Move moves the focus to the nearest primitive of the focus chain in the
+// given direction, e.g. dx = 1 and dy = 0 for right, based on the primitives'
+// positions on screen. It returns false if there is no primitive in that
+// direction.
+func (m *FocusManager) Move(dx, dy int) bool {
+	chain := m.Chain()
+	index := m.indexOf(chain, m.app.GetFocus())
+	if index < 0 {
+		return m.step(1)
+	}
+
+	x, y, width, height := chain[index].GetRect()
+	best, bestScore := -1, 0
+	for candidate, p := range chain {
+		if candidate == index {
+			continue
+		}
+		cx, cy, cwidth, cheight := p.GetRect()
+
+		// How far is the candidate away in the given direction and how far is it
+		// off to the side?
+		var distance, offset int
+		switch {
+		case dx > 0:
+			distance = cx - (x + width)
+			offset = rangeDistance(y, height, cy, cheight)
+		case dx < 0:
+			distance = x - (cx + cwidth)
+			offset = rangeDistance(y, height, cy, cheight)
+		case dy > 0:
+			distance = cy - (y + height)
+			offset = rangeDistance(x, width, cx, cwidth)
+		case dy < 0:
+			distance = y - (cy + cheight)
+			offset = rangeDistance(x, width, cx, cwidth)
+		default:
+			return false
+		}
+		if distance < 0 {
+			continue // Not in that direction.
+		}
+
+		score := distance + 2*offset
+		if best < 0 || score < bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	if best < 0 {
+		return false
+	}
+	m.focus(chain[best])
+	return true
+}
+
+// rangeDistance returns the distance between two one-dimensional ranges given
+// by their starts and lengths, or 0 if they overlap.
+func rangeDistance(start1, length1, start2, length2 int) int {
+	if start2 >= start1+length1 {
+		return start2 - (start1 + length1) + 1
+	}
+	if start1 >= start2+length2 {
+		return start1 - (start2 + length2) + 1
+	}
+	return 0
+}
+
+// HandleKey handles the given key event if it moves the focus and returns
+// whether it did. This is called by the application for every key event
+// before it is passed to the focused primitive.
+func (m *FocusManager) HandleKey(event *tcell.EventKey) bool {
+	if m.app == nil {
+		return false
+	}
+
+	// Most keys don't move the focus. Don't walk the tree for them.
+	key := event.Key()
+	switch key {
+	case tcell.KeyTab, tcell.KeyBacktab:
+	case tcell.KeyUp, tcell.KeyDown, tcell.KeyLeft, tcell.KeyRight:
+		if event.Modifiers() != m.directionalModifier {
+			return false
+		}
+	default:
+		return false
+	}
+
+	// Leave primitives which are not part of the tree alone.
+	focus := m.app.GetFocus()
+	if focus != nil && FindById(m.app.GetRoot(), focus.Id()) == nil {
+		return false
+	}
+
+	switch key {
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if focus != nil && !m.leavesWithTab(focus, key) {
+			return false
+		}
+		if key == tcell.KeyTab {
+			return m.Next()
+		}
+		return m.Previous()
+	case tcell.KeyUp:
+		return m.Move(0, -1)
+	case tcell.KeyDown:
+		return m.Move(0, 1)
+	case tcell.KeyLeft:
+		return m.Move(-1, 0)
+	default:
+		return m.Move(1, 0)
+	}
+}
+
+// tabUser is implemented by primitives which use Tab or Backtab themselves in
+// some situations.
+type tabUser interface {
+	usesTab(key tcell.Key) bool
+}
+
+// doneNotifier is implemented by primitives which call a handler when the user
+// leaves them with Tab or Backtab.
+type doneNotifier interface {
+	hasDoneFunc() bool
+}
+
+// leavesWithTab returns whether the focus manager moves the focus away from
+// the given primitive when the given key (Tab or Backtab) is pressed, as
+// opposed to leaving it to the primitive.
+func (m *FocusManager) leavesWithTab(p Primitive, key tcell.Key) bool {
+	if user, ok := p.(tabUser); ok && user.usesTab(key) {
+		return false
+	}
+
+	// Forms navigate between their own elements.
+	if form, ok := Parent(m.app.GetRoot(), p).(*Form); ok {
+		if key == tcell.KeyTab && form.focusedElement < len(form.items)+len(form.buttons)-1 ||
+			key == tcell.KeyBacktab && form.focusedElement > 0 {
+			return false
+		}
+		// Validate the item as the form does when it is left.
+		if form.focusedElement < len(form.items) {
+			form.validateItem(form.focusedElement)
+		}
+		return true
+	}
+
+	if notifier, ok := p.(doneNotifier); ok && notifier.hasDoneFunc() {
+		return false
+	}
+	return true
+}
+
+// enterScope moves the focus into the current scope if it is outside.
+func (m *FocusManager) enterScope() {
+	if m.app == nil {
+		return
+	}
+	focus := m.app.GetFocus()
+	if focus != nil && FindById(m.Scope(), focus.Id()) != nil {
+		return
+	}
+	if chain := m.Chain(); len(chain) > 0 {
+		m.focus(chain[0])
+	}
+}
+
+// indexOf returns the index of the given primitive in the focus chain or -1
+// if it is not part of it. If the primitive is a container, the index of its
+// first descendant in the chain is returned.
+func (m *FocusManager) indexOf(chain []Primitive, p Primitive) int {
+	if p == nil {
+		return -1
+	}
+	for index, candidate := range chain {
+		if candidate == p {
+			return index
+		}
+	}
+	if _, ok := p.(Container); ok {
+		for index, candidate := range chain {
+			if FindById(p, candidate.Id()) != nil {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+// focus gives the focus to the given primitive of the focus chain. Form
+// elements are focused through their form.
+func (m *FocusManager) focus(p Primitive) {
+	if form, ok := Parent(m.app.GetRoot(), p).(*Form); ok {
+		for index, child := range form.Children() {
+			if child == p {
+				form.SetFocus(index)
+				if !form.HasFocus() {
+					m.app.SetFocus(form)
+				}
+				return
+			}
+		}
+	}
+	m.app.SetFocus(p)
+}
+
+// walkVisible calls the visit function for all visible primitives in the tree
+// starting at root, in the order they are drawn.
+func walkVisible(root Primitive, visit func(p Primitive)) {
+	if root == nil {
+		return
+	}
+	visit(root)
+	var children []Primitive
+	if pages, ok := root.(interface {
+		visibleChildren() []Primitive
+	}); ok {
+		children = pages.visibleChildren()
+	} else if container, ok := root.(Container); ok {
+		children = container.Children()
+	}
+	for _, child := range children {
+		walkVisible(child, visit)
+	}
+}
@@ -0,0 +1,194 @@
+package tview_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// focusLayout is a layout with a list on the left and a form above a text
+// view on the right.
+type focusLayout struct {
+	root   *tview.Pages
+	list   *tview.List
+	form   *tview.Form
+	input  *tview.InputField
+	button *tview.Button
+	text   *tview.TextView
+}
+
+func newFocusLayout() *focusLayout {
+	l := &focusLayout{
+		list:  tview.NewList().AddItem("Item", "", 0, nil),
+		input: tview.NewInputField().SetLabel("Name"),
+		text:  tview.NewTextView(),
+	}
+	l.form = tview.NewForm().AddFormItem(l.input).AddButton("OK", nil)
+	l.button = l.form.Children()[1].(*tview.Button)
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(l.form, 0, 1, false).
+		AddItem(l.text, 0, 1, false)
+	flex := tview.NewFlex().
+		AddItem(l.list, 0, 1, true).
+		AddItem(tview.NewBox(), 2, 0, false).
+		AddItem(right, 0, 1, false)
+	l.root = tview.NewPages().AddPage("main", flex, true, true)
+	return l
+}
+
+// start runs the layout in a harness with a focus manager.
+func (l *focusLayout) start() *tviewtest.Harness {
+	h := tviewtest.New(l.root, 40, 20)
+	h.Do(func() {
+		h.App.SetFocusManager(tview.NewFocusManager())
+	})
+	return h
+}
+
+// focus returns the primitive which has the focus.
+func focus(h *tviewtest.Harness) (p tview.Primitive) {
+	h.Do(func() {
+		p = h.App.GetFocus()
+	})
+	return
+}
+
+func TestFocusManagerTab(t *testing.T) {
+	l := newFocusLayout()
+	h := l.start()
+	defer h.Stop()
+
+	for index, want := range []tview.Primitive{l.input, l.button, l.text, l.list} {
+		h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+		if p := focus(h); p != want {
+			t.Fatalf("Tab %d focused %T, want %T", index+1, p, want)
+		}
+	}
+	for index, want := range []tview.Primitive{l.text, l.button, l.input} {
+		h.KeyPress(tcell.KeyBacktab, 0, tcell.ModNone)
+		if p := focus(h); p != want {
+			t.Fatalf("Backtab %d focused %T, want %T", index+1, p, want)
+		}
+	}
+}
+
+func TestFocusManagerDirectional(t *testing.T) {
+	l := newFocusLayout()
+	h := l.start()
+	defer h.Stop()
+
+	// The text view is closer to the list than the form's input field.
+	h.KeyPress(tcell.KeyRight, 0, tcell.ModAlt)
+	if p := focus(h); p != l.text {
+		t.Fatalf("Alt-Right focused %T, want the text view", p)
+	}
+	h.KeyPress(tcell.KeyUp, 0, tcell.ModAlt)
+	if p := focus(h); p != l.button {
+		t.Fatalf("Alt-Up focused %T, want the button", p)
+	}
+	h.KeyPress(tcell.KeyLeft, 0, tcell.ModAlt)
+	if p := focus(h); p != l.list {
+		t.Fatalf("Alt-Left focused %T, want the list", p)
+	}
+}
+
+func TestFocusManagerFormValidation(t *testing.T) {
+	l := newFocusLayout()
+	l.form.SetItemValidator(0, func(value interface{}) error {
+		return errors.New("required")
+	})
+	h := l.start()
+	defer h.Stop()
+
+	h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+	var err error
+	h.Do(func() {
+		err = l.form.GetItemError(0)
+	})
+	if err == nil {
+		t.Error("input field was not validated when it was left")
+	}
+	if p := focus(h); p != l.button {
+		t.Errorf("Tab focused %T, want the button", p)
+	}
+}
+
+func TestFocusManagerModal(t *testing.T) {
+	l := newFocusLayout()
+	h := l.start()
+	defer h.Stop()
+
+	modal := tview.NewModal().AddButtons([]string{"Yes", "No"})
+	h.Do(func() {
+		l.root.AddPage("modal", modal, true, true)
+	})
+	for index := 0; index < 3; index++ {
+		h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+		if !modal.HasFocus() {
+			t.Fatalf("Tab %d left the modal", index+1)
+		}
+	}
+}
+
+func TestFocusManagerTextArea(t *testing.T) {
+	area := tview.NewTextArea()
+	flex := tview.NewFlex().
+		AddItem(area, 0, 1, true).
+		AddItem(tview.NewTextView(), 0, 1, false)
+	h := tviewtest.New(flex, 40, 5)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.SetFocusManager(tview.NewFocusManager())
+	})
+
+	h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+	var text string
+	h.Do(func() {
+		text = area.GetText()
+	})
+	if text == "" || strings.Trim(text, " \t") != "" {
+		t.Errorf("text area contains %q after Tab, want a tab", text)
+	}
+	if p := focus(h); p != area {
+		t.Errorf("Tab moved the focus to %T", p)
+	}
+}
+
+func TestFocusManagerAutocomplete(t *testing.T) {
+	input := tview.NewInputField().SetAutocompleteFunc(func(ctx context.Context, text string, suggest func(entries []string)) {
+		suggest([]string{"alpha.example.com"})
+	})
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(tview.NewTextView(), 0, 1, false)
+	h := tviewtest.New(flex, 30, 5)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.SetFocusManager(tview.NewFocusManager())
+	})
+
+	h.Type("al")
+	h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+	var text string
+	h.Do(func() {
+		text = input.GetText()
+	})
+	if text != "alpha.example.com" {
+		t.Errorf("Tab did not accept the suggestion, text is %q", text)
+	}
+	if p := focus(h); p != input {
+		t.Errorf("Tab moved the focus to %T", p)
+	}
+
+	// Without suggestions, Tab moves the focus.
+	h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+	if p := focus(h); p == input {
+		t.Error("Tab did not move the focus")
+	}
+}
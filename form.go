@@ -1,6 +1,7 @@
 package tview
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/gdamore/tcell"
@@ -11,6 +12,10 @@ import (
 // horizontal layouts.
 var DefaultFormFieldWidth = 10
 
+// DefaultFormFieldHeight is the default field height, in lines, of multi-line
+// form elements whose field height is flexible (0).
+var DefaultFormFieldHeight = 3
+
 // FormItem is the interface all form items must implement to be able to be
 // included in a form.
 type FormItem interface {
@@ -35,6 +40,34 @@ type FormItem interface {
 	SetFinishedFunc(handler func(key tcell.Key)) FormItem
 }
 
+// FormValueItem is the interface form items implement if they hold a value
+// which can be collected with Form.GetValues() and set with Form.SetValues().
+// Values are keyed by the item's name (see Box.SetName()).
+type FormValueItem interface {
+	FormItem
+
+	// Name returns the item's name.
+	Name() string
+
+	// GetValues returns a map from the item's name to its value.
+	GetValues() map[string]interface{}
+
+	// SetValue sets the item's value. An error is returned if the value is not
+	// valid for this item, e.g. because it has the wrong type.
+	SetValue(value interface{}) error
+}
+
+// MultiLineFormItem is the interface form items implement if their field spans
+// more than one line, e.g. TextArea.
+type MultiLineFormItem interface {
+	FormItem
+
+	// GetFieldHeight returns the height of the form item's field in lines. A
+	// value of 0 indicates that the field height is flexible, in which case
+	// DefaultFormFieldHeight is used.
+	GetFieldHeight() int
+}
+
 // Form allows you to combine multiple one-line form elements into a vertical
 // or horizontal layout. Form elements include types such as InputField or
 // Checkbox. These elements can be optionally followed by one or more buttons
@@ -44,7 +77,7 @@ type FormItem interface {
 type Form struct {
 	*Box
 
-	// The items of the form (one row per item).
+	// The items of the form (one row per item, more for multi-line items).
 	items []FormItem
 
 	// The buttons of the form.
@@ -79,6 +112,23 @@ type Form struct {
 	// The color of the button text.
 	buttonTextColor tcell.Color
 
+	// The color of validation error messages.
+	errorColor tcell.Color
+
+	// Optional functions which check the values of form items, and the errors
+	// they returned during the last validation.
+	validators map[FormItem]func(value interface{}) error
+	errors     map[FormItem]error
+
+	// An optional function which is called by Submit() when all items are
+	// valid. It receives the form's values and may return errors for items,
+	// keyed by their names.
+	submit func(values map[string]interface{}) (errors map[string]error)
+
+	// We keep a reference to the function which allows us to set the focus to
+	// an invalid item.
+	setFocus func(p Primitive)
+
 	// An optional function which is called when the user hits Escape.
 	cancel func()
 }
@@ -95,6 +145,9 @@ func NewForm() *Form {
 		fieldTextColor:        Styles.PrimaryTextColor,
 		buttonBackgroundColor: Styles.ContrastBackgroundColor,
 		buttonTextColor:       Styles.PrimaryTextColor,
+		errorColor:            Styles.ErrorTextColor,
+		validators:            make(map[FormItem]func(value interface{}) error),
+		errors:                make(map[FormItem]error),
 	}
 
 	f.focus = f
@@ -137,6 +190,13 @@ func (f *Form) SetFieldTextColor(color tcell.Color) *Form {
 	return f
 }
 
+// SetErrorColor sets the color of validation error messages shown underneath
+// invalid items.
+func (f *Form) SetErrorColor(color tcell.Color) *Form {
+	f.errorColor = color
+	return f
+}
+
 // SetButtonsAlign sets how the buttons align horizontally, one of AlignLeft
 // (the default), AlignCenter, and AlignRight. This is only
 func (f *Form) SetButtonsAlign(align int) *Form {
@@ -156,6 +216,19 @@ func (f *Form) SetButtonTextColor(color tcell.Color) *Form {
 	return f
 }
 
+// ApplyTheme sets the form's colors and attributes to those of the given
+// theme. They are passed on to the form's items and buttons when the form is
+// drawn.
+func (f *Form) ApplyTheme(theme *Theme) {
+	f.Box.ApplyTheme(theme)
+	f.labelColor = theme.SecondaryTextColor
+	f.fieldBackgroundColor = theme.ContrastBackgroundColor
+	f.fieldTextColor = theme.PrimaryTextColor
+	f.buttonBackgroundColor = theme.ContrastBackgroundColor
+	f.buttonTextColor = theme.PrimaryTextColor
+	f.errorColor = theme.ErrorTextColor
+}
+
 // AddInputField adds an input field to the form. It has a label, an optional
 // initial value, a field width (a value of 0 extends it as far as possible),
 // an optional accept function to validate the item's value (set to nil to
@@ -171,6 +244,20 @@ func (f *Form) AddInputField(label, value string, fieldWidth int, accept func(te
 	return f
 }
 
+// AddTextArea adds a multi-line text area to the form. It has a label, an
+// optional initial text, a field width and height (a value of 0 uses the
+// defaults, see DefaultFormFieldHeight), and an (optional) callback function
+// which is invoked when the text has changed.
+func (f *Form) AddTextArea(label, text string, fieldWidth, fieldHeight int, changed func(text string)) *Form {
+	f.items = append(f.items, NewTextArea().
+		SetLabel(label).
+		SetText(text).
+		SetFieldWidth(fieldWidth).
+		SetFieldHeight(fieldHeight).
+		SetChangedFunc(changed))
+	return f
+}
+
 // AddPasswordField adds a password field to the form. This is similar to an
 // input field except that the user's input not shown. Instead, a "mask"
 // character is displayed. The password field has a label, an optional initial
@@ -219,10 +306,20 @@ func (f *Form) AddButton(label string, selected func()) *Form {
 	return f
 }
 
+// AddSubmitButton adds a new button to the form which calls Submit() when
+// selected.
+func (f *Form) AddSubmitButton(label string) *Form {
+	return f.AddButton(label, func() {
+		f.Submit()
+	})
+}
+
 // Clear removes all input elements from the form, including the buttons if
 // specified.
 func (f *Form) Clear(includeButtons bool) *Form {
 	f.items = nil
+	f.validators = make(map[FormItem]func(value interface{}) error)
+	f.errors = make(map[FormItem]error)
 	if includeButtons {
 		f.buttons = nil
 	}
@@ -245,6 +342,182 @@ func (f *Form) GetFormItem(index int) FormItem {
 	return f.items[index]
 }
 
+// GetValues returns the values of all form items which implement
+// FormValueItem, merged into one map keyed by the items' names. Items without
+// a name are skipped.
+func (f *Form) GetValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, item := range f.items {
+		valueItem, ok := item.(FormValueItem)
+		if !ok || valueItem.Name() == "" {
+			continue
+		}
+		for name, value := range valueItem.GetValues() {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// SetValues sets the values of the form items whose names are keys of the
+// given map. Items which don't implement FormValueItem or whose names are not
+// found in the map are left unchanged. All values are set even if some of them
+// fail, in which case the first error is returned.
+func (f *Form) SetValues(values map[string]interface{}) error {
+	var firstErr error
+	for _, item := range f.items {
+		valueItem, ok := item.(FormValueItem)
+		if !ok || valueItem.Name() == "" {
+			continue
+		}
+		value, ok := values[valueItem.Name()]
+		if !ok {
+			continue
+		}
+		if err := valueItem.SetValue(value); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("form item %q: %s", valueItem.Name(), err)
+		}
+	}
+	return firstErr
+}
+
+// SetItemValidator sets a function which checks the value of the form item
+// with the given index (see GetFormItem()). It is called with the item's value
+// (nil for items which don't implement FormValueItem) when the item loses focus,
+// e.g. when the user tabs or clicks away from it, and when the form is
+// validated. If it returns an error, the error
+// message is shown underneath the item until the item is valid again.
+//
+// Provide nil to remove the validator.
+func (f *Form) SetItemValidator(index int, validator func(value interface{}) error) *Form {
+	item := f.items[index]
+	if validator == nil {
+		delete(f.validators, item)
+		delete(f.errors, item)
+	} else {
+		f.validators[item] = validator
+	}
+	return f
+}
+
+// GetItemError returns the error of the form item with the given index as
+// determined by the last validation or by the submit function, or nil if the
+// item is valid.
+func (f *Form) GetItemError(index int) error {
+	return f.errors[f.items[index]]
+}
+
+// SetItemError sets an error for the form item with the given index which is
+// shown underneath the item. Provide nil to clear the error. This can be used
+// to report errors which are not detected by the item's validator. The error
+// is cleared when the item is validated again or when Submit() is called.
+func (f *Form) SetItemError(index int, err error) *Form {
+	if err == nil {
+		delete(f.errors, f.items[index])
+	} else {
+		f.errors[f.items[index]] = err
+	}
+	return f
+}
+
+// validateItem runs the validator of the form item with the given index, if
+// there is one, and records its result. It returns the validation error.
+func (f *Form) validateItem(index int) error {
+	item := f.items[index]
+	validator, ok := f.validators[item]
+	if !ok {
+		return f.errors[item]
+	}
+
+	var value interface{}
+	if valueItem, ok := item.(FormValueItem); ok {
+		value = valueItem.GetValues()[valueItem.Name()]
+	}
+	err := validator(value)
+	if err == nil {
+		delete(f.errors, item)
+	} else {
+		f.errors[item] = err
+	}
+	return err
+}
+
+// Validate runs the validators of all form items and returns the index of the
+// first invalid item or -1 if all items are valid. Errors are shown underneath
+// the invalid items.
+func (f *Form) Validate() int {
+	firstInvalid := -1
+	for index := range f.items {
+		if f.validateItem(index) != nil && firstInvalid < 0 {
+			firstInvalid = index
+		}
+	}
+	return firstInvalid
+}
+
+// SetSubmitFunc sets a handler which is called by Submit() when all form items
+// are valid. It receives the form's values (see GetValues()) and may return
+// errors for items, keyed by the items' names, e.g. when a server rejected
+// the values. These errors are shown underneath the items.
+func (f *Form) SetSubmitFunc(handler func(values map[string]interface{}) (errors map[string]error)) *Form {
+	f.submit = handler
+	return f
+}
+
+// Submit validates all form items. If an item is invalid, the focus is moved to
+// the first invalid item and false is returned. Otherwise, the submit function
+// (see SetSubmitFunc()) is called with the form's values. If it returns any
+// errors, the focus is moved to the first item with an error and false is
+// returned. True is returned if the values were submitted successfully.
+//
+// Errors returned by the submit function during a previous call or set with
+// SetItemError() are cleared first as they may no longer apply.
+func (f *Form) Submit() bool {
+	f.errors = make(map[FormItem]error)
+	firstInvalid := f.Validate()
+	submitErrors := make(map[FormItem]error)
+	if firstInvalid < 0 && f.submit != nil {
+		errors := f.submit(f.GetValues())
+		for index, item := range f.items {
+			valueItem, ok := item.(FormValueItem)
+			if !ok || valueItem.Name() == "" {
+				continue
+			}
+			if err := errors[valueItem.Name()]; err != nil {
+				submitErrors[item] = err
+				if firstInvalid < 0 {
+					firstInvalid = index
+				}
+			}
+		}
+	}
+
+	if firstInvalid >= 0 {
+		// Moving the focus validates the item which is left, so the errors
+		// of the submit function are recorded afterwards.
+		f.SetFocus(firstInvalid)
+		for item, err := range submitErrors {
+			f.errors[item] = err
+		}
+		return false
+	}
+	return true
+}
+
+// SetFocus shifts the focus to the form element with the given index, counting
+// items first and buttons last. If the form does not have focus, the element
+// will receive the focus the next time the form does.
+func (f *Form) SetFocus(index int) *Form {
+	if index < 0 || index >= len(f.items)+len(f.buttons) {
+		return f
+	}
+	f.focusedElement = index
+	if f.setFocus != nil && f.HasFocus() {
+		f.Focus(f.setFocus)
+	}
+	return f
+}
+
 // SetCancelFunc sets a handler which is called when the user hits the Escape
 // key.
 func (f *Form) SetCancelFunc(callback func()) *Form {
@@ -311,13 +584,23 @@ func (f *Form) Draw(screen tcell.Screen) {
 		if x+itemWidth >= rightLimit {
 			itemWidth = rightLimit - x
 		}
+		itemHeight := 1
+		if multiLine, ok := item.(MultiLineFormItem); ok {
+			itemHeight = multiLine.GetFieldHeight()
+			if itemHeight <= 0 {
+				itemHeight = DefaultFormFieldHeight
+			}
+			if y+itemHeight > bottomLimit {
+				itemHeight = bottomLimit - y
+			}
+		}
 		item.SetFormAttributes(
 			label,
 			f.labelColor,
 			f.backgroundColor,
 			f.fieldTextColor,
 			f.fieldBackgroundColor,
-		).SetRect(x, y, itemWidth, 1)
+		).SetRect(x, y, itemWidth, itemHeight)
 
 		// Draw items with focus last (in case of overlaps).
 		if item.GetFocusable().HasFocus() {
@@ -326,11 +609,23 @@ func (f *Form) Draw(screen tcell.Screen) {
 			item.Draw(screen)
 		}
 
+		// Draw the validation error underneath the field.
+		err := f.errors[item]
+		if err != nil && y+itemHeight < bottomLimit {
+			fieldX := x + StringWidth(label)
+			if fieldX < x+itemWidth {
+				Print(screen, err.Error(), fieldX, y+itemHeight, x+itemWidth-fieldX, AlignLeft, f.errorColor)
+			}
+		}
+
 		// Advance to next item.
 		if f.horizontal {
 			x += itemWidth + f.itemPadding
 		} else {
-			y += 1 + f.itemPadding
+			y += itemHeight + f.itemPadding
+			if err != nil && f.itemPadding == 0 {
+				y++ // Make room for the error message.
+			}
 		}
 	}
 
@@ -393,6 +688,7 @@ func (f *Form) Draw(screen tcell.Screen) {
 
 // Focus is called by the application when the primitive receives focus.
 func (f *Form) Focus(delegate func(p Primitive)) {
+	f.setFocus = delegate
 	if len(f.items)+len(f.buttons) == 0 {
 		return
 	}
@@ -426,6 +722,13 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 		// We're selecting an item.
 		item := f.items[f.focusedElement]
 		item.SetFinishedFunc(handler)
+		if notifier, ok := item.(blurNotifier); ok {
+			// Validate the item when the user leaves it, be it with the
+			// keyboard or the mouse.
+			notifier.setBlurredFunc(func() {
+				f.itemBlurred(item)
+			})
+		}
 		delegate(item)
 	} else {
 		// We're selecting a button.
@@ -435,6 +738,90 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// blurNotifier is implemented by primitives which can report that they lost
+// focus, i.e. all primitives based on Box.
+type blurNotifier interface {
+	setBlurredFunc(handler func())
+}
+
+// itemBlurred is called when the given form item lost focus. Unless the focus
+// merely moved within the item (e.g. to the list of a drop-down), the item is
+// validated.
+func (f *Form) itemBlurred(item FormItem) {
+	if item.GetFocusable().HasFocus() {
+		return
+	}
+	for index, formItem := range f.items {
+		if formItem == item {
+			f.validateItem(index)
+			return
+		}
+	}
+}
+
+// MouseHandler returns the mouse handler for this primitive. Mouse events are
+// passed on to the form's items and buttons. Clicking an item or a button
+// gives it the focus and makes it the form's current element so keyboard
+// navigation continues from there.
+func (f *Form) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// focusElement returns a focus function for the element with the given
+		// index which routes focus requests for the element itself through the
+		// form.
+		focusElement := func(index int, element Primitive) func(p Primitive) {
+			return func(p Primitive) {
+				if p != element {
+					setFocus(p)
+					return
+				}
+				f.focusedElement = index
+				f.Focus(setFocus)
+			}
+		}
+
+		// Pass mouse events along to the first element that takes it.
+		for index, item := range f.items {
+			handler := item.MouseHandler()
+			if handler == nil {
+				continue
+			}
+			consumed, capture = handler(action, event, focusElement(index, item))
+			if consumed {
+				return
+			}
+		}
+		for index, button := range f.buttons {
+			handler := button.MouseHandler()
+			if handler == nil {
+				continue
+			}
+			consumed, capture = handler(action, event, focusElement(len(f.items)+index, button))
+			if consumed {
+				return
+			}
+		}
+
+		// Clicks on the form's background don't go anywhere else.
+		if action == MouseLeftDown && f.InRect(event.Position()) {
+			consumed = true
+		}
+
+		return
+	})
+}
+
+// Children returns the form's items followed by its buttons.
+func (f *Form) Children() []Primitive {
+	children := make([]Primitive, 0, len(f.items)+len(f.buttons))
+	for _, item := range f.items {
+		children = append(children, item)
+	}
+	for _, button := range f.buttons {
+		children = append(children, button)
+	}
+	return children
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (f *Form) HasFocus() bool {
 	for _, item := range f.items {
@@ -0,0 +1,96 @@
+package tview
+
+import (
+	"errors"
+	"testing"
+)
+
+// newSubmitForm returns a form with a required name field and a checkbox
+// whose values are validated by the given submit function.
+func newSubmitForm(submit func(values map[string]interface{}) map[string]error) (*Form, *InputField, *Checkbox) {
+	name := NewInputField().SetLabel("Name")
+	name.SetName("name")
+	agree := NewCheckbox().SetLabel("Agree")
+	agree.SetName("agree")
+	form := NewForm().
+		AddFormItem(name).
+		AddFormItem(agree).
+		SetItemValidator(0, func(value interface{}) error {
+			if value.(string) == "" {
+				return errors.New("required")
+			}
+			return nil
+		}).
+		SetSubmitFunc(submit)
+	return form, name, agree
+}
+
+func TestFormValues(t *testing.T) {
+	form, name, agree := newSubmitForm(nil)
+	name.SetText("bob")
+	agree.SetChecked(true)
+
+	values := form.GetValues()
+	if values["name"] != "bob" || values["agree"] != true {
+		t.Errorf("GetValues() = %v", values)
+	}
+	if err := form.SetValues(map[string]interface{}{"name": "ann", "agree": false}); err != nil {
+		t.Fatal(err)
+	}
+	if name.GetText() != "ann" || agree.IsChecked() {
+		t.Errorf("SetValues() did not change the items")
+	}
+	if err := form.SetValues(map[string]interface{}{"agree": "yes"}); err == nil {
+		t.Error("SetValues() accepted a value of the wrong type")
+	}
+}
+
+func TestFormValidate(t *testing.T) {
+	var submitted bool
+	form, name, _ := newSubmitForm(func(values map[string]interface{}) map[string]error {
+		submitted = true
+		return nil
+	})
+
+	if form.Submit() || submitted {
+		t.Fatal("form with an empty required field was submitted")
+	}
+	if form.Validate() != 0 || form.GetItemError(0) == nil {
+		t.Errorf("empty required field is valid")
+	}
+	name.SetText("bob")
+	if !form.Submit() || !submitted {
+		t.Error("valid form was not submitted")
+	}
+	if form.GetItemError(0) != nil {
+		t.Errorf("valid field has error %v", form.GetItemError(0))
+	}
+}
+
+func TestFormSubmitErrors(t *testing.T) {
+	var submissions int
+	form, name, agree := newSubmitForm(func(values map[string]interface{}) map[string]error {
+		submissions++
+		if values["agree"] != true {
+			return map[string]error{"agree": errors.New("must agree")}
+		}
+		return nil
+	})
+	name.SetText("bob")
+
+	if form.Submit() {
+		t.Fatal("form rejected by the submit function was submitted")
+	}
+	if err := form.GetItemError(1); err == nil || err.Error() != "must agree" {
+		t.Errorf("checkbox has error %v, want the submit error", err)
+	}
+
+	// The error from the submit function must not prevent another submission.
+	agree.SetChecked(true)
+	if !form.Submit() || submissions != 2 {
+		t.Errorf("corrected form was not submitted again (%d submissions)", submissions)
+	}
+	if err := form.GetItemError(1); err != nil {
+		t.Errorf("checkbox still has error %v", err)
+	}
+}
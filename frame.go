@@ -136,8 +136,8 @@ func (f *Frame) Draw(screen tcell.Screen) {
 	if bottomMin < bottom {
 		bottom = bottomMin - f.footer
 	}
-	if top > bottom {
-		return // No space for the primitive.
+	if top > bottom || f.primitive == nil {
+		return // No space for the primitive or nothing to draw.
 	}
 	f.primitive.SetRect(x, top, width, bottom+1-top)
 
@@ -145,16 +145,56 @@ func (f *Frame) Draw(screen tcell.Screen) {
 	f.primitive.Draw(screen)
 }
 
+// Children returns the contained primitive.
+func (f *Frame) Children() []Primitive {
+	if f.primitive == nil {
+		return nil
+	}
+	return []Primitive{f.primitive}
+}
+
 // Focus is called when this primitive receives focus.
 func (f *Frame) Focus(delegate func(p Primitive)) {
+	if f.primitive == nil {
+		f.Box.Focus(delegate)
+		return
+	}
 	delegate(f.primitive)
 }
 
+// MouseHandler returns the mouse handler for this primitive. Mouse events are
+// passed on to the contained primitive.
+func (f *Frame) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !f.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events on to the contained primitive.
+		if f.primitive != nil {
+			if handler := f.primitive.MouseHandler(); handler != nil {
+				consumed, capture = handler(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+
+		// Clicking on the frame itself focuses the contained primitive.
+		if action == MouseLeftDown {
+			setFocus(f)
+			consumed = true
+		}
+
+		return
+	})
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (f *Frame) HasFocus() bool {
 	focusable, ok := f.primitive.(Focusable)
 	if ok {
 		return focusable.HasFocus()
 	}
-	return false
+	return f.Box.HasFocus()
 }
@@ -0,0 +1,177 @@
+package tview
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// History is implemented by stores which keep the texts entered into an input
+// field so they can be recalled later (see InputField.SetHistory()).
+type History interface {
+	// Entries returns the entries of the history, oldest first.
+	Entries() ([]string, error)
+
+	// Add adds an entry to the end of the history.
+	Add(entry string) error
+}
+
+// MemoryHistory is a History which keeps its entries in memory. Entries are
+// lost when the application exits.
+type MemoryHistory struct {
+	sync.Mutex
+
+	// The entries, oldest first.
+	entries []string
+
+	// The maximum number of entries. A value of 0 means no limit.
+	maxEntries int
+}
+
+// NewMemoryHistory returns a new, empty in-memory history which keeps up to
+// the given number of entries, dropping the oldest entries when it is full. A
+// value of 0 means no limit.
+func NewMemoryHistory(maxEntries int) *MemoryHistory {
+	return &MemoryHistory{maxEntries: maxEntries}
+}
+
+// Entries returns the entries of the history, oldest first.
+func (h *MemoryHistory) Entries() ([]string, error) {
+	h.Lock()
+	defer h.Unlock()
+	return append([]string(nil), h.entries...), nil
+}
+
+// Add adds an entry to the end of the history unless it is the same as the
+// last entry.
+func (h *MemoryHistory) Add(entry string) error {
+	h.Lock()
+	defer h.Unlock()
+	h.entries = addHistoryEntry(h.entries, entry, h.maxEntries)
+	return nil
+}
+
+// FileHistory is a History which keeps its entries in a text file, one entry
+// per line, so they are available the next time the application is started.
+// Line breaks in entries are replaced with spaces.
+type FileHistory struct {
+	sync.Mutex
+
+	// The path of the history file.
+	path string
+
+	// The maximum number of entries. A value of 0 means no limit.
+	maxEntries int
+
+	// The entries, oldest first, or nil if the file was not read yet.
+	entries []string
+}
+
+// NewFileHistory returns a history which is stored in the file with the given
+// path. The file is created when the first entry is added. Up to the given
+// number of entries are kept, dropping the oldest entries when the history is
+// full. A value of 0 means no limit.
+func NewFileHistory(path string, maxEntries int) *FileHistory {
+	return &FileHistory{
+		path:       path,
+		maxEntries: maxEntries,
+	}
+}
+
+// Entries returns the entries of the history, oldest first.
+func (h *FileHistory) Entries() ([]string, error) {
+	h.Lock()
+	defer h.Unlock()
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	return append([]string(nil), h.entries...), nil
+}
+
+// Add adds an entry to the end of the history unless it is the same as the
+// last entry. The history file is updated immediately. If that fails, the
+// entry is not added.
+func (h *FileHistory) Add(entry string) error {
+	h.Lock()
+	defer h.Unlock()
+	if err := h.load(); err != nil {
+		return err
+	}
+	entry = strings.Replace(strings.Replace(entry, "\r", " ", -1), "\n", " ", -1)
+	entries := addHistoryEntry(h.entries, entry, h.maxEntries)
+	switch {
+	case len(entries) > len(h.entries):
+		// The entry was appended.
+		if err := h.append(entry); err != nil {
+			return err
+		}
+	case len(h.entries) > 0 && entries[len(entries)-1] != h.entries[len(h.entries)-1]:
+		// Old entries were dropped, write the entire file.
+		if err := h.save(entries); err != nil {
+			return err
+		}
+	}
+	h.entries = entries
+	return nil
+}
+
+// load reads the history file if it was not read yet. A missing file is an
+// empty history.
+func (h *FileHistory) load() error {
+	if h.entries != nil {
+		return nil
+	}
+	file, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		h.entries = []string{}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+	entries := []string{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		entries = addHistoryEntry(entries, scanner.Text(), h.maxEntries)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	h.entries = entries
+	return nil
+}
+
+// append adds an entry to the end of the history file.
+func (h *FileHistory) append(entry string) error {
+	file, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(entry + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// save replaces the contents of the history file with the given entries.
+func (h *FileHistory) save(entries []string) error {
+	return ioutil.WriteFile(h.path, []byte(strings.Join(entries, "\n")+"\n"), 0600)
+}
+
+// addHistoryEntry appends an entry to the given entries unless it is empty or
+// the same as the last entry and drops the oldest entries if there are more
+// than "maxEntries" (unless that is 0). The new entries are returned.
+func addHistoryEntry(entries []string, entry string, maxEntries int) []string {
+	if entry == "" || len(entries) > 0 && entries[len(entries)-1] == entry {
+		return entries
+	}
+	entries = append(entries, entry)
+	if maxEntries > 0 && len(entries) > maxEntries {
+		entries = append([]string(nil), entries[len(entries)-maxEntries:]...)
+	}
+	return entries
+}
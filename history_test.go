@@ -0,0 +1,173 @@
+package tview
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestMemoryHistory(t *testing.T) {
+	for _, test := range []struct {
+		maxEntries int
+		add        []string
+		entries    []string
+	}{
+		{0, nil, nil},
+		{0, []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{0, []string{"a", "", "b", "b", "a"}, []string{"a", "b", "a"}},
+		{2, []string{"a", "b", "c"}, []string{"b", "c"}},
+		{2, []string{"a", "b", "b"}, []string{"a", "b"}},
+	} {
+		history := NewMemoryHistory(test.maxEntries)
+		for _, entry := range test.add {
+			if err := history.Add(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		entries, err := history.Entries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(entries, test.entries) {
+			t.Errorf("%d entries of %q: got %q, want %q", test.maxEntries, test.add, entries, test.entries)
+		}
+	}
+}
+
+func TestFileHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history")
+
+	// entries checks the entries of the history and the lines of its file.
+	entries := func(history History, want ...string) {
+		t.Helper()
+		entries, err := history.Entries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(entries, want) {
+			t.Errorf("history has entries %q, want %q", entries, want)
+		}
+		file, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var text string
+		for _, entry := range want {
+			text += entry + "\n"
+		}
+		if string(file) != text {
+			t.Errorf("history file contains %q, want %q", file, text)
+		}
+	}
+
+	history := NewFileHistory(path, 3)
+	if entries, err := history.Entries(); err != nil || len(entries) != 0 {
+		t.Fatalf("history without a file has entries %q (error %v)", entries, err)
+	}
+	for _, entry := range []string{"one", "two\nlines", "two\nlines", "", "three\r\n"} {
+		if err := history.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries(history, "one", "two lines", "three  ")
+
+	// Dropping old entries rewrites the file.
+	history.Add("four")
+	entries(history, "two lines", "three  ", "four")
+
+	// Another history reads the file, keeping up to its number of entries.
+	reloaded := NewFileHistory(path, 2)
+	if err := reloaded.Add("four"); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := reloaded.Entries(); !reflect.DeepEqual(entries, []string{"three  ", "four"}) {
+		t.Errorf("reloaded history has entries %q", entries)
+	}
+	reloaded.Add("five")
+	entries(reloaded, "four", "five")
+}
+
+func TestFileHistoryWriteError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The file cannot be created.
+	history := NewFileHistory(filepath.Join(dir, "missing", "history"), 0)
+	if err := history.Add("one"); err == nil {
+		t.Error("no error adding an entry to a file which cannot be created")
+	}
+	if entries, _ := history.Entries(); len(entries) != 0 {
+		t.Errorf("entry was added although it was not written: %q", entries)
+	}
+
+	// The file cannot be rewritten.
+	history = NewFileHistory(filepath.Join(dir, "history"), 1)
+	if err := history.Add("one"); err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(dir)
+	if err := history.Add("two"); err == nil {
+		t.Error("no error rewriting a file in a removed directory")
+	}
+	if entries, _ := history.Entries(); !reflect.DeepEqual(entries, []string{"one"}) {
+		t.Errorf("history has entries %q after a failed write, want [one]", entries)
+	}
+}
+
+func TestInputFieldHistory(t *testing.T) {
+	history := NewMemoryHistory(0)
+	input := NewInputField().SetHistory(history)
+	handler := input.InputHandler()
+	key := func(key tcell.Key, ch rune) {
+		handler(tcell.NewEventKey(key, ch, tcell.ModNone), func(p Primitive) {})
+	}
+	enter := func(text string) {
+		input.SetText("")
+		for _, ch := range text {
+			key(tcell.KeyRune, ch)
+		}
+		key(tcell.KeyEnter, 0)
+	}
+
+	// Without entries, the arrow keys do nothing.
+	key(tcell.KeyUp, 0)
+	if text := input.GetText(); text != "" {
+		t.Errorf("empty history recalled %q", text)
+	}
+
+	enter("one")
+	enter("two")
+	if entries, _ := history.Entries(); !reflect.DeepEqual(entries, []string{"one", "two"}) {
+		t.Fatalf("entering texts added %q to the history", entries)
+	}
+
+	input.SetText("draft")
+	for _, step := range []struct {
+		key  tcell.Key
+		text string
+	}{
+		{tcell.KeyUp, "two"},
+		{tcell.KeyUp, "one"},
+		{tcell.KeyUp, "one"},
+		{tcell.KeyDown, "two"},
+		{tcell.KeyDown, "draft"},
+		{tcell.KeyDown, "draft"},
+	} {
+		key(step.key, 0)
+		if text := input.GetText(); text != step.text {
+			t.Errorf("key %d recalled %q, want %q", step.key, text, step.text)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package tview
 
 import (
+	"context"
+	"fmt"
 	"math"
-	"regexp"
+	"reflect"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell"
@@ -11,11 +14,34 @@ import (
 )
 
 // InputField is a one-line box (three lines if there is a title) where the
-// user can enter text.
+// user can enter text. The following keys are available to edit the text:
+//
+//   - Left / right arrow: Move the cursor.
+//   - Ctrl-left / ctrl-right arrow: Move the cursor to the previous / next word.
+//   - Home, Ctrl-A / End, Ctrl-E: Move the cursor to the start / end of the text.
+//   - Backspace / Delete, Ctrl-D: Delete the character before / after the
+//     cursor.
+//   - Ctrl-K / Ctrl-U: Delete from the cursor to the end / start of the text.
+//   - Ctrl-W: Delete the word before the cursor.
+//
+// Text which is longer than the input area is scrolled horizontally to keep the
+// cursor visible.
 //
 // Use SetMaskCharacter() to hide input from onlookers (e.g. for password
 // input).
 //
+// Use SetHistory() to let the user recall previously entered texts with the up
+// and down arrows. Texts are added to the history when Enter is pressed.
+// Ctrl-R starts a reverse incremental search through the history: typed
+// characters are added to the search query, Ctrl-R moves to the next older
+// match, Escape or Ctrl-G abort the search, and any other key accepts the
+// match.
+//
+// Use SetAutocompleteFunc() to suggest entries while the user is typing. The
+// suggestions are shown in a list below the input field which is navigated with
+// the up and down arrows. Tab and Enter accept the selected entry, Escape
+// closes the list.
+//
 // See https://github.com/rivo/tview/wiki/InputField for an example.
 type InputField struct {
 	*Box
@@ -23,12 +49,21 @@ type InputField struct {
 	// The text that was entered.
 	text string
 
+	// The cursor position as a byte index into the text.
+	cursorPos int
+
+	// The byte index of the first character shown in the input area.
+	offset int
+
 	// The text to be displayed before the input area.
 	label string
 
 	// The label color.
 	labelColor tcell.Color
 
+	// The text attributes of the label.
+	labelAttributes tcell.AttrMask
+
 	// The background color of the input area.
 	fieldBackgroundColor tcell.Color
 
@@ -53,16 +88,63 @@ type InputField struct {
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, enter, or escape).
 	done func(tcell.Key)
+
+	// An optional function which provides autocomplete suggestions for the
+	// entered text.
+	autocomplete func(ctx context.Context, text string, suggest func(entries []string))
+
+	// Cancels the context of the last call to the autocomplete function, nil
+	// if there was none.
+	autocompleteCancel func()
+
+	// The list of autocomplete suggestions. It is only shown if it has items.
+	autocompleteList *List
+
+	// The history of entered texts, nil for none.
+	history History
+
+	// The history entries while the history is navigated or searched, nil
+	// otherwise.
+	historyEntries []string
+
+	// The index of the history entry which is shown. The length of the entries
+	// refers to the text which was being edited when navigation started.
+	historyIndex int
+
+	// The text which was being edited when navigating or searching the history
+	// started.
+	historyDraft string
+
+	// Whether or not a reverse history search is active and the search query.
+	historySearch bool
+	historyQuery  string
+
+	// Whether or not the last history search found no match.
+	historySearchFailed bool
 }
 
 // NewInputField returns a new input field.
 func NewInputField() *InputField {
-	return &InputField{
+	i := &InputField{
 		Box:                  NewBox(),
 		labelColor:           Styles.SecondaryTextColor,
+		labelAttributes:      Styles.LabelAttributes,
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
+		autocompleteList:     NewList().ShowSecondaryText(false),
 	}
+	i.autocompleteList.SetMainTextColor(Styles.PrimitiveBackgroundColor).
+		SetSelectedTextColor(Styles.PrimitiveBackgroundColor).
+		SetSelectedBackgroundColor(Styles.PrimaryTextColor).
+		SetBackgroundColor(Styles.MoreContrastBackgroundColor)
+	i.DeclareProps(PropSpec{
+		Name:    "text",
+		Type:    reflect.TypeOf(""),
+		Default: "",
+		Get:     func() interface{} { return i.GetText() },
+		Set:     func(value interface{}) { i.SetText(value.(string)) },
+	})
+	return i
 }
 
 func (i *InputField) GetValues() map[string]interface{} {
@@ -71,12 +153,32 @@ func (i *InputField) GetValues() map[string]interface{} {
 	}
 }
 
-// SetText sets the current text of the input field.
+// SetValue sets the text of the input field. The value must be a string.
+func (i *InputField) SetValue(value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	i.SetText(text)
+	return nil
+}
+
+// SetText sets the current text of the input field and moves the cursor to its
+// end. Subscribers of the "text" prop are notified if the text changed.
 func (i *InputField) SetText(text string) *InputField {
 	i.Lock()
+	previous := i.text
 	i.text = text
+	i.cursorPos = len(text)
+	i.offset = 0
+	i.closeAutocomplete()
+	i.historyEntries = nil
+	i.historySearch = false
 	i.Unlock()
 
+	if text != previous {
+		i.notifyProp("text", text)
+	}
 	if i.changed != nil {
 		i.changed(text)
 	}
@@ -117,6 +219,16 @@ func (i *InputField) SetLabelColor(color tcell.Color) *InputField {
 	return i
 }
 
+// SetLabelAttributes sets the text attributes of the label, e.g.
+// tcell.AttrBold.
+func (i *InputField) SetLabelAttributes(attributes tcell.AttrMask) *InputField {
+	i.Lock()
+	defer i.Unlock()
+
+	i.labelAttributes = attributes
+	return i
+}
+
 // SetFieldBackgroundColor sets the background color of the input area.
 func (i *InputField) SetFieldBackgroundColor(color tcell.Color) *InputField {
 	i.Lock()
@@ -135,6 +247,23 @@ func (i *InputField) SetFieldTextColor(color tcell.Color) *InputField {
 	return i
 }
 
+// ApplyTheme sets the colors and attributes of the input field to those of the
+// given theme.
+func (i *InputField) ApplyTheme(theme *Theme) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.Box.ApplyTheme(theme)
+	i.labelColor = theme.SecondaryTextColor
+	i.labelAttributes = theme.LabelAttributes
+	i.fieldBackgroundColor = theme.ContrastBackgroundColor
+	i.fieldTextColor = theme.PrimaryTextColor
+	i.autocompleteList.SetMainTextColor(theme.PrimitiveBackgroundColor).
+		SetSelectedTextColor(theme.PrimitiveBackgroundColor).
+		SetSelectedBackgroundColor(theme.PrimaryTextColor).
+		SetBackgroundColor(theme.MoreContrastBackgroundColor)
+}
+
 // SetFormAttributes sets attributes shared by all form items.
 func (i *InputField) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
 	i.Lock()
@@ -199,6 +328,170 @@ func (i *InputField) SetChangedFunc(handler func(text string)) *InputField {
 	return i
 }
 
+// SetAutocompleteFunc sets a function which provides autocomplete suggestions
+// for the text entered by the user. It is called whenever the user changes the
+// text and receives the current text and a function which is called with the
+// suggested entries. An empty list of entries closes the list of suggestions.
+//
+// The suggestions may be provided asynchronously. If the text changes before
+// they are ready, the context is canceled and suggestions provided for it
+// later on are ignored. As with any other change to a primitive, the function
+// to suggest entries must then be called from the application's event loop:
+//
+//   inputField.SetAutocompleteFunc(func(ctx context.Context, text string, suggest func(entries []string)) {
+//     go func() {
+//       entries := lookupHosts(ctx, text)
+//       app.QueueUpdateDraw(func() {
+//         suggest(entries)
+//       })
+//     }()
+//   })
+//
+// Set the function to nil to turn off autocompletion.
+func (i *InputField) SetAutocompleteFunc(callback func(ctx context.Context, text string, suggest func(entries []string))) *InputField {
+	i.Lock()
+	defer i.Unlock()
+
+	i.closeAutocomplete()
+	i.autocomplete = callback
+	return i
+}
+
+// Autocomplete calls the autocomplete function (see SetAutocompleteFunc()) for
+// the current text, e.g. to show suggestions before the user starts typing.
+func (i *InputField) Autocomplete() *InputField {
+	i.startAutocomplete()
+	return i
+}
+
+// startAutocomplete cancels the previous call to the autocomplete function and
+// calls it for the current text.
+func (i *InputField) startAutocomplete() {
+	i.closeAutocomplete()
+	if i.autocomplete == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	i.autocompleteCancel = cancel
+	i.autocomplete(ctx, i.text, func(entries []string) {
+		if ctx.Err() != nil {
+			return // The text has changed in the meantime.
+		}
+		i.autocompleteList.Clear()
+		for _, entry := range entries {
+			i.autocompleteList.AddItem(entry, "", 0, nil)
+		}
+		i.autocompleteList.SetCurrentItem(0)
+	})
+}
+
+// closeAutocomplete cancels the last call to the autocomplete function and
+// closes the list of suggestions.
+func (i *InputField) closeAutocomplete() {
+	if i.autocompleteCancel != nil {
+		i.autocompleteCancel()
+		i.autocompleteCancel = nil
+	}
+	i.autocompleteList.Clear()
+}
+
+// SetHistory sets the history from which previously entered texts are recalled
+// and to which texts are added when the user presses Enter. A nil value (the
+// default) turns the history off. See NewMemoryHistory() and NewFileHistory()
+// for implementations.
+func (i *InputField) SetHistory(history History) *InputField {
+	i.Lock()
+	defer i.Unlock()
+
+	i.history = history
+	i.historyEntries = nil
+	i.historySearch = false
+	return i
+}
+
+// startHistory loads the history entries, if they are not loaded yet, and
+// returns whether or not there are any.
+func (i *InputField) startHistory() bool {
+	if i.history == nil {
+		return false
+	}
+	if i.historyEntries == nil {
+		entries, err := i.history.Entries()
+		if err != nil || len(entries) == 0 {
+			return false
+		}
+		i.historyEntries = entries
+		i.historyIndex = len(entries)
+		i.historyDraft = i.text
+	}
+	return true
+}
+
+// recallHistory shows the history entry with the given index, or the text
+// which was being edited if the index is the number of entries.
+func (i *InputField) recallHistory(index int) {
+	if index < 0 || index > len(i.historyEntries) {
+		return
+	}
+	i.historyIndex = index
+	if index == len(i.historyEntries) {
+		i.text = i.historyDraft
+	} else {
+		i.text = i.historyEntries[index]
+	}
+	i.cursorPos = len(i.text)
+}
+
+// searchHistory searches the history entries for the search query, starting at
+// the given index and moving towards older entries. The first match is shown.
+func (i *InputField) searchHistory(index int) {
+	i.historySearchFailed = false
+	if i.historyQuery == "" {
+		return
+	}
+	if index >= len(i.historyEntries) {
+		index = len(i.historyEntries) - 1
+	}
+	for ; index >= 0; index-- {
+		if pos := strings.LastIndex(i.historyEntries[index], i.historyQuery); pos >= 0 {
+			i.historyIndex = index
+			i.text = i.historyEntries[index]
+			i.cursorPos = pos
+			return
+		}
+	}
+	i.historySearchFailed = true
+}
+
+// historyInput handles key events while the history is searched. It returns
+// true if the event was consumed. Otherwise, the search has ended with the
+// current match and the event is to be processed as usual.
+func (i *InputField) historyInput(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyCtrlR: // Next older match.
+		i.searchHistory(i.historyIndex - 1)
+	case tcell.KeyRune:
+		i.historyQuery += string(event.Rune())
+		i.searchHistory(i.historyIndex)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if i.historyQuery != "" {
+			_, size := utf8.DecodeLastRuneInString(i.historyQuery)
+			i.historyQuery = i.historyQuery[:len(i.historyQuery)-size]
+			i.searchHistory(len(i.historyEntries))
+		}
+	case tcell.KeyEscape, tcell.KeyCtrlG: // Abort the search.
+		i.text = i.historyDraft
+		i.cursorPos = len(i.text)
+		i.historySearch = false
+		i.historyEntries = nil
+	default: // Accept the match.
+		i.historySearch = false
+		i.historyEntries = nil
+		return false
+	}
+	return true
+}
+
 // SetDoneFunc sets a handler which is called when the user is done entering
 // text. The callback function is provided with the key that was pressed, which
 // is one of the following:
@@ -215,6 +508,24 @@ func (i *InputField) SetDoneFunc(handler func(key tcell.Key)) *InputField {
 	return i
 }
 
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the input field with Tab or Backtab.
+func (i *InputField) hasDoneFunc() bool {
+	return i.done != nil
+}
+
+// capturesTextInput returns true as the input field uses all printable
+// characters.
+func (i *InputField) capturesTextInput() bool {
+	return true
+}
+
+// usesTab returns whether the given key (Tab or Backtab) is used by the input
+// field itself, i.e. to accept an autocomplete suggestion.
+func (i *InputField) usesTab(key tcell.Key) bool {
+	return key == tcell.KeyTab && i.autocompleteList.GetItemCount() > 0
+}
+
 // SetFinishedFunc calls SetDoneFunc().
 func (i *InputField) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	return i.SetDoneFunc(handler)
@@ -225,8 +536,9 @@ func (i *InputField) SetFinishedFunction(handler func(key tcell.Key)) {
 
 // Draw draws this primitive onto the screen.
 func (i *InputField) Draw(screen tcell.Screen) {
-	i.RLock()
-	defer i.RUnlock()
+	// Drawing updates the scroll offset, hence the write lock.
+	i.Lock()
+	defer i.Unlock()
 
 	i.Box.Draw(screen)
 
@@ -238,7 +550,7 @@ func (i *InputField) Draw(screen tcell.Screen) {
 	}
 
 	// Draw label.
-	_, drawnWidth := Print(screen, i.label, x, y, rightLimit-x, AlignLeft, i.labelColor)
+	_, drawnWidth := printWithAttributes(screen, i.displayedLabel(), x, y, rightLimit-x, AlignLeft, i.labelColor, i.labelAttributes)
 	x += drawnWidth
 
 	// Draw input area.
@@ -254,70 +566,143 @@ func (i *InputField) Draw(screen tcell.Screen) {
 		screen.SetContent(x+index, y, ' ', nil, fieldStyle)
 	}
 
-	// Draw entered text.
-	text := i.text
-	if i.maskCharacter > 0 {
-		text = strings.Repeat(string(i.maskCharacter), utf8.RuneCountInString(i.text))
-	}
+	// Scroll the text to keep the cursor visible.
 	fieldWidth-- // We need one cell for the cursor.
-	if fieldWidth < runewidth.StringWidth(text) {
-		runes := []rune(text)
-		for pos := len(runes) - 1; pos >= 0; pos-- {
-			ch := runes[pos]
-			w := runewidth.RuneWidth(ch)
-			if fieldWidth-w < 0 {
-				break
-			}
-			_, _, style, _ := screen.GetContent(x+fieldWidth-w, y)
-			style = style.Foreground(i.fieldTextColor)
-			for w > 0 {
-				fieldWidth--
-				screen.SetContent(x+fieldWidth, y, ch, nil, style)
-				w--
-			}
+	if fieldWidth < 0 {
+		fieldWidth = 0
+	}
+	if i.cursorPos > len(i.text) {
+		i.cursorPos = len(i.text)
+	}
+	if i.offset > i.cursorPos {
+		i.offset = i.cursorPos
+	}
+	for i.offset < i.cursorPos && i.textWidth(i.offset, i.cursorPos) > fieldWidth {
+		_, size := utf8.DecodeRuneInString(i.text[i.offset:])
+		i.offset += size
+	}
+	for i.offset > 0 {
+		// Fill the input area if the text was shortened.
+		_, size := utf8.DecodeLastRuneInString(i.text[:i.offset])
+		if i.textWidth(i.offset-size, len(i.text)) > fieldWidth {
+			break
 		}
-	} else {
-		pos := 0
-		for _, ch := range text {
-			w := runewidth.RuneWidth(ch)
-			_, _, style, _ := screen.GetContent(x+pos, y)
-			style = style.Foreground(i.fieldTextColor)
-			for w > 0 {
-				screen.SetContent(x+pos, y, ch, nil, style)
-				pos++
-				w--
-			}
+		i.offset -= size
+	}
+
+	// Draw entered text.
+	pos := 0
+	for _, ch := range i.text[i.offset:] {
+		if i.maskCharacter > 0 {
+			ch = i.maskCharacter
+		}
+		w := runewidth.RuneWidth(ch)
+		if pos+w > fieldWidth+1 {
+			break
+		}
+		_, _, style, _ := screen.GetContent(x+pos, y)
+		style = style.Foreground(i.fieldTextColor)
+		for w > 0 {
+			screen.SetContent(x+pos, y, ch, nil, style)
+			pos++
+			w--
 		}
 	}
 
 	// Set cursor.
 	if i.focus.HasFocus() {
-		i.setCursor(screen)
+		screen.ShowCursor(x+i.textWidth(i.offset, i.cursorPos), y)
+	}
+
+	// Draw the autocomplete suggestions.
+	if i.focus.HasFocus() && i.autocompleteList.GetItemCount() > 0 {
+		i.drawAutocomplete(screen, x, y)
 	}
 }
 
-// setCursor sets the cursor position.
-func (i *InputField) setCursor(screen tcell.Screen) {
-	i.RLock()
-	defer i.RUnlock()
+// drawAutocomplete draws the list of autocomplete suggestions below (or, if
+// there is no space, above) the input area which starts at the given screen
+// position.
+func (i *InputField) drawAutocomplete(screen tcell.Screen, x, y int) {
+	swidth, sheight := screen.Size()
+	lwidth := 0
+	for index := 0; index < i.autocompleteList.GetItemCount(); index++ {
+		main, _ := i.autocompleteList.GetItemText(index)
+		if w := StringWidth(main); w > lwidth {
+			lwidth = w
+		}
+	}
+	if x+lwidth > swidth {
+		lwidth = swidth - x
+	}
+	ly := y + 1
+	lheight := i.autocompleteList.GetItemCount()
+	if ly+lheight > sheight {
+		if y-lheight >= 0 {
+			ly = y - lheight
+		} else if sheight-ly > y {
+			lheight = sheight - ly
+		} else {
+			ly, lheight = 0, y
+		}
+	}
+	i.autocompleteList.SetRect(x, ly, lwidth, lheight)
+	i.autocompleteList.Draw(screen)
+}
 
-	x := i.x
-	y := i.y
-	rightLimit := x + i.width
-	if i.border {
-		x++
-		y++
-		rightLimit -= 2
+// displayedLabel returns the label shown before the input area, which is the
+// search prompt while the history is searched.
+func (i *InputField) displayedLabel() string {
+	if !i.historySearch {
+		return i.label
 	}
-	fieldWidth := runewidth.StringWidth(i.text)
-	if i.fieldWidth > 0 && fieldWidth > i.fieldWidth-1 {
-		fieldWidth = i.fieldWidth - 1
+	prompt := "(reverse-i-search)"
+	if i.historySearchFailed {
+		prompt = "(failed reverse-i-search)"
 	}
-	x += StringWidth(i.label) + fieldWidth
-	if x >= rightLimit {
-		x = rightLimit - 1
+	return fmt.Sprintf("%s'%s': ", prompt, Escape(i.historyQuery))
+}
+
+// textWidth returns the screen width of the text between the given byte
+// positions, as it is shown in the input area.
+func (i *InputField) textWidth(from, to int) int {
+	if i.maskCharacter > 0 {
+		return utf8.RuneCountInString(i.text[from:to]) * runewidth.RuneWidth(i.maskCharacter)
+	}
+	return runewidth.StringWidth(i.text[from:to])
+}
+
+// positionAt returns the byte position of the character shown at the given
+// screen position, or the end of the text if there is no character.
+func (i *InputField) positionAt(x, y int) int {
+	innerX, _, _, _ := i.GetInnerRect()
+	column := x - innerX - StringWidth(i.displayedLabel())
+	if column < 0 || i.offset > len(i.text) {
+		return i.offset
+	}
+	for pos := i.offset; pos < len(i.text); {
+		_, size := utf8.DecodeRuneInString(i.text[pos:])
+		if i.textWidth(i.offset, pos+size) > column {
+			return pos
+		}
+		pos += size
+	}
+	return len(i.text)
+}
+
+// wordStart returns the byte position of the start of the word before the
+// cursor.
+func (i *InputField) wordStart() int {
+	return strings.LastIndexFunc(strings.TrimRightFunc(i.text[:i.cursorPos], unicode.IsSpace), unicode.IsSpace) + 1
+}
+
+// wordEnd returns the byte position of the end of the word after the cursor.
+func (i *InputField) wordEnd() int {
+	text := strings.TrimLeftFunc(i.text[i.cursorPos:], unicode.IsSpace)
+	if index := strings.IndexFunc(text, unicode.IsSpace); index >= 0 {
+		return len(i.text) - len(text) + index
 	}
-	screen.ShowCursor(x, y)
+	return len(i.text)
 }
 
 // InputHandler returns the handler for this primitive.
@@ -328,6 +713,9 @@ func (i *InputField) InputHandler() func(tcell.Event, func(Primitive)) {
 			// Trigger changed events.
 			currentText := i.text
 			defer func() {
+				if i.text != currentText {
+					i.notifyProp("text", i.text)
+				}
 				if i.text != currentText && i.changed != nil {
 					//i.Lock()
 					//defer i.Unlock()
@@ -335,28 +723,115 @@ func (i *InputField) InputHandler() func(tcell.Event, func(Primitive)) {
 				}
 			}()
 
+			// Navigate the autocomplete suggestions.
+			if i.autocompleteList.GetItemCount() > 0 {
+				switch evt.Key() {
+				case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+					i.autocompleteList.InputHandler()(evt, setFocus)
+					return
+				case tcell.KeyTab, tcell.KeyEnter:
+					main, _ := i.autocompleteList.GetItemText(i.autocompleteList.GetCurrentItem())
+					i.text = main
+					i.cursorPos = len(main)
+					i.closeAutocomplete()
+					return
+				case tcell.KeyEscape:
+					i.closeAutocomplete()
+					return
+				}
+			}
+
+			// Navigate and search the history.
+			if i.historySearch && i.historyInput(evt) {
+				return
+			}
+			switch evt.Key() {
+			case tcell.KeyUp:
+				if i.startHistory() {
+					i.recallHistory(i.historyIndex - 1)
+				}
+				return
+			case tcell.KeyDown:
+				if i.historyEntries != nil {
+					i.recallHistory(i.historyIndex + 1)
+				}
+				return
+			case tcell.KeyCtrlR:
+				if i.startHistory() {
+					i.historyIndex = len(i.historyEntries)
+					i.historyDraft = i.text
+					i.historySearch = true
+					i.historyQuery = ""
+					i.historySearchFailed = false
+				}
+				return
+			}
+			i.historyEntries = nil
+
+			// Update the autocomplete suggestions if the text was edited.
+			defer func() {
+				if i.text != currentText {
+					i.startAutocomplete()
+				}
+			}()
+
 			// Process key evt.
+			word := evt.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) != 0
 			switch key := evt.Key(); key {
 			case tcell.KeyRune: // Regular character.
-				newText := i.text + string(evt.Rune())
+				ch := string(evt.Rune())
+				newText := i.text[:i.cursorPos] + ch + i.text[i.cursorPos:]
 				if i.accept != nil {
 					if !i.accept(newText, evt.Rune()) {
 						break
 					}
 				}
 				i.text = newText
-			case tcell.KeyCtrlU: // Delete all.
-				i.text = ""
-			case tcell.KeyCtrlW: // Delete last word.
-				lastWord := regexp.MustCompile(`\s*\S+\s*$`)
-				i.text = lastWord.ReplaceAllString(i.text, "")
-			case tcell.KeyBackspace, tcell.KeyBackspace2: // Delete last character.
-				if len(i.text) == 0 {
+				i.cursorPos += len(ch)
+			case tcell.KeyLeft: // Move the cursor to the previous character or word.
+				if word {
+					i.cursorPos = i.wordStart()
+				} else if i.cursorPos > 0 {
+					_, size := utf8.DecodeLastRuneInString(i.text[:i.cursorPos])
+					i.cursorPos -= size
+				}
+			case tcell.KeyRight: // Move the cursor to the next character or word.
+				if word {
+					i.cursorPos = i.wordEnd()
+				} else if i.cursorPos < len(i.text) {
+					_, size := utf8.DecodeRuneInString(i.text[i.cursorPos:])
+					i.cursorPos += size
+				}
+			case tcell.KeyHome, tcell.KeyCtrlA: // Move the cursor to the start.
+				i.cursorPos = 0
+			case tcell.KeyEnd, tcell.KeyCtrlE: // Move the cursor to the end.
+				i.cursorPos = len(i.text)
+			case tcell.KeyCtrlK: // Delete until the end.
+				i.text = i.text[:i.cursorPos]
+			case tcell.KeyCtrlU: // Delete until the start.
+				i.text = i.text[i.cursorPos:]
+				i.cursorPos = 0
+			case tcell.KeyCtrlW: // Delete the word before the cursor.
+				start := i.wordStart()
+				i.text = i.text[:start] + i.text[i.cursorPos:]
+				i.cursorPos = start
+			case tcell.KeyBackspace, tcell.KeyBackspace2: // Delete the character before the cursor.
+				if i.cursorPos == 0 {
+					break
+				}
+				_, size := utf8.DecodeLastRuneInString(i.text[:i.cursorPos])
+				i.text = i.text[:i.cursorPos-size] + i.text[i.cursorPos:]
+				i.cursorPos -= size
+			case tcell.KeyDelete, tcell.KeyCtrlD: // Delete the character after the cursor.
+				if i.cursorPos == len(i.text) {
 					break
 				}
-				runes := []rune(i.text)
-				i.text = string(runes[:len(runes)-1])
+				_, size := utf8.DecodeRuneInString(i.text[i.cursorPos:])
+				i.text = i.text[:i.cursorPos] + i.text[i.cursorPos+size:]
 			case tcell.KeyEnter, tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+				if key == tcell.KeyEnter && i.history != nil {
+					i.history.Add(i.text)
+				}
 				if i.done != nil {
 					i.done(key)
 				}
@@ -364,3 +839,21 @@ func (i *InputField) InputHandler() func(tcell.Event, func(Primitive)) {
 		}
 	})
 }
+
+// MouseHandler returns the mouse handler for this primitive.
+func (i *InputField) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return i.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !i.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		if action == MouseLeftDown {
+			setFocus(i)
+			i.cursorPos = i.positionAt(event.Position())
+			consumed = true
+		}
+
+		return
+	})
+}
@@ -0,0 +1,29 @@
+package tview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestInputFieldScroll(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	screen.Init()
+	defer screen.Fini()
+	screen.SetSize(10, 1)
+
+	input := NewInputField().SetText("abcdefghijklmnop")
+	input.SetRect(0, 0, 10, 1)
+	input.Draw(screen)
+	screen.Show()
+
+	// The end of the text is shown, followed by the cursor.
+	cells, _, _ := screen.GetContents()
+	var text string
+	for _, cell := range cells {
+		text += string(cell.Runes)
+	}
+	if text != "hijklmnop " {
+		t.Errorf("input field shows %q, want %q", text, "hijklmnop ")
+	}
+}
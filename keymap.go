@@ -0,0 +1,587 @@
+package tview
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// KeyCombo is a key combination, e.g. "ctrl-x" or "g". Key combinations are
+// compared in a normalized form: Shift is part of the rune of printable
+// characters and Ctrl is part of the key of control characters.
+type KeyCombo struct {
+	Key  tcell.Key     // The key, tcell.KeyRune for printable characters.
+	Rune rune          // The printable character if Key is tcell.KeyRune.
+	Mod  tcell.ModMask // The modifier keys.
+}
+
+// NewKeyCombo returns the normalized key combination of the given key, rune,
+// and modifiers, e.g. as reported by a tcell.EventKey.
+func NewKeyCombo(key tcell.Key, ch rune, mod tcell.ModMask) KeyCombo {
+	switch {
+	case key == tcell.KeyRune:
+		mod &^= tcell.ModShift
+	case key <= tcell.KeyUS:
+		mod &^= tcell.ModCtrl
+	}
+	if key != tcell.KeyRune {
+		ch = 0
+	}
+	return KeyCombo{Key: key, Rune: ch, Mod: mod}
+}
+
+// keyNames maps lower-case key names to keys.
+var keyNames = map[string]tcell.Key{
+	"escape":   tcell.KeyEsc,
+	"return":   tcell.KeyEnter,
+	"del":      tcell.KeyDelete,
+	"pgdown":   tcell.KeyPgDn,
+	"pagedown": tcell.KeyPgDn,
+	"pageup":   tcell.KeyPgUp,
+}
+
+func init() {
+	for key, name := range tcell.KeyNames {
+		if !strings.HasPrefix(name, "Ctrl-") {
+			keyNames[strings.ToLower(name)] = key
+		}
+	}
+	// tcell calls 0x08 (i.e. Ctrl-H) "Backspace" but most terminals send 0x7F
+	// for the backspace key.
+	keyNames["backspace"] = tcell.KeyBackspace2
+}
+
+// ParseKeyCombo parses a key combination such as "ctrl-x", "alt-enter", "G",
+// "shift-tab", or "f1". It consists of optional modifiers ("ctrl", "alt",
+// "shift", "meta") and a key, separated by dashes. Keys are either single
+// characters (case-sensitive) or key names (case-insensitive) such as
+// "enter", "esc", "tab", "backspace", "delete", "insert", "home", "end",
+// "pgup", "pgdn", "up", "down", "left", "right", "space", and "f1" to "f64".
+//
+// "backspace" is the key most terminals send for the backspace key (0x7F).
+// Some terminals send Ctrl-H (0x08) instead, which is "ctrl-h". Bind both to
+// catch the backspace key on all terminals.
+func ParseKeyCombo(text string) (KeyCombo, error) {
+	if text == "" {
+		return KeyCombo{}, fmt.Errorf("empty key")
+	}
+	name, mods := text, ""
+	if index := strings.LastIndex(text[:len(text)-1], "-"); len(text) > 1 && index >= 0 {
+		name, mods = text[index+1:], text[:index]
+	}
+	if name == "" {
+		return KeyCombo{}, fmt.Errorf("invalid key %q", text)
+	}
+
+	var mod tcell.ModMask
+	if mods != "" {
+		for _, m := range strings.Split(mods, "-") {
+			switch strings.ToLower(m) {
+			case "ctrl":
+				mod |= tcell.ModCtrl
+			case "alt":
+				mod |= tcell.ModAlt
+			case "shift":
+				mod |= tcell.ModShift
+			case "meta":
+				mod |= tcell.ModMeta
+			default:
+				return KeyCombo{}, fmt.Errorf("invalid modifier %q in key %q", m, text)
+			}
+		}
+	}
+
+	// Single characters.
+	if strings.ToLower(name) == "space" {
+		name = " "
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		ch, _ := utf8.DecodeRuneInString(name)
+		if mod&tcell.ModCtrl != 0 {
+			// Control characters.
+			switch c := unicode.ToLower(ch); {
+			case c >= 'a' && c <= 'z':
+				return NewKeyCombo(tcell.Key(c-'a'+1), 0, mod), nil
+			case c == ' ' || c == '@':
+				return NewKeyCombo(tcell.KeyCtrlSpace, 0, mod), nil
+			case c >= '[' && c <= '_':
+				return NewKeyCombo(tcell.Key(c-'[')+tcell.KeyCtrlLeftSq, 0, mod), nil
+			}
+			return KeyCombo{}, fmt.Errorf("invalid control key %q", text)
+		}
+		if mod&tcell.ModShift != 0 {
+			ch = unicode.ToUpper(ch)
+		}
+		return NewKeyCombo(tcell.KeyRune, ch, mod), nil
+	}
+
+	// Named keys.
+	key, ok := keyNames[strings.ToLower(name)]
+	if !ok {
+		return KeyCombo{}, fmt.Errorf("unknown key %q", text)
+	}
+	if key == tcell.KeyTab && mod&tcell.ModShift != 0 {
+		key, mod = tcell.KeyBacktab, mod&^tcell.ModShift
+	}
+	return NewKeyCombo(key, 0, mod), nil
+}
+
+// ParseKeys parses a sequence of key combinations separated by spaces, e.g.
+// "ctrl-x ctrl-s" or "g g". See ParseKeyCombo() for the format of each key
+// combination.
+func ParseKeys(text string) ([]KeyCombo, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no keys in %q", text)
+	}
+	keys := make([]KeyCombo, len(fields))
+	for index, field := range fields {
+		key, err := ParseKeyCombo(field)
+		if err != nil {
+			return nil, err
+		}
+		keys[index] = key
+	}
+	return keys, nil
+}
+
+// String returns the key combination in the format accepted by
+// ParseKeyCombo().
+func (k KeyCombo) String() string {
+	var prefix string
+	if k.Mod&tcell.ModCtrl != 0 {
+		prefix += "ctrl-"
+	}
+	if k.Mod&tcell.ModAlt != 0 {
+		prefix += "alt-"
+	}
+	if k.Mod&tcell.ModMeta != 0 {
+		prefix += "meta-"
+	}
+	if k.Mod&tcell.ModShift != 0 {
+		prefix += "shift-"
+	}
+
+	switch {
+	case k.Key == tcell.KeyRune && k.Rune == ' ':
+		return prefix + "space"
+	case k.Key == tcell.KeyRune:
+		return prefix + string(k.Rune)
+	case k.Key == tcell.KeyBackspace2:
+		return prefix + "backspace"
+	case k.Key == tcell.KeyTab, k.Key == tcell.KeyEnter, k.Key == tcell.KeyEsc:
+		return prefix + strings.ToLower(tcell.KeyNames[k.Key])
+	case k.Key == tcell.KeyCtrlSpace:
+		return "ctrl-" + prefix + "space"
+	case k.Key >= tcell.KeyCtrlA && k.Key <= tcell.KeyCtrlZ:
+		return "ctrl-" + prefix + string(rune('a'+k.Key-tcell.KeyCtrlA))
+	case k.Key >= tcell.KeyCtrlLeftSq && k.Key <= tcell.KeyUS:
+		return "ctrl-" + prefix + string(rune('['+k.Key-tcell.KeyCtrlLeftSq))
+	}
+	if name, ok := tcell.KeyNames[k.Key]; ok {
+		return prefix + strings.ToLower(name)
+	}
+	return fmt.Sprintf("%skey%d", prefix, k.Key)
+}
+
+// KeyBinding binds a sequence of key combinations to a named action.
+type KeyBinding struct {
+	Name        string     // The action's name.
+	Keys        []KeyCombo // The key combinations to be pressed one after another.
+	Description string     // A description of the action, shown in the help overlay.
+	Scope       Primitive  // The primitive the binding is restricted to, nil for global bindings.
+	Action      func()     // The function to be called when the keys were pressed.
+}
+
+// KeysString returns the binding's keys in the format accepted by
+// ParseKeys().
+func (b *KeyBinding) KeysString() string {
+	keys := make([]string, len(b.Keys))
+	for index, key := range b.Keys {
+		keys[index] = key.String()
+	}
+	return strings.Join(keys, " ")
+}
+
+// Keymap is a registry of key bindings. It is installed with
+// Application.SetKeymap() and then handles key events before they reach the
+// focused primitive (and before an installed FocusManager).
+//
+// A binding may consist of multiple key combinations (a chord) such as
+// "ctrl-x ctrl-s", which need to be pressed one after another. While a chord
+// is being entered, its keys are consumed by the keymap. If a key does not
+// continue any chord, it is handled as if no chord had been started.
+//
+// Bindings may be global or restricted to a scope, i.e. a primitive. Scoped
+// bindings are active only while the focus is on the scope or on one of the
+// primitives it contains. To restrict bindings to a page of Pages, use the
+// page's primitive as the scope. Bindings of more specific scopes (those
+// nested deeper in the primitive tree) take precedence over bindings of
+// enclosing scopes and global bindings with the same keys.
+//
+// Bindings whose first key is a printable character without modifiers are not
+// triggered while an InputField, a TextArea, a TextView with an open search
+// prompt or in keyboard selection mode, or a Table with an open filter prompt
+// has the focus, so that the character reaches it.
+//
+// A help overlay listing the active bindings is shown when the help key (F1 by
+// default) is pressed and closed with the next key.
+type Keymap struct {
+	// The application the keymap is installed in.
+	app *Application
+
+	// All registered bindings.
+	bindings []*KeyBinding
+
+	// The keys of a chord entered so far.
+	pending []KeyCombo
+
+	// The key which shows the help overlay. Its Key is -1 if there is none.
+	helpKey KeyCombo
+
+	// Whether or not the help overlay is visible.
+	helpVisible bool
+
+	// The box drawn around the help overlay.
+	helpBox *Box
+
+	// The focused primitive and the primitives containing it, innermost
+	// first, as found by the last call to focusPath().
+	lastFocusPath []Primitive
+}
+
+// NewKeymap returns a new keymap without any bindings.
+func NewKeymap() *Keymap {
+	helpBox := NewBox().SetBorder(true).SetTitle(" Key Bindings ")
+	helpBox.SetBorderPadding(0, 0, 1, 1)
+	return &Keymap{
+		helpKey: NewKeyCombo(tcell.KeyF1, 0, tcell.ModNone),
+		helpBox: helpBox,
+	}
+}
+
+// Bind adds a global binding of the given keys (see ParseKeys()) to an action
+// with the given name and description. An error is returned if the keys
+// cannot be parsed or if they conflict with another global binding, i.e. if
+// one binding's keys equal or start with the other binding's keys.
+func (k *Keymap) Bind(name, keys, description string, action func()) error {
+	return k.BindScoped(nil, name, keys, description, action)
+}
+
+// BindScoped adds a binding like Bind() which is only active while the focus is
+// on the given scope primitive or on one of the primitives it contains.
+// Conflicts are only checked against bindings of the same scope.
+func (k *Keymap) BindScoped(scope Primitive, name, keys, description string, action func()) error {
+	combos, err := ParseKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	for _, binding := range k.bindings {
+		if binding.Scope != scope {
+			continue
+		}
+		if hasKeyPrefix(binding.Keys, combos) || hasKeyPrefix(combos, binding.Keys) {
+			return fmt.Errorf("keys %q of %q conflict with keys %q of %q", keys, name, binding.KeysString(), binding.Name)
+		}
+	}
+
+	k.bindings = append(k.bindings, &KeyBinding{
+		Name:        name,
+		Keys:        combos,
+		Description: description,
+		Scope:       scope,
+		Action:      action,
+	})
+	return nil
+}
+
+// Unbind removes all bindings of the action with the given name.
+func (k *Keymap) Unbind(name string) *Keymap {
+	bindings := k.bindings[:0]
+	for _, binding := range k.bindings {
+		if binding.Name != name {
+			bindings = append(bindings, binding)
+		}
+	}
+	k.bindings = bindings
+	k.pending = nil
+	return k
+}
+
+// GetBindings returns all bindings in the order they were added.
+func (k *Keymap) GetBindings() []*KeyBinding {
+	return append([]*KeyBinding(nil), k.bindings...)
+}
+
+// GetPending returns the keys of a chord entered so far, or nil if no chord is
+// being entered.
+func (k *Keymap) GetPending() []KeyCombo {
+	return append([]KeyCombo(nil), k.pending...)
+}
+
+// SetHelpKey sets the key combination (see ParseKeyCombo()) which shows the
+// help overlay. Provide an empty string to disable the help key.
+func (k *Keymap) SetHelpKey(key string) error {
+	if key == "" {
+		k.helpKey = KeyCombo{Key: -1}
+		return nil
+	}
+	combo, err := ParseKeyCombo(key)
+	if err != nil {
+		return err
+	}
+	k.helpKey = combo
+	return nil
+}
+
+// ShowHelp shows or hides the help overlay.
+func (k *Keymap) ShowHelp(show bool) *Keymap {
+	k.helpVisible = show
+	return k
+}
+
+// IsHelpVisible returns whether or not the help overlay is visible.
+func (k *Keymap) IsHelpVisible() bool {
+	return k.helpVisible
+}
+
+// ActiveBindings returns the bindings which are currently active, given the
+// application's focus. Bindings of more specific scopes come first. Bindings
+// which are hidden by bindings with the same keys in a more specific scope are
+// omitted.
+func (k *Keymap) ActiveBindings() []*KeyBinding {
+	if k.app == nil {
+		return k.activeBindings(nil, nil)
+	}
+	return k.activeBindings(k.app.GetRoot(), k.app.GetFocus())
+}
+
+// activeBindings returns the active bindings given the root of the primitive
+// tree and the focused primitive.
+func (k *Keymap) activeBindings(root, focus Primitive) []*KeyBinding {
+	// The scopes containing the focus, innermost first.
+	scopes := append(k.focusPath(root, focus), nil) // Global bindings.
+
+	var active []*KeyBinding
+	for _, scope := range scopes {
+	Bindings:
+		for _, binding := range k.bindings {
+			if binding.Scope != scope {
+				continue
+			}
+			for _, other := range active {
+				if hasKeyPrefix(other.Keys, binding.Keys) && len(other.Keys) == len(binding.Keys) {
+					continue Bindings // Hidden.
+				}
+			}
+			active = append(active, binding)
+		}
+	}
+	return active
+}
+
+// focusPath returns the focused primitive and the primitives containing it up
+// to the root, innermost first, or nil if the focus is not part of the tree.
+// The path found last is reused as long as its primitives still contain each
+// other so that the tree is only searched when the focus or the tree changed.
+func (k *Keymap) focusPath(root, focus Primitive) []Primitive {
+	if root == nil || focus == nil {
+		return nil
+	}
+	if path := k.lastFocusPath; len(path) > 0 && path[0] == focus && path[len(path)-1] == root {
+		valid := true
+		for index := 1; index < len(path) && valid; index++ {
+			valid = containsChild(path[index], path[index-1])
+		}
+		if valid {
+			return append([]Primitive(nil), path...)
+		}
+	}
+
+	var path []Primitive
+	var find func(p Primitive) bool
+	find = func(p Primitive) bool {
+		if p == focus {
+			path = append(path, p)
+			return true
+		}
+		if container, ok := p.(Container); ok {
+			for _, child := range container.Children() {
+				if child != nil && find(child) {
+					path = append(path, p)
+					return true
+				}
+			}
+		}
+		return false
+	}
+	find(root)
+	k.lastFocusPath = path
+	return append([]Primitive(nil), path...)
+}
+
+// containsChild returns whether or not the given child is one of the children
+// of the given primitive.
+func containsChild(parent, child Primitive) bool {
+	container, ok := parent.(Container)
+	if !ok {
+		return false
+	}
+	for _, p := range container.Children() {
+		if p == child {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleKey handles the given key event if it is part of an active binding or
+// shows or hides the help overlay, and returns whether it did. This is called
+// by the application for every key event before it is passed on.
+func (k *Keymap) HandleKey(event *tcell.EventKey) bool {
+	combo := NewKeyCombo(event.Key(), event.Rune(), event.Modifiers())
+
+	// Any key closes the help overlay.
+	if k.helpVisible {
+		k.helpVisible = false
+		return true
+	}
+	if len(k.pending) == 0 && combo == k.helpKey {
+		k.helpVisible = true
+		return true
+	}
+
+	var root, focus Primitive
+	if k.app != nil {
+		root, focus = k.app.GetRoot(), k.app.GetFocus()
+	}
+	active := k.activeBindings(root, focus)
+	var textInput bool
+	if input, ok := focus.(textInputCapturer); ok {
+		textInput = input.capturesTextInput()
+	}
+
+	keys := append(append([]KeyCombo(nil), k.pending...), combo)
+	if k.match(active, keys, textInput) {
+		return true
+	}
+	if len(k.pending) > 0 {
+		// The chord was not continued. Try the key by itself.
+		k.pending = nil
+		return k.match(active, []KeyCombo{combo}, textInput)
+	}
+	return false
+}
+
+// textInputCapturer is implemented by primitives which may currently use
+// printable characters themselves, e.g. to enter text. Bindings starting with
+// an unmodified rune are then not applied.
+type textInputCapturer interface {
+	capturesTextInput() bool
+}
+
+// match executes the first of the given bindings whose keys equal the given
+// keys or remembers the keys as pending if they start a binding's chord. It
+// returns false if no binding matched.
+func (k *Keymap) match(bindings []*KeyBinding, keys []KeyCombo, textInput bool) bool {
+	for _, binding := range bindings {
+		first := binding.Keys[0]
+		if textInput && first.Key == tcell.KeyRune && first.Mod == tcell.ModNone {
+			continue
+		}
+		if !hasKeyPrefix(binding.Keys, keys) {
+			continue
+		}
+		if len(binding.Keys) > len(keys) {
+			k.pending = keys
+			return true
+		}
+		k.pending = nil
+		if binding.Action != nil {
+			binding.Action()
+		}
+		return true
+	}
+	return false
+}
+
+// hasKeyPrefix returns whether or not keys starts with prefix.
+func hasKeyPrefix(keys, prefix []KeyCombo) bool {
+	if len(prefix) > len(keys) {
+		return false
+	}
+	for index, key := range prefix {
+		if keys[index] != key {
+			return false
+		}
+	}
+	return true
+}
+
+// HelpText returns a description of the active bindings, one per line, as
+// shown in the help overlay.
+func (k *Keymap) HelpText() string {
+	var lines []string
+	for _, binding := range k.ActiveBindings() {
+		lines = append(lines, binding.KeysString()+"  "+binding.describe())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describe returns the binding's description or, if it has none, its name.
+func (b *KeyBinding) describe() string {
+	if b.Description == "" {
+		return b.Name
+	}
+	return b.Description
+}
+
+// drawHelp draws the help overlay, centered on the screen, if it is visible,
+// in the colors of the given theme (Styles if it is nil).
+func (k *Keymap) drawHelp(screen tcell.Screen, root, focus Primitive, theme *Theme) {
+	if !k.helpVisible {
+		return
+	}
+	if theme == nil {
+		theme = &Styles
+	}
+	bindings := k.activeBindings(root, focus)
+
+	// Determine the size of the overlay.
+	var keysWidth, descriptionWidth int
+	for _, binding := range bindings {
+		if w := StringWidth(binding.KeysString()); w > keysWidth {
+			keysWidth = w
+		}
+		if w := StringWidth(binding.describe()); w > descriptionWidth {
+			descriptionWidth = w
+		}
+	}
+	screenWidth, screenHeight := screen.Size()
+	width := keysWidth + descriptionWidth + 6
+	if w := StringWidth(" Key Bindings ") + 4; width < w {
+		width = w
+	}
+	height := len(bindings) + 2
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if height > screenHeight {
+		height = screenHeight
+	}
+
+	k.helpBox.SetRect((screenWidth-width)/2, (screenHeight-height)/2, width, height)
+	k.helpBox.Draw(screen)
+	x, y, innerWidth, innerHeight := k.helpBox.GetInnerRect()
+	for index, binding := range bindings {
+		if index >= innerHeight {
+			break
+		}
+		Print(screen, binding.KeysString(), x, y+index, keysWidth, AlignLeft, theme.SecondaryTextColor)
+		Print(screen, binding.describe(), x+keysWidth+2, y+index, innerWidth-keysWidth-2, AlignLeft, theme.PrimaryTextColor)
+	}
+}
@@ -0,0 +1,163 @@
+package tview_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+func TestParseKeyCombo(t *testing.T) {
+	for _, keys := range []string{"ctrl-x", "alt-enter", "G", "shift-tab", "f1", "alt--", "-", "space", "ctrl-up", "backspace", "ctrl-h", "esc"} {
+		combo, err := tview.ParseKeyCombo(keys)
+		if err != nil {
+			t.Fatalf("ParseKeyCombo(%q): %v", keys, err)
+		}
+		if again, _ := tview.ParseKeyCombo(combo.String()); again != combo {
+			t.Errorf("%q was printed as %q", keys, combo.String())
+		}
+	}
+	if _, err := tview.ParseKeyCombo("hyper-x"); err == nil {
+		t.Error("ParseKeyCombo() accepted an unknown modifier")
+	}
+
+	// Ctrl-H is a different key than backspace.
+	backspace, _ := tview.ParseKeyCombo("backspace")
+	ctrlH, _ := tview.ParseKeyCombo("ctrl-h")
+	if backspace == ctrlH || ctrlH.String() != "ctrl-h" {
+		t.Errorf("ctrl-h was parsed as %q", ctrlH)
+	}
+	if combo := tview.NewKeyCombo(tcell.KeyBackspace, 0, tcell.ModCtrl); combo != ctrlH {
+		t.Errorf("key 0x08 was normalized to %q, want ctrl-h", combo)
+	}
+}
+
+func TestKeymapScopeChanges(t *testing.T) {
+	left := tview.NewList().AddItem("Left", "", 0, nil)
+	leftFlex, rightFlex := tview.NewFlex().AddItem(left, 0, 1, true), tview.NewFlex()
+	keymap := tview.NewKeymap()
+	keymap.BindScoped(leftFlex, "left", "x", "", nil)
+	keymap.BindScoped(rightFlex, "right", "x", "", nil)
+
+	h := tviewtest.New(tview.NewFlex().AddItem(leftFlex, 0, 1, true).AddItem(rightFlex, 0, 1, false), 40, 10)
+	defer h.Stop()
+
+	// active returns the names of the active bindings.
+	active := func() (names []string) {
+		h.Do(func() {
+			for _, binding := range keymap.ActiveBindings() {
+				names = append(names, binding.Name)
+			}
+		})
+		return
+	}
+	h.Do(func() {
+		h.App.SetKeymap(keymap)
+	})
+	if names := fmt.Sprint(active()); names != "[left]" {
+		t.Errorf("active bindings are %s, want [left]", names)
+	}
+
+	// Moving the focused primitive to another container changes its scopes.
+	h.Do(func() {
+		leftFlex.DelItem(0)
+		rightFlex.AddItem(left, 0, 1, true)
+	})
+	if names := fmt.Sprint(active()); names != "[right]" {
+		t.Errorf("active bindings are %s after moving the focus, want [right]", names)
+	}
+}
+
+func TestKeymapChords(t *testing.T) {
+	list := tview.NewList().AddItem("Item", "", 0, nil)
+	keymap := tview.NewKeymap()
+	var actions []string
+	keymap.Bind("save", "ctrl-x ctrl-s", "Save", func() { actions = append(actions, "save") })
+	keymap.Bind("top", "g g", "Go to top", func() { actions = append(actions, "top") })
+	keymap.BindScoped(list, "list-top", "g g", "Go to the first item", func() { actions = append(actions, "list-top") })
+	if err := keymap.Bind("cut", "ctrl-x", "Cut", nil); err == nil {
+		t.Error("Bind() accepted a prefix of another binding")
+	}
+
+	h := tviewtest.New(list, 40, 10)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.SetKeymap(keymap)
+	})
+	h.Type("gg")
+	h.KeyPress(tcell.KeyCtrlX, 0, tcell.ModCtrl)
+	h.KeyPress(tcell.KeyCtrlS, 0, tcell.ModCtrl)
+
+	var result []string
+	h.Do(func() {
+		result = append(result, actions...)
+	})
+	if len(result) != 2 || result[0] != "list-top" || result[1] != "save" {
+		t.Errorf("keymap executed %v, want [list-top save]", result)
+	}
+}
+
+// textInputTest checks that the binding for "j" is not executed while the
+// given primitive captures text input after the keys were pressed.
+func textInputTest(t *testing.T, p tview.Primitive, keys string) {
+	t.Helper()
+	keymap := tview.NewKeymap()
+	var count int
+	keymap.Bind("down", "j", "Down", func() { count++ })
+
+	h := tviewtest.New(p, 40, 10)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.SetKeymap(keymap)
+	})
+	h.Type("j")
+	h.Type(keys)
+	h.Type("j")
+
+	var result int
+	h.Do(func() {
+		result = count
+	})
+	if result != 1 {
+		t.Errorf("binding was executed %d times, want once", result)
+	}
+}
+
+func TestKeymapTextInput(t *testing.T) {
+	keymap := tview.NewKeymap()
+	var count int
+	keymap.Bind("down", "j", "Down", func() { count++ })
+
+	input := tview.NewInputField()
+	h := tviewtest.New(input, 40, 1)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.SetKeymap(keymap)
+	})
+	h.Type("jj")
+
+	var (
+		text   string
+		result int
+	)
+	h.Do(func() {
+		text, result = input.GetText(), count
+	})
+	if text != "jj" || result != 0 {
+		t.Errorf("input field contains %q and binding was executed %d times", text, result)
+	}
+}
+
+func TestKeymapTextViewSearch(t *testing.T) {
+	textInputTest(t, tview.NewTextView().SetSearchable(true), "/")
+}
+
+func TestKeymapTextViewSelection(t *testing.T) {
+	textInputTest(t, tview.NewTextView().SetSelectable(true), "v")
+}
+
+func TestKeymapTableFilter(t *testing.T) {
+	textInputTest(t, tview.NewTable().SetFilterable(true), "/")
+}
@@ -25,6 +25,10 @@ func (l *ListItem) Id() string {
 
 // List displays rows of items, each of which can be selected.
 //
+// If multi-select is enabled with SetMultiSelect(), the user may also select
+// multiple items, e.g. to apply an action to all of them. See
+// GetSelectedItems().
+//
 // See https://github.com/rivo/tview/wiki/List for an example.
 type List struct {
 	*Box
@@ -53,6 +57,15 @@ type List struct {
 	// The background color for selected items.
 	selectedBackgroundColor tcell.Color
 
+	// Whether or not the user may select multiple items.
+	multiSelect bool
+
+	// The items selected with multi-select, in addition to the current item.
+	itemSelection multiSelection
+
+	// The background color of items selected with multi-select.
+	selectedItemsColor tcell.Color
+
 	// An optional function which is called when the user has navigated to a list
 	// item.
 	changed func(index int, mainText, secondaryText string, shortcut rune)
@@ -61,6 +74,10 @@ type List struct {
 	// function will be called even if the list item defines its own callback.
 	selected func(index int, mainText, secondaryText string, shortcut rune)
 
+	// An optional function which is called when the user changes which items
+	// are selected with multi-select.
+	selectedItemsChanged func(items []int)
+
 	// An optional function which is called when the user presses the Escape key.
 	done func()
 }
@@ -75,6 +92,7 @@ func NewList() *List {
 		shortcutColor:           Styles.SecondaryTextColor,
 		selectedTextColor:       Styles.PrimitiveBackgroundColor,
 		selectedBackgroundColor: Styles.PrimaryTextColor,
+		selectedItemsColor:      Styles.ContrastBackgroundColor,
 	}
 }
 
@@ -88,6 +106,11 @@ func (l *List) SetCurrentItem(index int) *List {
 	return l
 }
 
+// GetCurrentItem returns the index of the currently selected list item.
+func (l *List) GetCurrentItem() int {
+	return l.currentItem
+}
+
 // SetMainTextColor sets the color of the items' main text.
 func (l *List) SetMainTextColor(color tcell.Color) *List {
 	l.mainTextColor = color
@@ -118,6 +141,87 @@ func (l *List) SetSelectedBackgroundColor(color tcell.Color) *List {
 	return l
 }
 
+// ApplyTheme sets the list's colors and attributes to those of the given
+// theme.
+func (l *List) ApplyTheme(theme *Theme) {
+	l.Box.ApplyTheme(theme)
+	l.mainTextColor = theme.PrimaryTextColor
+	l.secondaryTextColor = theme.TertiaryTextColor
+	l.shortcutColor = theme.SecondaryTextColor
+	l.selectedTextColor = theme.PrimitiveBackgroundColor
+	l.selectedBackgroundColor = theme.PrimaryTextColor
+	l.selectedItemsColor = theme.ContrastBackgroundColor
+}
+
+// SetMultiSelect sets the flag that, if true, lets the user select multiple
+// items. The selected items are independent of the current item. The
+// following keys are available:
+//
+//   - Space: Select or unselect the current item (instead of triggering the
+//     "selected" callbacks).
+//   - Shift-up/down arrow, Shift-page up/down, Shift-home/end: Move to another
+//     item and select all items between the item where this started and the
+//     new current item.
+//   - Ctrl-A: Select all items.
+//   - *: Invert the selection (unless it is an item's shortcut).
+//
+// Clicking an item with Ctrl pressed selects or unselects it. Clicking it with
+// Shift pressed selects all items between the current item and the clicked
+// item. Selected items are shown with the background color set with
+// SetSelectedItemsColor().
+func (l *List) SetMultiSelect(multiSelect bool) *List {
+	l.multiSelect = multiSelect
+	return l
+}
+
+// GetSelectedItems returns the indices of the items selected with
+// multi-select (see SetMultiSelect()) in ascending order.
+func (l *List) GetSelectedItems() []int {
+	return l.itemSelection.list()
+}
+
+// SetSelectedItems selects the items with the given indices, replacing the
+// previously selected items.
+func (l *List) SetSelectedItems(items ...int) *List {
+	l.itemSelection.set(items)
+	return l
+}
+
+// SelectAllItems selects all items.
+func (l *List) SelectAllItems() *List {
+	l.selectAllItems(false)
+	return l
+}
+
+// InvertSelectedItems selects all items which are not selected and unselects
+// those which are selected.
+func (l *List) InvertSelectedItems() *List {
+	l.selectAllItems(true)
+	return l
+}
+
+// ClearSelectedItems unselects all items.
+func (l *List) ClearSelectedItems() *List {
+	l.itemSelection.set(nil)
+	return l
+}
+
+// SetSelectedItemsChangedFunc sets the function which is called when the user
+// changes which items are selected (see SetMultiSelect()). The function
+// receives the indices of the selected items as returned by
+// GetSelectedItems().
+func (l *List) SetSelectedItemsChangedFunc(handler func(items []int)) *List {
+	l.selectedItemsChanged = handler
+	return l
+}
+
+// SetSelectedItemsColor sets the background color of items selected with
+// multi-select (see SetMultiSelect()).
+func (l *List) SetSelectedItemsColor(color tcell.Color) *List {
+	l.selectedItemsColor = color
+	return l
+}
+
 // ShowSecondaryText determines whether or not to show secondary item texts.
 func (l *List) ShowSecondaryText(show bool) *List {
 	l.showSecondaryText = show
@@ -176,13 +280,53 @@ func (l *List) AddItem(mainText, secondaryText string, shortcut rune, selected f
 	return l
 }
 
+// GetItemCount returns the number of items in the list.
+func (l *List) GetItemCount() int {
+	return len(l.items)
+}
+
+// GetItemText returns an item's texts (main and secondary). Panics if the index
+// is out of range.
+func (l *List) GetItemText(index int) (main, secondary string) {
+	return l.items[index].MainText, l.items[index].SecondaryText
+}
+
 // Clear removes all items from the list.
 func (l *List) Clear() *List {
 	l.items = nil
 	l.currentItem = 0
+	l.itemSelection.set(nil)
 	return l
 }
 
+// selectAllItems selects all items. If "invert" is true, those which are
+// already selected are unselected instead.
+func (l *List) selectAllItems(invert bool) {
+	items := make([]int, 0, len(l.items))
+	for index := range l.items {
+		if !invert || !l.itemSelection.has(index) {
+			items = append(items, index)
+		}
+	}
+	l.itemSelection.set(items)
+}
+
+// selectItemRange selects all items between the given item and the item
+// where the range selection started, which is "from" if no range selection
+// was started yet.
+func (l *List) selectItemRange(from, to int) {
+	l.itemSelection.startRange(from)
+	from = l.itemSelection.anchor
+	if from > to {
+		from, to = to, from
+	}
+	var items []int
+	for index := from; index <= to; index++ {
+		items = append(items, index)
+	}
+	l.itemSelection.setRange(items)
+}
+
 // Draw draws this primitive onto the screen.
 func (l *List) Draw(screen tcell.Screen) {
 	l.Box.Draw(screen)
@@ -219,6 +363,14 @@ func (l *List) Draw(screen tcell.Screen) {
 		// Main text.
 		Print(screen, item.MainText, x, y, width, AlignLeft, l.mainTextColor)
 
+		// Background color of items selected with multi-select.
+		if l.itemSelection.has(index) {
+			for bx := 0; bx < width; bx++ {
+				m, c, style, _ := screen.GetContent(x+bx, y)
+				screen.SetContent(x+bx, y, m, c, style.Background(l.selectedItemsColor))
+			}
+		}
+
 		// Background color of selected text.
 		if index == l.currentItem {
 			textWidth := StringWidth(item.MainText)
@@ -251,10 +403,22 @@ func (l *List) Draw(screen tcell.Screen) {
 func (l *List) InputHandler() func(tcell.Event, func(Primitive)) {
 	return l.wrapInputHandler(func(event tcell.Event, setFocus func(p Primitive)) {
 		previousItem := l.currentItem
+		var previouslySelectedItems []int
+		if l.multiSelect && l.selectedItemsChanged != nil {
+			previouslySelectedItems = l.itemSelection.list()
+		}
 		switch evt := event.(type) {
 		case *tcell.EventKey:
+			key := evt.Key()
+
+			// Movement keys with Shift select a range of items.
+			selectRange := l.multiSelect && evt.Modifiers()&tcell.ModShift != 0 &&
+				(key == tcell.KeyUp || key == tcell.KeyDown || key == tcell.KeyPgUp || key == tcell.KeyPgDn || key == tcell.KeyHome || key == tcell.KeyEnd)
+			if !selectRange {
+				l.itemSelection.endRange()
+			}
 
-			switch key := evt.Key(); key {
+			switch key {
 			case tcell.KeyTab, tcell.KeyDown, tcell.KeyRight:
 				l.currentItem++
 			case tcell.KeyBacktab, tcell.KeyUp, tcell.KeyLeft:
@@ -282,8 +446,18 @@ func (l *List) InputHandler() func(tcell.Event, func(Primitive)) {
 				if l.done != nil {
 					l.done()
 				}
+			case tcell.KeyCtrlA:
+				if l.multiSelect {
+					l.selectAllItems(false)
+				}
 			case tcell.KeyRune:
 				ch := evt.Rune()
+				if ch == ' ' && l.multiSelect {
+					if l.currentItem >= 0 && l.currentItem < len(l.items) {
+						l.itemSelection.toggle(l.currentItem)
+					}
+					break
+				}
 				if ch != ' ' {
 					// It's not a space bar. Is it a shortcut?
 					var found bool
@@ -296,6 +470,9 @@ func (l *List) InputHandler() func(tcell.Event, func(Primitive)) {
 						}
 					}
 					if !found {
+						if ch == '*' && l.multiSelect {
+							l.selectAllItems(true)
+						}
 						break
 					}
 				}
@@ -307,6 +484,16 @@ func (l *List) InputHandler() func(tcell.Event, func(Primitive)) {
 					l.selected(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
 				}
 			}
+
+			if selectRange && len(l.items) > 0 {
+				// Ranges don't wrap around.
+				if l.currentItem < 0 {
+					l.currentItem = 0
+				} else if l.currentItem >= len(l.items) {
+					l.currentItem = len(l.items) - 1
+				}
+				l.selectItemRange(previousItem, l.currentItem)
+			}
 		}
 
 		if l.currentItem < 0 {
@@ -319,5 +506,100 @@ func (l *List) InputHandler() func(tcell.Event, func(Primitive)) {
 			item := l.items[l.currentItem]
 			l.changed(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
 		}
+		if l.multiSelect && l.selectedItemsChanged != nil && !l.itemSelection.equal(previouslySelectedItems) {
+			l.selectedItemsChanged(l.itemSelection.list())
+		}
+	})
+}
+
+// indexAtPoint returns the index of the list item found at the given screen
+// position or a negative value if there is no such list item.
+func (l *List) indexAtPoint(x, y int) int {
+	rectX, rectY, width, height := l.GetInnerRect()
+	if x < rectX || x >= rectX+width || y < rectY || y >= rectY+height {
+		return -1
+	}
+
+	index := y - rectY
+	if l.showSecondaryText {
+		index /= 2
+	}
+
+	if index >= len(l.items) {
+		return -1
+	}
+	return index
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return l.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !l.InRect(x, y) && action != MouseLeftDrag && action != MouseLeftUp {
+			return false, nil
+		}
+
+		// Process mouse event.
+		previousItem := l.currentItem
+		var previouslySelectedItems []int
+		if l.multiSelect && l.selectedItemsChanged != nil {
+			previouslySelectedItems = l.itemSelection.list()
+		}
+		switch action {
+		case MouseLeftDown:
+			setFocus(l)
+			capture = l
+			consumed = true
+		case MouseLeftDrag:
+			// Moving the mouse with the button pressed moves the current item.
+			if index := l.indexAtPoint(x, y); index >= 0 {
+				l.currentItem = index
+			}
+			capture = l
+			consumed = true
+		case MouseLeftUp:
+			consumed = true
+		case MouseLeftClick:
+			index := l.indexAtPoint(x, y)
+			if index >= 0 && l.multiSelect && event.Modifiers()&tcell.ModCtrl != 0 {
+				// Select or unselect the clicked item.
+				l.itemSelection.toggle(index)
+			} else if index >= 0 && l.multiSelect && event.Modifiers()&tcell.ModShift != 0 {
+				// Select the items up to the clicked item.
+				l.selectItemRange(l.currentItem, index)
+				l.itemSelection.endRange()
+				l.currentItem = index
+			} else if index >= 0 {
+				l.currentItem = index
+				item := l.items[index]
+				if item.Selected != nil {
+					item.Selected()
+				}
+				if l.selected != nil {
+					l.selected(index, item.MainText, item.SecondaryText, item.Shortcut)
+				}
+			}
+			consumed = true
+		case MouseScrollUp:
+			if l.currentItem > 0 {
+				l.currentItem--
+			}
+			consumed = true
+		case MouseScrollDown:
+			if l.currentItem < len(l.items)-1 {
+				l.currentItem++
+			}
+			consumed = true
+		}
+
+		if l.currentItem != previousItem && l.currentItem < len(l.items) && l.changed != nil {
+			item := l.items[l.currentItem]
+			l.changed(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
+		}
+		if l.multiSelect && l.selectedItemsChanged != nil && !l.itemSelection.equal(previouslySelectedItems) {
+			l.selectedItemsChanged(l.itemSelection.list())
+		}
+
+		return
 	})
 }
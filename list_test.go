@@ -0,0 +1,48 @@
+package tview_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+func TestListMultiSelect(t *testing.T) {
+	list := tview.NewList().ShowSecondaryText(false).SetMultiSelect(true)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		list.AddItem(name, "", 0, nil)
+	}
+	var changed []int
+	list.SetSelectedItemsChangedFunc(func(items []int) {
+		changed = items
+	})
+	h := tviewtest.New(list, 20, 6)
+	defer h.Stop()
+
+	// items returns the selected items and those last passed to the handler.
+	items := func() (selected, reported string) {
+		h.Do(func() {
+			selected, reported = fmt.Sprint(list.GetSelectedItems()), fmt.Sprint(changed)
+		})
+		return
+	}
+
+	h.Type(" ")
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModShift)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModShift)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModShift)
+	if selected, reported := items(); selected != "[0 1 2 3]" || reported != selected {
+		t.Errorf("selected items are %s, handler received %s, want [0 1 2 3]", selected, reported)
+	}
+	h.Type("*")
+	if selected, _ := items(); selected != "[]" {
+		t.Errorf("selected items are %s after inverting, want none", selected)
+	}
+	h.KeyPress(tcell.KeyCtrlA, 0, tcell.ModCtrl)
+	if selected, _ := items(); selected != "[0 1 2 3]" {
+		t.Errorf("selected items are %s after selecting all, want [0 1 2 3]", selected)
+	}
+}
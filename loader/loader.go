@@ -0,0 +1,988 @@
+/*
+Package loader builds tview primitive trees from declarative view descriptions
+written in YAML or JSON (which is a subset of YAML).
+
+Every primitive is described by a mapping with a "type" key. The following keys
+are available for all types:
+
+  type:   The primitive's type (see below).
+  name:   The primitive's name, set with SetName().
+  border: Whether or not to draw a border (bool).
+  title:  The title shown in the border.
+  props:  A mapping of props set with SetProp().
+
+The following types and their keys are supported. Keys ending in a callback
+signature refer to a function in the Registry passed to the loader, by name.
+The registered function must have exactly that signature.
+
+  box
+
+  flex
+    direction:  "column" (default, items side by side) or "row" (items stacked).
+    fullScreen: bool
+    items:      A list of mappings with the following keys:
+      view:       The item's primitive.
+      fixed:      The item's fixed size (int, 0 for a flexible size).
+      proportion: The item's proportion (int, default 1).
+      focus:      Whether or not the item receives the focus (bool).
+
+  pages
+    pages:   A list of mappings with the following keys:
+      name:    The page's name.
+      view:    The page's primitive.
+      resize:  Whether or not to resize the page (bool, default true).
+      visible: Whether or not the page is visible (bool).
+    current: The name of the page to switch to.
+
+  form
+    horizontal:   bool
+    itemPadding:  int
+    buttonsAlign: "left", "center", or "right".
+    items:        A list of form items, i.e. primitives of the types input,
+                  password, textarea, checkbox, and dropdown, which may
+                  additionally have the key "validator"
+                  (func(value interface{}) error).
+    buttons:      A list of mappings with the following keys:
+      label:    The button's label.
+      selected: func()
+      submit:   Whether or not selecting the button submits the form (bool).
+    cancel:       func()
+    submit:       func(values map[string]interface{}) map[string]error
+
+  input, password
+    label, value: string
+    width:        int
+    mask:         The mask character (password only, default "*").
+    changed:      func(text string)
+
+  textarea
+    label, value:  string
+    width, height: int
+    wrap:          bool
+    tabSize:       int
+    changed:       func(text string)
+
+  checkbox
+    label:   string
+    checked: bool
+    changed: func(checked bool)
+
+  dropdown
+    label:   string
+    width:   int
+    options: A list of strings or of mappings with the keys "text" and "value".
+    current: The index of the initially selected option (int).
+    selected: func(text string, value interface{}, index int)
+
+  button
+    label:    string
+    selected: func()
+
+  table
+    borders:       bool
+    fixedRows:     int
+    fixedColumns:  int
+    selectRows:    bool
+    selectColumns: bool
+    cells:         A list of rows, each a list of strings or of mappings with
+                   the keys "text", "align", "maxWidth", and "selectable".
+    selected:      func(row, column int)
+    changed:       func(row, column int)
+    done:          func(key tcell.Key)
+
+  list
+    showSecondaryText: bool
+    items:    A list of mappings with the keys "text", "secondaryText",
+              "shortcut" (a single character), and "selected" (func()).
+    selected: func(index int, mainText, secondaryText string, shortcut rune)
+    changed:  func(index int, mainText, secondaryText string, shortcut rune)
+    done:     func()
+
+  textview
+    text:          string
+    dynamicColors: bool
+    regions:       bool
+    scrollable:    bool
+    wrap:          bool
+    wordWrap:      bool
+    align:         "left", "center", or "right".
+    changed:       func()
+    done:          func(key tcell.Key)
+
+  modal
+    text:    string
+    buttons: A list of button labels.
+    done:    func(buttonIndex int, buttonLabel string)
+
+A small example:
+
+  type: flex
+  direction: row
+  items:
+    - fixed: 3
+      view:
+        type: textview
+        text: "Welcome"
+    - view:
+        type: form
+        name: login
+        items:
+          - type: input
+            name: user
+            label: User
+          - type: password
+            name: password
+            label: Password
+        buttons:
+          - label: Login
+            submit: true
+        submit: login
+
+Errors returned by the loader are of type *Error and point to the location of
+the problem in the document.
+*/
+package loader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Registry maps names used in view descriptions to callback functions.
+type Registry map[string]interface{}
+
+// Error describes a problem found in a view description.
+type Error struct {
+	File    string // The name of the file, if known.
+	Line    int    // The line of the problem, starting at 1 (0 if unknown).
+	Column  int    // The column of the problem, starting at 1 (0 if unknown).
+	Message string // The description of the problem.
+}
+
+// Error returns the error's location and message.
+func (e *Error) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		if location != "" {
+			location += ":"
+		}
+		location += strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	if location == "" {
+		return e.Message
+	}
+	return location + ": " + e.Message
+}
+
+// Load builds a primitive tree from the given YAML or JSON document. Callbacks
+// are looked up in the given registry, which may be nil if the document does
+// not refer to any.
+func Load(data []byte, registry Registry) (tview.Primitive, error) {
+	return load("", data, registry)
+}
+
+// LoadFile builds a primitive tree from the YAML or JSON document in the file
+// with the given path. See Load() for details.
+func LoadFile(path string, registry Registry) (tview.Primitive, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if pathErr, ok := err.(*os.PathError); ok {
+			err = pathErr.Err // The file name is part of the Error.
+		}
+		return nil, &Error{File: path, Message: err.Error()}
+	}
+	return load(path, data, registry)
+}
+
+// yamlError matches the line number in the syntax errors of the YAML parser.
+var yamlError = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// load builds a primitive tree from the given document. The file name is only
+// used in error messages.
+func load(file string, data []byte, registry Registry) (tview.Primitive, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		e := &Error{File: file, Message: err.Error()}
+		if m := yamlError.FindStringSubmatch(err.Error()); m != nil {
+			e.Line, _ = strconv.Atoi(m[1])
+			e.Message = m[2]
+		}
+		return nil, e
+	}
+	if len(document.Content) == 0 {
+		return nil, &Error{File: file, Message: "empty document"}
+	}
+
+	b := &builder{file: file, registry: registry}
+	primitive := b.primitive(document.Content[0])
+	if b.err != nil {
+		return nil, b.err
+	}
+	return primitive, nil
+}
+
+// builder builds primitives from YAML nodes. It records the first error it
+// encounters, after which the results of its functions are meaningless.
+type builder struct {
+	file     string
+	registry Registry
+	err      error
+}
+
+// fail records an error at the location of the given node unless an error
+// was already recorded.
+func (b *builder) fail(node *yaml.Node, format string, args ...interface{}) {
+	if b.err == nil {
+		b.err = &Error{
+			File:    b.file,
+			Line:    node.Line,
+			Column:  node.Column,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+}
+
+// resolve returns the node an alias node refers to or the node itself.
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// object gives access to the values of a mapping node and keeps track of the
+// keys which were accessed so that unknown keys can be reported.
+type object struct {
+	b      *builder
+	node   *yaml.Node
+	keys   []*yaml.Node
+	values map[string]*yaml.Node
+	used   map[string]bool
+}
+
+// object returns an object for the given mapping node.
+func (b *builder) object(node *yaml.Node) *object {
+	node = resolve(node)
+	o := &object{
+		b:      b,
+		node:   node,
+		values: make(map[string]*yaml.Node),
+		used:   make(map[string]bool),
+	}
+	if node.Kind != yaml.MappingNode {
+		b.fail(node, "expected a mapping")
+		return o
+	}
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		key := node.Content[index]
+		if _, ok := o.values[key.Value]; ok {
+			b.fail(key, "duplicate key %q", key.Value)
+		}
+		o.keys = append(o.keys, key)
+		o.values[key.Value] = resolve(node.Content[index+1])
+	}
+	return o
+}
+
+// get returns the value node of the given key or nil if the key does not
+// exist.
+func (o *object) get(key string) *yaml.Node {
+	o.used[key] = true
+	return o.values[key]
+}
+
+// finish reports the first key which was not accessed.
+func (o *object) finish() {
+	for _, key := range o.keys {
+		if !o.used[key.Value] {
+			o.b.fail(key, "unknown key %q", key.Value)
+			return
+		}
+	}
+}
+
+// scalar decodes the scalar value of the given key into target. It returns
+// false if the key does not exist.
+func (o *object) scalar(key string, target interface{}, kind string) bool {
+	node := o.get(key)
+	if node == nil {
+		return false
+	}
+	if node.Kind != yaml.ScalarNode || node.Decode(target) != nil {
+		o.b.fail(node, "%q must be %s", key, kind)
+	}
+	return true
+}
+
+// string decodes the string value of the given key into target.
+func (o *object) string(key string, target *string) bool {
+	return o.scalar(key, target, "a string")
+}
+
+// int decodes the integer value of the given key into target.
+func (o *object) int(key string, target *int) bool {
+	return o.scalar(key, target, "an integer")
+}
+
+// bool decodes the boolean value of the given key into target.
+func (o *object) bool(key string, target *bool) bool {
+	return o.scalar(key, target, "true or false")
+}
+
+// value decodes the value of the given key into a generic value.
+func (o *object) value(key string) (interface{}, bool) {
+	node := o.get(key)
+	if node == nil {
+		return nil, false
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		o.b.fail(node, "invalid value for %q: %s", key, err)
+	}
+	return value, true
+}
+
+// align decodes an alignment value ("left", "center", or "right") into
+// target.
+func (o *object) align(key string, target *int) bool {
+	var align string
+	if !o.string(key, &align) {
+		return false
+	}
+	switch align {
+	case "left":
+		*target = tview.AlignLeft
+	case "center":
+		*target = tview.AlignCenter
+	case "right":
+		*target = tview.AlignRight
+	default:
+		o.b.fail(o.values[key], "%q must be \"left\", \"center\", or \"right\"", key)
+	}
+	return true
+}
+
+// list returns the items of the sequence value of the given key.
+func (o *object) list(key string) []*yaml.Node {
+	node := o.get(key)
+	if node == nil {
+		return nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		o.b.fail(node, "%q must be a list", key)
+		return nil
+	}
+	items := make([]*yaml.Node, len(node.Content))
+	for index, item := range node.Content {
+		items[index] = resolve(item)
+	}
+	return items
+}
+
+// callback looks up the function named by the value of the given key in the
+// registry and stores it in target, which must be a pointer to a variable of
+// a function type. It returns false if the key does not exist.
+func (o *object) callback(key string, target interface{}) bool {
+	var name string
+	if !o.string(key, &name) {
+		return false
+	}
+	node := o.values[key]
+	fn, ok := o.b.registry[name]
+	if !ok {
+		o.b.fail(node, "callback %q is not registered", name)
+		return false
+	}
+	targetValue := reflect.ValueOf(target).Elem()
+	fnValue := reflect.ValueOf(fn)
+	if !fnValue.IsValid() || !fnValue.Type().AssignableTo(targetValue.Type()) {
+		o.b.fail(node, "callback %q has type %T, expected %s", name, fn, targetValue.Type())
+		return false
+	}
+	targetValue.Set(fnValue)
+	return true
+}
+
+// primitive builds the primitive described by the given node.
+func (b *builder) primitive(node *yaml.Node) tview.Primitive {
+	o := b.object(node)
+	var typeName string
+	if !o.string("type", &typeName) {
+		b.fail(node, "missing key \"type\"")
+		return nil
+	}
+
+	var primitive tview.Primitive
+	switch typeName {
+	case "box":
+		primitive = tview.NewBox()
+	case "flex":
+		primitive = b.flex(o)
+	case "pages":
+		primitive = b.pages(o)
+	case "form":
+		primitive = b.form(o)
+	case "input", "password":
+		primitive = b.inputField(o, typeName == "password")
+	case "textarea":
+		primitive = b.textArea(o)
+	case "checkbox":
+		primitive = b.checkbox(o)
+	case "dropdown":
+		primitive = b.dropDown(o)
+	case "button":
+		primitive = b.button(o)
+	case "table":
+		primitive = b.table(o)
+	case "list":
+		primitive = b.list(o)
+	case "textview":
+		primitive = b.textView(o)
+	case "modal":
+		primitive = b.modal(o)
+	default:
+		b.fail(o.values["type"], "unknown type %q", typeName)
+		return nil
+	}
+
+	b.common(o, primitive)
+	return primitive
+}
+
+// common applies the keys shared by all primitives and reports unknown keys.
+func (b *builder) common(o *object, primitive tview.Primitive) {
+	var name string
+	if o.string("name", &name) {
+		if p, ok := primitive.(interface {
+			SetName(name string)
+		}); ok {
+			p.SetName(name)
+		}
+	}
+
+	if p, ok := primitive.(interface {
+		SetBorder(show bool) *tview.Box
+		SetTitle(title string) *tview.Box
+	}); ok {
+		var border bool
+		if o.bool("border", &border) {
+			p.SetBorder(border)
+		}
+		var title string
+		if o.string("title", &title) {
+			p.SetTitle(title)
+		}
+	}
+
+	if props := o.get("props"); props != nil {
+		po := b.object(props)
+		for _, key := range po.keys {
+			value, _ := po.value(key.Value)
+			if err := primitive.SetProp(key.Value, value); err != nil {
+				b.fail(key, "%s", err)
+			}
+		}
+	}
+
+	o.finish()
+}
+
+// flex builds a Flex.
+func (b *builder) flex(o *object) tview.Primitive {
+	flex := tview.NewFlex()
+
+	var direction string
+	if o.string("direction", &direction) {
+		switch direction {
+		case "row":
+			flex.SetDirection(tview.FlexRow)
+		case "column":
+			flex.SetDirection(tview.FlexColumn)
+		default:
+			b.fail(o.values["direction"], "\"direction\" must be \"row\" or \"column\"")
+		}
+	}
+	var fullScreen bool
+	if o.bool("fullScreen", &fullScreen) {
+		flex.SetFullScreen(fullScreen)
+	}
+
+	for _, node := range o.list("items") {
+		item := b.object(node)
+		var (
+			fixed, proportion = 0, 1
+			focus             bool
+			view              tview.Primitive
+		)
+		item.int("fixed", &fixed)
+		item.int("proportion", &proportion)
+		item.bool("focus", &focus)
+		if viewNode := item.get("view"); viewNode != nil {
+			view = b.primitive(viewNode)
+		} else {
+			b.fail(node, "missing key \"view\"")
+		}
+		item.finish()
+		flex.AddItem(view, fixed, proportion, focus)
+	}
+
+	return flex
+}
+
+// pages builds a Pages.
+func (b *builder) pages(o *object) tview.Primitive {
+	pages := tview.NewPages()
+
+	for _, node := range o.list("pages") {
+		page := b.object(node)
+		var (
+			name            string
+			resize, visible = true, false
+			view            tview.Primitive
+		)
+		if !page.string("name", &name) {
+			b.fail(node, "missing key \"name\"")
+		}
+		page.bool("resize", &resize)
+		page.bool("visible", &visible)
+		if viewNode := page.get("view"); viewNode != nil {
+			view = b.primitive(viewNode)
+		} else {
+			b.fail(node, "missing key \"view\"")
+		}
+		page.finish()
+		if view != nil {
+			pages.AddPage(name, view, resize, visible)
+		}
+	}
+
+	var current string
+	if o.string("current", &current) {
+		if !pages.HasPage(current) {
+			b.fail(o.values["current"], "unknown page %q", current)
+		} else {
+			pages.SwitchToPage(current, make(map[string]interface{}))
+		}
+	}
+
+	return pages
+}
+
+// form builds a Form.
+func (b *builder) form(o *object) tview.Primitive {
+	form := tview.NewForm()
+
+	var horizontal bool
+	if o.bool("horizontal", &horizontal) {
+		form.SetHorizontal(horizontal)
+	}
+	var padding int
+	if o.int("itemPadding", &padding) {
+		form.SetItemPadding(padding)
+	}
+	var align int
+	if o.align("buttonsAlign", &align) {
+		form.SetButtonsAlign(align)
+	}
+
+	var index int
+	for _, node := range o.list("items") {
+		// The validator is a form-level setting, extract it before building the
+		// item.
+		itemObject := b.object(node)
+		var validator func(value interface{}) error
+		itemObject.callback("validator", &validator)
+
+		item, ok := b.primitive(node).(tview.FormItem)
+		if !ok {
+			b.fail(node, "not a form item")
+			continue
+		}
+		form.AddFormItem(item)
+		if validator != nil {
+			form.SetItemValidator(index, validator)
+		}
+		index++
+	}
+
+	for _, node := range o.list("buttons") {
+		button := b.object(node)
+		var (
+			label    string
+			selected func()
+			submit   bool
+		)
+		if !button.string("label", &label) {
+			b.fail(node, "missing key \"label\"")
+		}
+		button.callback("selected", &selected)
+		button.bool("submit", &submit)
+		button.finish()
+		if submit {
+			next := selected
+			selected = func() {
+				if form.Submit() && next != nil {
+					next()
+				}
+			}
+		}
+		form.AddButton(label, selected)
+	}
+
+	var cancel func()
+	if o.callback("cancel", &cancel) {
+		form.SetCancelFunc(cancel)
+	}
+	var submit func(values map[string]interface{}) map[string]error
+	if o.callback("submit", &submit) {
+		form.SetSubmitFunc(submit)
+	}
+
+	return form
+}
+
+// inputField builds an InputField, masked if it is a password field.
+func (b *builder) inputField(o *object, password bool) tview.Primitive {
+	inputField := tview.NewInputField()
+
+	var label, value string
+	if o.string("label", &label) {
+		inputField.SetLabel(label)
+	}
+	if o.string("value", &value) {
+		inputField.SetText(value)
+	}
+	var width int
+	if o.int("width", &width) {
+		inputField.SetFieldWidth(width)
+	}
+	if password {
+		mask := "*"
+		if o.string("mask", &mask) && utf8.RuneCountInString(mask) != 1 {
+			b.fail(o.values["mask"], "\"mask\" must be a single character")
+		}
+		r, _ := utf8.DecodeRuneInString(mask)
+		inputField.SetMaskCharacter(r)
+	}
+	var changed func(text string)
+	if o.callback("changed", &changed) {
+		inputField.SetChangedFunc(changed)
+	}
+	o.get("validator") // Handled by the form.
+
+	return inputField
+}
+
+// textArea builds a TextArea.
+func (b *builder) textArea(o *object) tview.Primitive {
+	textArea := tview.NewTextArea()
+
+	var label string
+	if o.string("label", &label) {
+		textArea.SetLabel(label)
+	}
+	var width, height, tabSize int
+	if o.int("width", &width) {
+		textArea.SetFieldWidth(width)
+	}
+	if o.int("height", &height) {
+		textArea.SetFieldHeight(height)
+	}
+	var wrap bool
+	if o.bool("wrap", &wrap) {
+		textArea.SetWrap(wrap)
+	}
+	if o.int("tabSize", &tabSize) {
+		textArea.SetTabSize(tabSize)
+	}
+	var value string
+	if o.string("value", &value) {
+		textArea.SetText(value) // After the tab size, tabs are expanded.
+	}
+	var changed func(text string)
+	if o.callback("changed", &changed) {
+		textArea.SetChangedFunc(changed)
+	}
+	o.get("validator") // Handled by the form.
+
+	return textArea
+}
+
+// checkbox builds a Checkbox.
+func (b *builder) checkbox(o *object) tview.Primitive {
+	checkbox := tview.NewCheckbox()
+
+	var label string
+	if o.string("label", &label) {
+		checkbox.SetLabel(label)
+	}
+	var checked bool
+	if o.bool("checked", &checked) {
+		checkbox.SetChecked(checked)
+	}
+	var changed func(checked bool)
+	if o.callback("changed", &changed) {
+		checkbox.SetChangedFunc(changed)
+	}
+	o.get("validator") // Handled by the form.
+
+	return checkbox
+}
+
+// dropDown builds a DropDown.
+func (b *builder) dropDown(o *object) tview.Primitive {
+	dropDown := tview.NewDropDown()
+
+	var label string
+	if o.string("label", &label) {
+		dropDown.SetLabel(label)
+	}
+	var width int
+	if o.int("width", &width) {
+		dropDown.SetFieldWidth(width)
+	}
+
+	var (
+		texts  []string
+		values []interface{}
+	)
+	for _, node := range o.list("options") {
+		if node.Kind == yaml.ScalarNode {
+			texts = append(texts, node.Value)
+			values = append(values, node.Value)
+			continue
+		}
+		option := b.object(node)
+		var text string
+		if !option.string("text", &text) {
+			b.fail(node, "missing key \"text\"")
+		}
+		value, ok := option.value("value")
+		if !ok {
+			value = text
+		}
+		option.finish()
+		texts = append(texts, text)
+		values = append(values, value)
+	}
+	var selected func(text string, value interface{}, index int)
+	o.callback("selected", &selected)
+	dropDown.SetOptions(texts, values, selected)
+
+	var current int
+	if o.int("current", &current) {
+		if current < 0 || current >= len(texts) {
+			b.fail(o.values["current"], "\"current\" must be the index of an option")
+		} else {
+			dropDown.SetCurrentOption(current)
+		}
+	}
+	o.get("validator") // Handled by the form.
+
+	return dropDown
+}
+
+// button builds a Button.
+func (b *builder) button(o *object) tview.Primitive {
+	var label string
+	o.string("label", &label)
+	button := tview.NewButton(label)
+
+	var selected func()
+	if o.callback("selected", &selected) {
+		button.SetSelectedFunc(selected)
+	}
+
+	return button
+}
+
+// table builds a Table.
+func (b *builder) table(o *object) tview.Primitive {
+	table := tview.NewTable()
+
+	var borders bool
+	if o.bool("borders", &borders) {
+		table.SetBorders(borders)
+	}
+	var fixedRows, fixedColumns int
+	o.int("fixedRows", &fixedRows)
+	o.int("fixedColumns", &fixedColumns)
+	table.SetFixed(fixedRows, fixedColumns)
+	var selectRows, selectColumns bool
+	o.bool("selectRows", &selectRows)
+	o.bool("selectColumns", &selectColumns)
+	table.SetSelectable(selectRows, selectColumns)
+
+	cellsNode := o.get("cells")
+	if cellsNode != nil && cellsNode.Kind != yaml.SequenceNode {
+		b.fail(cellsNode, "\"cells\" must be a list of rows")
+	} else if cellsNode != nil {
+		for row, rowNode := range cellsNode.Content {
+			rowNode = resolve(rowNode)
+			if rowNode.Kind != yaml.SequenceNode {
+				b.fail(rowNode, "a row must be a list of cells")
+				continue
+			}
+			for column, cellNode := range rowNode.Content {
+				table.SetCell(row, column, b.tableCell(resolve(cellNode)))
+			}
+		}
+	}
+
+	var selected, changed func(row, column int)
+	if o.callback("selected", &selected) {
+		table.SetSelectedFunc(selected)
+	}
+	if o.callback("changed", &changed) {
+		table.SetSelectionChangedFunc(changed)
+	}
+	var done func(key tcell.Key)
+	if o.callback("done", &done) {
+		table.SetDoneFunc(done)
+	}
+
+	return table
+}
+
+// tableCell builds a TableCell from a string or a mapping.
+func (b *builder) tableCell(node *yaml.Node) *tview.TableCell {
+	if node.Kind == yaml.ScalarNode {
+		return tview.NewTableCell(node.Value)
+	}
+
+	o := b.object(node)
+	var text string
+	o.string("text", &text)
+	cell := tview.NewTableCell(text)
+	o.align("align", &cell.Align)
+	o.int("maxWidth", &cell.MaxWidth)
+	selectable := true
+	o.bool("selectable", &selectable)
+	cell.NotSelectable = !selectable
+	o.finish()
+
+	return cell
+}
+
+// list builds a List.
+func (b *builder) list(o *object) tview.Primitive {
+	list := tview.NewList()
+
+	var showSecondaryText bool
+	if o.bool("showSecondaryText", &showSecondaryText) {
+		list.ShowSecondaryText(showSecondaryText)
+	}
+
+	for _, node := range o.list("items") {
+		item := b.object(node)
+		var (
+			text, secondaryText, shortcut string
+			selected                      func()
+		)
+		if !item.string("text", &text) {
+			b.fail(node, "missing key \"text\"")
+		}
+		item.string("secondaryText", &secondaryText)
+		if item.string("shortcut", &shortcut) && utf8.RuneCountInString(shortcut) != 1 {
+			b.fail(item.values["shortcut"], "\"shortcut\" must be a single character")
+		}
+		item.callback("selected", &selected)
+		item.finish()
+		r, _ := utf8.DecodeRuneInString(shortcut)
+		list.AddItem(text, secondaryText, r, selected)
+	}
+
+	var selected, changed func(index int, mainText, secondaryText string, shortcut rune)
+	if o.callback("selected", &selected) {
+		list.SetSelectedFunc(selected)
+	}
+	if o.callback("changed", &changed) {
+		list.SetChangedFunc(changed)
+	}
+	var done func()
+	if o.callback("done", &done) {
+		list.SetDoneFunc(done)
+	}
+
+	return list
+}
+
+// textView builds a TextView.
+func (b *builder) textView(o *object) tview.Primitive {
+	textView := tview.NewTextView()
+
+	var flag bool
+	if o.bool("dynamicColors", &flag) {
+		textView.SetDynamicColors(flag)
+	}
+	if o.bool("regions", &flag) {
+		textView.SetRegions(flag)
+	}
+	if o.bool("scrollable", &flag) {
+		textView.SetScrollable(flag)
+	}
+	if o.bool("wrap", &flag) {
+		textView.SetWrap(flag)
+	}
+	if o.bool("wordWrap", &flag) {
+		textView.SetWordWrap(flag)
+	}
+	var align int
+	if o.align("align", &align) {
+		textView.SetTextAlign(align)
+	}
+	var text string
+	if o.string("text", &text) {
+		fmt.Fprint(textView, text)
+	}
+
+	var changed func()
+	if o.callback("changed", &changed) {
+		textView.SetChangedFunc(changed)
+	}
+	var done func(key tcell.Key)
+	if o.callback("done", &done) {
+		textView.SetDoneFunc(done)
+	}
+
+	return textView
+}
+
+// modal builds a Modal.
+func (b *builder) modal(o *object) tview.Primitive {
+	modal := tview.NewModal()
+
+	var text string
+	if o.string("text", &text) {
+		modal.SetText(text)
+	}
+
+	var labels []string
+	for _, node := range o.list("buttons") {
+		if node.Kind != yaml.ScalarNode {
+			b.fail(node, "a button must be a label")
+			continue
+		}
+		labels = append(labels, node.Value)
+	}
+	modal.AddButtons(labels)
+
+	var done func(buttonIndex int, buttonLabel string)
+	if o.callback("done", &done) {
+		modal.SetDoneFunc(done)
+	}
+
+	return modal
+}
@@ -0,0 +1,231 @@
+package loader
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+)
+
+// mustLoad loads the given document and fails the test if that is not
+// possible.
+func mustLoad(t *testing.T, document string, registry Registry) tview.Primitive {
+	t.Helper()
+	primitive, err := Load([]byte(document), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return primitive
+}
+
+func TestLoadTypes(t *testing.T) {
+	for _, test := range []struct {
+		document string
+		check    func(p tview.Primitive) bool
+	}{
+		{`{type: box, name: b, border: true, title: Box}`, func(p tview.Primitive) bool {
+			box, ok := p.(*tview.Box)
+			return ok && box.Name() == "b"
+		}},
+		{`{type: flex, direction: row, items: [{fixed: 3, view: {type: box}}, {view: {type: box}}]}`, func(p tview.Primitive) bool {
+			flex, ok := p.(*tview.Flex)
+			return ok && flex.GetDirection() == tview.FlexRow && len(flex.GetItems()) == 2 && flex.GetItem(0).FixedSize == 3
+		}},
+		{`{type: pages, pages: [{name: a, view: {type: box}}, {name: b, view: {type: box}}], current: b}`, func(p tview.Primitive) bool {
+			pages, ok := p.(*tview.Pages)
+			return ok && pages.HasPage("a") && pages.GetCurrentPage().Name == "b"
+		}},
+		{`{type: input, label: "Name: ", value: bob, width: 10}`, func(p tview.Primitive) bool {
+			input, ok := p.(*tview.InputField)
+			return ok && input.GetLabel() == "Name: " && input.GetText() == "bob" && input.GetFieldWidth() == 10
+		}},
+		{`{type: password, value: secret, mask: "#"}`, func(p tview.Primitive) bool {
+			input, ok := p.(*tview.InputField)
+			return ok && input.GetText() == "secret"
+		}},
+		{`{type: textarea, label: Notes, value: "a\tb", tabSize: 2}`, func(p tview.Primitive) bool {
+			textArea, ok := p.(*tview.TextArea)
+			return ok && textArea.GetLabel() == "Notes" && textArea.GetText() == "a  b"
+		}},
+		{`{type: checkbox, label: Agree, checked: true}`, func(p tview.Primitive) bool {
+			checkbox, ok := p.(*tview.Checkbox)
+			return ok && checkbox.GetLabel() == "Agree" && checkbox.IsChecked()
+		}},
+		{`{type: dropdown, options: [red, {text: Blue, value: 2}], current: 1}`, func(p tview.Primitive) bool {
+			dropDown, ok := p.(*tview.DropDown)
+			if !ok {
+				return false
+			}
+			index, option := dropDown.GetCurrentOption()
+			return index == 1 && option.Text == "Blue" && option.Value == 2
+		}},
+		{`{type: button, label: OK}`, func(p tview.Primitive) bool {
+			button, ok := p.(*tview.Button)
+			return ok && button.GetLabel() == "OK"
+		}},
+		{`{type: table, selectRows: true, cells: [[a, {text: b, align: right, selectable: false}], [c]]}`, func(p tview.Primitive) bool {
+			table, ok := p.(*tview.Table)
+			if !ok {
+				return false
+			}
+			rows, columns := table.GetSelectable()
+			cell := table.GetCell(0, 1)
+			return rows && !columns && table.GetRowCount() == 2 && cell.Text == "b" && cell.Align == tview.AlignRight && cell.NotSelectable
+		}},
+		{`{type: list, items: [{text: One, secondaryText: first, shortcut: "1"}, {text: Two}]}`, func(p tview.Primitive) bool {
+			list, ok := p.(*tview.List)
+			if !ok {
+				return false
+			}
+			main, secondary := list.GetItemText(0)
+			return list.GetItemCount() == 2 && main == "One" && secondary == "first"
+		}},
+		{`{type: textview, text: Hello, dynamicColors: true, align: center}`, func(p tview.Primitive) bool {
+			textView, ok := p.(*tview.TextView)
+			return ok && textView.GetText() == "Hello"
+		}},
+		{`{type: modal, text: "Quit?", buttons: [Yes, No]}`, func(p tview.Primitive) bool {
+			_, ok := p.(*tview.Modal)
+			return ok
+		}},
+	} {
+		primitive, err := Load([]byte(test.document), nil)
+		if err != nil {
+			t.Errorf("%s: %s", test.document, err)
+		} else if !test.check(primitive) {
+			t.Errorf("%s: built %#v", test.document, primitive)
+		}
+	}
+}
+
+func TestLoadNested(t *testing.T) {
+	var submitted map[string]interface{}
+	registry := Registry{
+		"login": func(values map[string]interface{}) map[string]error {
+			submitted = values
+			return nil
+		},
+		"required": func(value interface{}) error {
+			if value == "" {
+				return errors.New("required")
+			}
+			return nil
+		},
+	}
+	root := mustLoad(t, `
+type: flex
+direction: row
+items:
+  - fixed: 1
+    view:
+      type: textview
+      text: Welcome
+  - view:
+      type: pages
+      pages:
+        - name: login
+          view:
+            type: form
+            name: login
+            items:
+              - type: input
+                name: user
+                label: User
+                validator: required
+              - type: password
+                name: password
+                label: Password
+            buttons:
+              - label: Login
+                submit: true
+            submit: login
+`, registry).(*tview.Flex)
+
+	pages := root.GetItem(1).Item.(*tview.Pages)
+	form := pages.GetPage("login").Item.(*tview.Form)
+	if form.Name() != "login" {
+		t.Errorf("form has name %q", form.Name())
+	}
+	user := form.GetFormItem(0).(*tview.InputField)
+	if form.Submit() {
+		t.Error("form was submitted although the validator of the user field fails")
+	}
+	user.SetText("bob")
+	if !form.Submit() || submitted["user"] != "bob" {
+		t.Errorf("form was not submitted to the registered function (%v)", submitted)
+	}
+}
+
+func TestLoadCallbacks(t *testing.T) {
+	var pressed bool
+	button := mustLoad(t, `{type: button, label: OK, selected: ok}`, Registry{
+		"ok": func() { pressed = true },
+	}).(*tview.Button)
+	button.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	if !pressed {
+		t.Error("registered function was not called")
+	}
+}
+
+func TestLoadProps(t *testing.T) {
+	button := mustLoad(t, `{type: button, label: OK, props: {label: Cancel}}`, nil).(*tview.Button)
+	if label := button.GetLabel(); label != "Cancel" {
+		t.Errorf("label is %q, want the prop's value", label)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, test := range []struct {
+		document     string
+		line, column int
+		message      string
+	}{
+		{"type: grid", 1, 7, `unknown type "grid"`},
+		{"type: box\ncolour: red", 2, 1, `unknown key "colour"`},
+		{"type: flex\nitems:\n  - view:\n      type: box\n      size: 3", 5, 7, `unknown key "size"`},
+		{"type: flex\nitems:\n  - fixed: 1", 3, 5, `missing key "view"`},
+		{"name: x", 1, 1, `missing key "type"`},
+		{"type: input\nwidth: wide", 2, 8, `"width" must be an integer`},
+		{"type: button\nselected: missing", 2, 11, `callback "missing" is not registered`},
+		{"type: table\nselected: ok", 2, 11, `callback "ok" has type func()`},
+		{"type: box\n  border: [", 2, 0, ""},
+	} {
+		_, err := Load([]byte(test.document), Registry{"ok": func() {}})
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%q: got error %v, want an *Error", test.document, err)
+			continue
+		}
+		if e.Line != test.line || test.column > 0 && e.Column != test.column || !strings.Contains(e.Message, test.message) {
+			t.Errorf("%q: got %d:%d %q, want %d:%d %q", test.document, e.Line, e.Column, e.Message, test.line, test.column, test.message)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "view.yaml")
+	if err := ioutil.WriteFile(path, []byte("type: box\nsize: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadFile(path, nil)
+	if e, ok := err.(*Error); !ok || e.Error() != path+`:2:1: unknown key "size"` {
+		t.Errorf("got error %v", err)
+	}
+
+	missing := filepath.Join(dir, "missing.yaml")
+	_, err = LoadFile(missing, nil)
+	if e, ok := err.(*Error); !ok || e.File != missing || e.Line != 0 {
+		t.Errorf("got error %#v for a missing file, want an *Error", err)
+	}
+}
@@ -54,6 +54,19 @@ func (m *Modal) SetTextColor(color tcell.Color) *Modal {
 	return m
 }
 
+// ApplyTheme sets the colors and attributes of the modal and its inner
+// primitives to those of the given theme.
+func (m *Modal) ApplyTheme(theme *Theme) {
+	m.Box.ApplyTheme(theme)
+	m.textColor = theme.PrimaryTextColor
+	m.form.ApplyTheme(theme)
+	m.form.SetButtonBackgroundColor(theme.PrimitiveBackgroundColor).
+		SetButtonTextColor(theme.PrimaryTextColor).
+		SetBackgroundColor(theme.ContrastBackgroundColor)
+	m.frame.ApplyTheme(theme)
+	m.frame.SetBackgroundColor(theme.ContrastBackgroundColor)
+}
+
 // SetDoneFunc sets a handler which is called when one of the buttons was
 // pressed. It receives the index of the button as well as its label text. The
 // handler is also called when the user presses the Escape key. The index will
@@ -86,6 +99,12 @@ func (m *Modal) AddButtons(labels []string) *Modal {
 	return m
 }
 
+// Children returns the modal's frame which contains the form with the
+// modal's buttons.
+func (m *Modal) Children() []Primitive {
+	return []Primitive{m.frame}
+}
+
 // Focus is called when this primitive receives focus.
 func (m *Modal) Focus(delegate func(p Primitive)) {
 	delegate(m.form)
@@ -96,6 +115,18 @@ func (m *Modal) HasFocus() bool {
 	return m.form.HasFocus()
 }
 
+// MouseHandler returns the mouse handler for this primitive. Mouse events are
+// passed on to the modal's buttons. All other mouse events are consumed so
+// that primitives below the modal window don't receive them.
+func (m *Modal) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if handler := m.frame.MouseHandler(); handler != nil {
+			_, capture = handler(action, event, setFocus)
+		}
+		return true, capture
+	})
+}
+
 // Draw draws this primitive onto the screen.
 func (m *Modal) Draw(screen tcell.Screen) {
 	m.Lock()
@@ -0,0 +1,482 @@
+package tview_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// mouseRecorder is a primitive which records the mouse actions it receives.
+// It consumes the actions within its rectangle and, if "captures" is true,
+// captures the mouse from a press until the release of the left button.
+type mouseRecorder struct {
+	*tview.Box
+	captures bool
+	captured bool
+	actions  []tview.MouseAction
+}
+
+func newMouseRecorder(captures bool) *mouseRecorder {
+	return &mouseRecorder{Box: tview.NewBox(), captures: captures}
+}
+
+func (r *mouseRecorder) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return r.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		if !r.captured && !r.InRect(event.Position()) {
+			return false, nil
+		}
+		r.actions = append(r.actions, action)
+		switch {
+		case action == tview.MouseLeftDown && r.captures:
+			r.captured = true
+		case action == tview.MouseLeftUp:
+			r.captured = false
+		}
+		if r.captured {
+			capture = r
+		}
+		return true, capture
+	})
+}
+
+// recorded returns the names of the actions recorded so far, leaving out
+// mouse movements, and clears them.
+func (r *mouseRecorder) recorded(h *tviewtest.Harness) string {
+	var names []string
+	h.Do(func() {
+		for _, action := range r.actions {
+			if action != tview.MouseMove {
+				names = append(names, mouseActionNames[action])
+			}
+		}
+		r.actions = nil
+	})
+	return fmt.Sprint(names)
+}
+
+var mouseActionNames = map[tview.MouseAction]string{
+	tview.MouseMove:              "move",
+	tview.MouseLeftDown:          "left-down",
+	tview.MouseLeftUp:            "left-up",
+	tview.MouseLeftClick:         "left-click",
+	tview.MouseLeftDoubleClick:   "left-double-click",
+	tview.MouseLeftDrag:          "left-drag",
+	tview.MouseMiddleDown:        "middle-down",
+	tview.MouseMiddleUp:          "middle-up",
+	tview.MouseMiddleClick:       "middle-click",
+	tview.MouseMiddleDoubleClick: "middle-double-click",
+	tview.MouseRightDown:         "right-down",
+	tview.MouseRightUp:           "right-up",
+	tview.MouseRightClick:        "right-click",
+	tview.MouseRightDoubleClick:  "right-double-click",
+	tview.MouseScrollUp:          "scroll-up",
+	tview.MouseScrollDown:        "scroll-down",
+	tview.MouseScrollLeft:        "scroll-left",
+	tview.MouseScrollRight:       "scroll-right",
+}
+
+// startMouse runs the given root primitive in a harness with mouse support.
+func startMouse(root tview.Primitive, width, height int) *tviewtest.Harness {
+	h := tviewtest.New(root, width, height)
+	h.Do(func() {
+		h.App.EnableMouse(true)
+	})
+	return h
+}
+
+func TestMouseActions(t *testing.T) {
+	recorder := newMouseRecorder(false)
+	h := startMouse(recorder, 10, 5)
+	defer h.Stop()
+
+	for _, test := range []struct {
+		name   string
+		events func()
+		want   string
+	}{
+		{"click", func() {
+			h.Click(2, 2)
+		}, "[left-down left-up left-click]"},
+		{"double click", func() {
+			h.Click(2, 2)
+		}, "[left-down left-up left-double-click]"},
+		{"click elsewhere", func() {
+			h.Click(6, 2)
+			h.Click(2, 2)
+		}, "[left-down left-up left-click left-down left-up left-click]"},
+		{"drag", func() {
+			h.Mouse(3, 3, tcell.Button1, tcell.ModNone)
+			h.Mouse(4, 3, tcell.Button1, tcell.ModNone)
+			h.Mouse(5, 3, tcell.ButtonNone, tcell.ModNone)
+		}, "[left-down left-drag left-up]"},
+		{"press after move", func() {
+			h.Mouse(1, 1, tcell.ButtonNone, tcell.ModNone)
+			h.Mouse(2, 1, tcell.Button1, tcell.ModNone)
+			h.Mouse(2, 1, tcell.ButtonNone, tcell.ModNone)
+		}, "[left-down left-up left-click]"},
+		{"right click", func() {
+			h.Mouse(1, 1, tcell.Button2, tcell.ModNone)
+			h.Mouse(1, 1, tcell.ButtonNone, tcell.ModNone)
+		}, "[right-down right-up right-click]"},
+		{"middle click", func() {
+			h.Mouse(1, 1, tcell.Button3, tcell.ModNone)
+			h.Mouse(1, 1, tcell.ButtonNone, tcell.ModNone)
+		}, "[middle-down middle-up middle-click]"},
+		{"wheel", func() {
+			h.Mouse(1, 1, tcell.WheelUp, tcell.ModNone)
+			h.Mouse(1, 1, tcell.WheelDown, tcell.ModNone)
+		}, "[scroll-up scroll-down]"},
+	} {
+		test.events()
+		if actions := recorder.recorded(h); actions != test.want {
+			t.Errorf("%s: recorded %s, want %s", test.name, actions, test.want)
+		}
+	}
+}
+
+func TestMouseCapture(t *testing.T) {
+	left, right := newMouseRecorder(true), newMouseRecorder(false)
+	flex := tview.NewFlex().
+		AddItem(left, 5, 0, false).
+		AddItem(right, 5, 0, false)
+	h := startMouse(flex, 10, 3)
+	defer h.Stop()
+
+	// The left recorder receives all events until the button is released.
+	h.Mouse(1, 1, tcell.Button1, tcell.ModNone)
+	h.Mouse(7, 1, tcell.Button1, tcell.ModNone)
+	h.Mouse(8, 1, tcell.ButtonNone, tcell.ModNone)
+	if actions := left.recorded(h); actions != "[left-down left-drag left-up]" {
+		t.Errorf("capturing primitive recorded %s", actions)
+	}
+	if actions := right.recorded(h); actions != "[]" {
+		t.Errorf("primitive under the captured mouse recorded %s", actions)
+	}
+
+	// Then events go to the primitive under the mouse again.
+	h.Click(8, 1)
+	if actions := right.recorded(h); actions != "[left-down left-up left-click]" {
+		t.Errorf("primitive under the released mouse recorded %s", actions)
+	}
+}
+
+func TestBoxInRect(t *testing.T) {
+	box := tview.NewBox()
+	box.SetRect(2, 3, 4, 5)
+	for _, test := range []struct {
+		x, y int
+		want bool
+	}{
+		{2, 3, true},
+		{5, 7, true},
+		{1, 3, false},
+		{2, 2, false},
+		{6, 3, false},
+		{2, 8, false},
+	} {
+		if in := box.InRect(test.x, test.y); in != test.want {
+			t.Errorf("InRect(%d, %d) = %t, want %t", test.x, test.y, in, test.want)
+		}
+	}
+}
+
+func TestMouseFocus(t *testing.T) {
+	list := tview.NewList().AddItem("Item", "", 0, nil)
+	box := tview.NewBox()
+	input := tview.NewInputField()
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(list, 3, 0, true).
+		AddItem(box, 3, 0, false).
+		AddItem(input, 1, 0, false)
+	h := startMouse(flex, 20, 7)
+	defer h.Stop()
+
+	for _, test := range []struct {
+		y    int
+		want tview.Primitive
+	}{
+		{6, input},
+		{4, box},
+		{0, list},
+	} {
+		h.Click(1, test.y)
+		if p := focus(h); p != test.want {
+			t.Errorf("click on line %d focused %T, want %T", test.y, p, test.want)
+		}
+	}
+}
+
+func TestButtonMouse(t *testing.T) {
+	var pressed int
+	button := tview.NewButton("OK").SetSelectedFunc(func() {
+		pressed++
+	})
+	flex := tview.NewFlex().
+		AddItem(tview.NewBox(), 5, 0, true).
+		AddItem(button, 5, 0, false)
+	h := startMouse(flex, 10, 1)
+	defer h.Stop()
+
+	// Releasing the button elsewhere does not press it.
+	h.Mouse(6, 0, tcell.Button1, tcell.ModNone)
+	h.Mouse(2, 0, tcell.ButtonNone, tcell.ModNone)
+	h.Click(6, 0)
+	var count int
+	h.Do(func() {
+		count = pressed
+	})
+	if count != 1 {
+		t.Errorf("button was pressed %d times, want once", count)
+	}
+	if p := focus(h); p != button {
+		t.Errorf("clicking the button focused %T", p)
+	}
+}
+
+func TestCheckboxMouse(t *testing.T) {
+	checkbox := tview.NewCheckbox().SetLabel("Check ")
+	h := startMouse(checkbox, 10, 1)
+	defer h.Stop()
+
+	h.Click(6, 0)
+	var checked bool
+	h.Do(func() {
+		checked = checkbox.IsChecked()
+	})
+	if !checked {
+		t.Error("clicking the checkbox did not check it")
+	}
+}
+
+func TestListMouse(t *testing.T) {
+	var selected []int
+	list := tview.NewList().ShowSecondaryText(false).
+		AddItem("a", "", 0, nil).
+		AddItem("b", "", 0, nil).
+		AddItem("c", "", 0, nil).
+		SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+			selected = append(selected, index)
+		})
+	h := startMouse(list, 10, 3)
+	defer h.Stop()
+
+	// current returns the list's current item.
+	current := func() (item int) {
+		h.Do(func() {
+			item = list.GetCurrentItem()
+		})
+		return
+	}
+
+	h.Click(0, 2)
+	if item := current(); item != 2 {
+		t.Errorf("click moved to item %d, want 2", item)
+	}
+	h.Mouse(0, 1, tcell.WheelUp, tcell.ModNone)
+	if item := current(); item != 1 {
+		t.Errorf("scrolling up moved to item %d, want 1", item)
+	}
+	var result string
+	h.Do(func() {
+		result = fmt.Sprint(selected)
+	})
+	if result != "[2]" {
+		t.Errorf("selected handler received %s, want [2]", result)
+	}
+}
+
+func TestDropDownMouse(t *testing.T) {
+	dropDown := tview.NewDropDown().
+		AddOption("one", nil, nil).
+		AddOption("two", nil, nil).
+		AddOption("three", nil, nil)
+	h := startMouse(dropDown, 10, 5)
+	defer h.Stop()
+
+	// current returns the index of the drop-down's current option.
+	current := func() (index int) {
+		h.Do(func() {
+			index, _ = dropDown.GetCurrentOption()
+		})
+		return
+	}
+
+	// Clicking opens the list, clicking an option selects it.
+	h.Click(0, 0)
+	h.Click(1, 2)
+	if index := current(); index != 1 {
+		t.Errorf("clicking an option selected option %d, want 1", index)
+	}
+	if p := focus(h); p != dropDown {
+		t.Errorf("focus is on %T after selecting an option, want the drop-down", p)
+	}
+
+	// A click outside of the open list closes it without a change.
+	h.Click(0, 0)
+	h.Click(9, 4)
+	if index := current(); index != 1 {
+		t.Errorf("clicking outside of the list selected option %d", index)
+	}
+	if p := focus(h); p != dropDown {
+		t.Errorf("focus is on %T after closing the list, want the drop-down", p)
+	}
+}
+
+func TestTableMouse(t *testing.T) {
+	table := newTable([][]string{
+		{"a", "b", "c"},
+		{"d", "e", "f"},
+		{"g", "h", "i"},
+	}).SetSelectable(true, true)
+	h := startMouse(table, 10, 3)
+	defer h.Stop()
+
+	h.Click(2, 1)
+	var row, column int
+	h.Do(func() {
+		row, column = table.GetSelection()
+	})
+	if row != 1 || column != 1 {
+		t.Errorf("click selected cell %d/%d, want 1/1", row, column)
+	}
+}
+
+func TestInputFieldMouse(t *testing.T) {
+	input := tview.NewInputField().SetLabel("> ").SetText("hello")
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 1, 0, true).
+		AddItem(input, 1, 0, false)
+	h := startMouse(flex, 20, 2)
+	defer h.Stop()
+
+	// Clicking moves the cursor to the clicked character.
+	h.Click(4, 1)
+	h.Type("X")
+	if text := inputText(h, input); text != "heXllo" {
+		t.Errorf("typing after a click resulted in %q, want %q", text, "heXllo")
+	}
+}
+
+// inputText returns the text of the given input field.
+func inputText(h *tviewtest.Harness, input *tview.InputField) (text string) {
+	h.Do(func() {
+		text = input.GetText()
+	})
+	return
+}
+
+func TestPagesMouse(t *testing.T) {
+	var pressed []string
+	back := tview.NewButton("Back").SetSelectedFunc(func() {
+		pressed = append(pressed, "back")
+	})
+	front := tview.NewButton("Front").SetSelectedFunc(func() {
+		pressed = append(pressed, "front")
+	})
+	front.SetRect(0, 0, 5, 1)
+	pages := tview.NewPages().
+		AddPage("back", back, true, true).
+		AddPage("front", front, false, true)
+	h := startMouse(pages, 10, 3)
+	defer h.Stop()
+
+	// The page on top receives the events first.
+	h.Click(1, 0)
+	h.Click(1, 2)
+	h.Do(func() {
+		pages.HidePage("front")
+	})
+	h.Click(1, 0)
+	var result string
+	h.Do(func() {
+		result = fmt.Sprint(pressed)
+	})
+	if result != "[front back back]" {
+		t.Errorf("buttons were pressed in the order %s, want [front back back]", result)
+	}
+}
+
+func TestFormMouseValidation(t *testing.T) {
+	required := func(value interface{}) error {
+		if value == "" {
+			return fmt.Errorf("required")
+		}
+		return nil
+	}
+	name := tview.NewInputField().SetLabel("Name")
+	name.SetName("name")
+	color := tview.NewDropDown().SetLabel("Color").
+		AddOption("red", nil, nil).
+		AddOption("blue", nil, nil)
+	color.SetName("color")
+	form := tview.NewForm().
+		AddFormItem(name).
+		AddFormItem(color).
+		SetItemValidator(0, required).
+		SetItemValidator(1, func(value interface{}) error {
+			if index, _ := color.GetCurrentOption(); index < 0 {
+				return fmt.Errorf("required")
+			}
+			return nil
+		})
+	h := startMouse(form, 20, 8)
+	defer h.Stop()
+
+	// itemError returns the validation error of the form item with the given
+	// index.
+	itemError := func(index int) (err error) {
+		h.Do(func() {
+			err = form.GetItemError(index)
+		})
+		return
+	}
+
+	// Clicking the drop-down leaves the input field and opens the list.
+	h.Click(8, 3)
+	if itemError(0) == nil {
+		t.Error("input field was not validated when it was left with the mouse")
+	}
+	if itemError(1) != nil {
+		t.Error("drop-down was validated when its list was opened")
+	}
+
+	// Selecting an option and going back to the input field validates the
+	// drop-down.
+	h.Click(8, 5)
+	h.Click(8, 1)
+	if err := itemError(1); err != nil {
+		t.Errorf("drop-down with a selected option has error %v", err)
+	}
+	if p := focus(h); p != name {
+		t.Errorf("focus is on %T, want the input field", p)
+	}
+}
+
+func TestFrameMouse(t *testing.T) {
+	button := tview.NewButton("OK")
+	frame := tview.NewFrame(button).SetBorders(1, 1, 0, 0, 1, 1)
+	flex := tview.NewFlex().
+		AddItem(tview.NewBox(), 0, 1, true).
+		AddItem(frame, 0, 1, false)
+	h := startMouse(flex, 20, 5)
+	defer h.Stop()
+
+	// Clicking the frame's border focuses the contained primitive.
+	h.Click(10, 0)
+	if p := focus(h); p != button {
+		t.Errorf("focus is on %T after clicking the frame, want the button", p)
+	}
+
+	// A frame without a primitive must not panic.
+	empty := tview.NewFrame(nil)
+	h.Do(func() {
+		flex.SetItem(1, empty, 0, 1, false)
+	})
+	h.Click(12, 2)
+	if p := focus(h); p != empty {
+		t.Errorf("focus is on %T after clicking an empty frame, want the frame", p)
+	}
+}
@@ -0,0 +1,117 @@
+package tview
+
+import "sort"
+
+// multiSelection is a set of selected indices, e.g. the rows of a table or the
+// items of a list, in addition to the current row or item. A range of indices
+// can be selected starting at an anchor.
+type multiSelection struct {
+	// The selected indices.
+	indices map[int]bool
+
+	// The index at which the current range selection started and the indices
+	// which were selected at that time (nil if no range selection was started).
+	anchor int
+	base   map[int]bool
+}
+
+// has returns whether the given index is selected.
+func (m *multiSelection) has(index int) bool {
+	return m.indices[index]
+}
+
+// list returns the selected indices in ascending order.
+func (m *multiSelection) list() []int {
+	list := make([]int, 0, len(m.indices))
+	for index := range m.indices {
+		list = append(list, index)
+	}
+	sort.Ints(list)
+	return list
+}
+
+// set replaces the selected indices.
+func (m *multiSelection) set(indices []int) {
+	m.indices = make(map[int]bool, len(indices))
+	for _, index := range indices {
+		m.indices[index] = true
+	}
+	m.endRange()
+}
+
+// toggle selects the given index if it is not selected and unselects it
+// otherwise.
+func (m *multiSelection) toggle(index int) {
+	if m.indices[index] {
+		delete(m.indices, index)
+	} else {
+		if m.indices == nil {
+			m.indices = make(map[int]bool)
+		}
+		m.indices[index] = true
+	}
+	m.endRange()
+}
+
+// startRange starts a range selection at the given index unless one was
+// already started.
+func (m *multiSelection) startRange(anchor int) {
+	if m.base != nil {
+		return
+	}
+	m.anchor = anchor
+	m.base = make(map[int]bool, len(m.indices))
+	for index := range m.indices {
+		m.base[index] = true
+	}
+}
+
+// setRange selects the given indices in addition to those which were selected
+// when the range selection was started.
+func (m *multiSelection) setRange(indices []int) {
+	m.indices = make(map[int]bool, len(m.base)+len(indices))
+	for index := range m.base {
+		m.indices[index] = true
+	}
+	for _, index := range indices {
+		m.indices[index] = true
+	}
+}
+
+// endRange ends the current range selection, if any.
+func (m *multiSelection) endRange() {
+	m.base = nil
+}
+
+// ranging returns whether a range selection was started.
+func (m *multiSelection) ranging() bool {
+	return m.base != nil
+}
+
+// remap moves the selected indices, e.g. after rows were sorted or removed.
+// The function returns the new index for an old index or a negative value if
+// the index no longer exists.
+func (m *multiSelection) remap(index func(int) int) {
+	indices := make(map[int]bool, len(m.indices))
+	for old := range m.indices {
+		if i := index(old); i >= 0 {
+			indices[i] = true
+		}
+	}
+	m.indices = indices
+	m.endRange()
+}
+
+// equal returns whether the given indices, as returned by list(), are the
+// selected indices.
+func (m *multiSelection) equal(list []int) bool {
+	if len(list) != len(m.indices) {
+		return false
+	}
+	for _, index := range list {
+		if !m.indices[index] {
+			return false
+		}
+	}
+	return true
+}
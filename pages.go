@@ -240,6 +240,32 @@ func (p *Pages) SendToBack(name string) *Pages {
 	return p
 }
 
+// Children returns the primitives of all pages, visible or not.
+func (p *Pages) Children() []Primitive {
+	//p.RLock()
+	//defer p.RUnlock()
+
+	children := make([]Primitive, len(p.pages))
+	for index, page := range p.pages {
+		children[index] = page.Item
+	}
+	return children
+}
+
+// visibleChildren returns the primitives of the visible pages.
+func (p *Pages) visibleChildren() []Primitive {
+	//p.RLock()
+	//defer p.RUnlock()
+
+	var children []Primitive
+	for _, page := range p.pages {
+		if page.Visible {
+			children = append(children, page.Item)
+		}
+	}
+	return children
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (p *Pages) HasFocus() bool {
 	//p.RLock()
@@ -282,6 +308,35 @@ func (p *Pages) Focus(delegate func(p Primitive)) {
 	}
 }
 
+// MouseHandler returns the mouse handler for this primitive. Mouse events are
+// passed on to the visible pages, starting with the one drawn last (i.e. the
+// one on top), until one of them consumes the event.
+func (p *Pages) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return p.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !p.InRect(event.Position()) {
+			return false, nil
+		}
+
+		// Pass mouse events along to the top-most visible page that takes it.
+		for index := len(p.pages) - 1; index >= 0; index-- {
+			page := p.pages[index]
+			if !page.Visible {
+				continue
+			}
+			handler := page.Item.MouseHandler()
+			if handler == nil {
+				continue
+			}
+			consumed, capture = handler(action, event, setFocus)
+			if consumed {
+				return
+			}
+		}
+
+		return
+	})
+}
+
 // SwitchToPage sets a page's visibility to "true" and all other pages'
 // visibility to "false".
 func (p *Pages) SwitchToPage(name string, context map[string]interface{}) *Pages {
@@ -7,6 +7,10 @@ type Primitive interface {
 	// everything should have a unique Id, defaults to a uuid
 	Id() string
 
+	// Name returns the primitive's name for human-readable usage, e.g. to look
+	// it up in the primitive tree (see FindByName()). It may be empty.
+	Name() string
+
 	// Draw draws this primitive onto the screen. Implementers can call the
 	// screen's ShowCursor() function but should only do so when they have focus.
 	// (They will need to keep track of this themselves.)
@@ -37,6 +41,27 @@ type Primitive interface {
 	// Box.wrapInputHandler() so you inherit that functionality.
 	InputHandler() func(event tcell.Event, setFocus func(p Primitive))
 
+	// MouseHandler returns a handler which receives mouse events. It is called
+	// by the Application class.
+	//
+	// A value of nil may also be returned to stop the downward propagation of
+	// mouse events.
+	//
+	// The handler receives the mouse action derived from the event (see
+	// MouseAction), the original tcell event, and a function that allows it to
+	// set the focus to a different primitive. It returns whether or not the
+	// event was consumed. It may also return a primitive which will then receive
+	// all subsequent mouse events directly, regardless of where they occur on
+	// screen, until a handler returns nil again. This is useful for dragging.
+	//
+	// The Application's Draw() function will be called automatically if the
+	// event was consumed.
+	//
+	// The Box class provides functionality to intercept mouse events. If you
+	// subclass from Box, it is recommended that you wrap your handler using
+	// Box.wrapMouseHandler() so you inherit that functionality.
+	MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive)
+
 	// Focus is called by the application when the primitive receives focus.
 	// Implementers may call delegate() to pass the focus on to another primitive.
 	Focus(delegate func(p Primitive))
@@ -78,4 +103,9 @@ type Primitive interface {
 	// SetProps replaces the primitive's props
 	// It is up to the implementor to ensure correctness.
 	SetProps(newProps map[string]interface{}) error
+
+	// SubscribeProp adds a handler which is called when the given prop changes
+	// (all props if the name is empty). It returns a function which removes
+	// the handler.
+	SubscribeProp(prop string, handler func(prop string, value interface{})) (unsubscribe func())
 }
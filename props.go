@@ -0,0 +1,374 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// PropSpec declares a prop of a primitive. Declared props are type-checked by
+// SetProp() and SetProps().
+//
+// A prop may be bound to the state of its primitive by providing Get and Set
+// functions. Bound props are not stored separately: GetProp() returns the
+// result of Get and SetProp() calls Set. This is how primitives expose their
+// state (e.g. the label of a Button) as props so that they can be driven with
+// SetProp(). Unbound props are stored in the primitive.
+type PropSpec struct {
+	// The prop's name.
+	Name string
+
+	// The type of the prop's values. Values which are not assignable to this
+	// type are rejected unless both are numeric, in which case the value is
+	// converted if that is possible without loss (e.g. 3.0 to an int but not
+	// 3.5, 300 to a uint8, or -1 to a uint). A nil type accepts any value.
+	Type reflect.Type
+
+	// The value of an unbound prop until it is set. Props missing from the map
+	// provided to SetProps() are reset to this value or, if it is nil, to the
+	// zero value of the prop's type.
+	Default interface{}
+
+	// Optional functions which read and change the state the prop is bound to.
+	// Set is called with a value of the prop's type. If Set does not notify
+	// the prop's subscribers itself (as the setters of primitives do, e.g.
+	// Button.SetLabel()), SetProp() notifies them if the value returned by Get
+	// changed.
+	Get func() interface{}
+	Set func(value interface{})
+}
+
+// propSubscription is a handler subscribed to prop changes.
+type propSubscription struct {
+	prop    string // The prop's name or an empty string for all props.
+	handler func(prop string, value interface{})
+}
+
+// DeclareProps adds the given props to the primitive's schema, replacing any
+// props with the same names. Unbound props are set to their default values
+// without notifying subscribers. An error is returned if a default value does
+// not match its prop's type, in which case no props are declared.
+//
+// Once the primitive has declared props, only declared props can be set.
+func (b *Box) DeclareProps(specs ...PropSpec) error {
+	//b.Lock()
+	//defer b.Unlock()
+
+	defaults := make([]interface{}, len(specs))
+	for index, spec := range specs {
+		if spec.Name == "" {
+			return fmt.Errorf("prop %d has no name", index)
+		}
+		if spec.Default == nil {
+			continue
+		}
+		value, err := checkPropValue(spec, spec.Default)
+		if err != nil {
+			return err
+		}
+		defaults[index] = value
+	}
+
+	for index, spec := range specs {
+		b.propSpecs[spec.Name] = spec
+		if spec.Get == nil && spec.Set == nil {
+			b.props[spec.Name] = defaults[index]
+		} else {
+			delete(b.props, spec.Name)
+		}
+	}
+	return nil
+}
+
+// GetPropSchema returns the props declared by the primitive, keyed by their
+// names.
+func (b *Box) GetPropSchema() map[string]PropSpec {
+	//b.RLock()
+	//defer b.RUnlock()
+
+	schema := make(map[string]PropSpec, len(b.propSpecs))
+	for name, spec := range b.propSpecs {
+		schema[name] = spec
+	}
+	return schema
+}
+
+// GetProp returns the value of the prop with the given name and whether or not
+// the prop was declared or set.
+func (b *Box) GetProp(prop string) (interface{}, bool) {
+	//b.RLock()
+	//defer b.RUnlock()
+
+	if spec, ok := b.propSpecs[prop]; ok && spec.Get != nil {
+		return spec.Get(), true
+	}
+	value, ok := b.props[prop]
+	return value, ok
+}
+
+// GetProps returns a copy of all props, including bound props.
+func (b *Box) GetProps() map[string]interface{} {
+	//b.RLock()
+	//defer b.RUnlock()
+
+	props := make(map[string]interface{}, len(b.props))
+	for name, value := range b.props {
+		props[name] = value
+	}
+	for name, spec := range b.propSpecs {
+		if spec.Get != nil {
+			props[name] = spec.Get()
+		}
+	}
+	return props
+}
+
+// SetProp sets the value of the prop with the given name and notifies the
+// subscribers of that prop if the value changed. If the primitive declared
+// props, an error is returned if the prop is not declared or if the value does
+// not match the prop's type.
+func (b *Box) SetProp(prop string, value interface{}) error {
+	value, err := b.checkProp(prop, value)
+	if err != nil {
+		return err
+	}
+	b.setProp(prop, value)
+	return nil
+}
+
+// SetProps replaces the primitive's props with the given ones. Declared props
+// which are missing from the map are reset to their default values, other
+// props which are missing are removed. Subscribers are notified of all
+// changed values. If any of the values is rejected (see SetProp()), an error
+// is returned and no props are changed.
+func (b *Box) SetProps(newProps map[string]interface{}) error {
+	checked := make(map[string]interface{}, len(newProps))
+	for prop, value := range newProps {
+		value, err := b.checkProp(prop, value)
+		if err != nil {
+			return err
+		}
+		checked[prop] = value
+	}
+
+	for prop, spec := range b.propSpecs {
+		if _, ok := checked[prop]; !ok {
+			value := spec.Default
+			if value == nil && spec.Type != nil {
+				value = reflect.Zero(spec.Type).Interface()
+			}
+			value, err := checkPropValue(spec, value)
+			if err != nil {
+				return err
+			}
+			checked[prop] = value
+		}
+	}
+	for prop := range b.props {
+		if _, ok := checked[prop]; !ok {
+			//b.Lock()
+			delete(b.props, prop)
+			//b.Unlock()
+			b.notifyProp(prop, nil)
+		}
+	}
+	for prop, value := range checked {
+		b.setProp(prop, value)
+	}
+	return nil
+}
+
+// SubscribeProp adds a handler which is called with the name and the new value
+// of the prop with the given name whenever it changes. Provide an empty name
+// to be notified of changes of all props. Handlers are called when props are
+// changed with SetProp() or SetProps(), when the state of a bound prop is
+// changed with the primitive's setters (e.g. Button.SetLabel(),
+// InputField.SetText(), or Table.SetCell()), and when a primitive changes a
+// prop in response to user input (e.g. the text of an InputField).
+//
+// Values are compared to find out if a prop changed. Slices and maps are
+// compared by identity, not by their contents.
+//
+// The returned function removes the handler again.
+func (b *Box) SubscribeProp(prop string, handler func(prop string, value interface{})) (unsubscribe func()) {
+	//b.Lock()
+	//defer b.Unlock()
+
+	subscription := &propSubscription{prop: prop, handler: handler}
+	b.propSubscriptions = append(b.propSubscriptions, subscription)
+	return func() {
+		//b.Lock()
+		//defer b.Unlock()
+
+		for index, s := range b.propSubscriptions {
+			if s == subscription {
+				b.propSubscriptions = append(b.propSubscriptions[:index], b.propSubscriptions[index+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// checkProp checks if the given value may be assigned to the prop with the
+// given name and returns it, converted to the prop's type if necessary.
+func (b *Box) checkProp(prop string, value interface{}) (interface{}, error) {
+	//b.RLock()
+	//defer b.RUnlock()
+
+	if len(b.propSpecs) == 0 {
+		return value, nil // Nothing declared, anything goes.
+	}
+	spec, ok := b.propSpecs[prop]
+	if !ok {
+		return nil, fmt.Errorf("unknown prop %q", prop)
+	}
+	return checkPropValue(spec, value)
+}
+
+// setProp sets a checked prop value and notifies subscribers if it changed.
+// The setters of bound props may notify subscribers themselves, in which case
+// they are not notified again.
+func (b *Box) setProp(prop string, value interface{}) {
+	current, _ := b.GetProp(prop)
+	notifications := b.propNotifications
+	if spec, ok := b.propSpecs[prop]; ok && spec.Set != nil {
+		spec.Set(value)
+	} else {
+		//b.Lock()
+		b.props[prop] = value
+		//b.Unlock()
+	}
+	if b.propNotifications == notifications && !samePropValue(current, value) {
+		b.notifyProp(prop, value)
+	}
+}
+
+// notifyProp calls all handlers subscribed to the prop with the given name.
+func (b *Box) notifyProp(prop string, value interface{}) {
+	//b.Lock()
+	b.propNotifications++
+	subscriptions := append([]*propSubscription(nil), b.propSubscriptions...)
+	//b.Unlock()
+
+	for _, subscription := range subscriptions {
+		if subscription.prop == "" || subscription.prop == prop {
+			subscription.handler(prop, value)
+		}
+	}
+}
+
+// checkPropValue checks if the given value matches the prop's type and
+// returns it, converted to the prop's type if necessary. A nil value is
+// accepted for types which can be nil and returned as the type's zero value.
+func checkPropValue(spec PropSpec, value interface{}) (interface{}, error) {
+	if spec.Type == nil {
+		return value, nil
+	}
+
+	if value == nil {
+		switch spec.Type.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+			return reflect.Zero(spec.Type).Interface(), nil
+		}
+		return nil, fmt.Errorf("prop %q of type %s cannot be nil", spec.Name, spec.Type)
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.AssignableTo(spec.Type) {
+		if spec.Type.Kind() == reflect.Interface {
+			return value, nil
+		}
+		return reflect.ValueOf(value).Convert(spec.Type).Interface(), nil
+	}
+	if isNumericKind(valueType.Kind()) && isNumericKind(spec.Type.Kind()) {
+		if !convertsExactly(reflect.ValueOf(value), spec.Type) {
+			return nil, fmt.Errorf("prop %q of type %s cannot hold the value %v", spec.Name, spec.Type, value)
+		}
+		return reflect.ValueOf(value).Convert(spec.Type).Interface(), nil
+	}
+	return nil, fmt.Errorf("prop %q expects a value of type %s, got %s", spec.Name, spec.Type, valueType)
+}
+
+// convertsExactly returns whether or not the given numeric value can be
+// converted to the given numeric type without changing it, i.e. without
+// dropping a fraction, overflowing, or wrapping a negative number around.
+func convertsExactly(value reflect.Value, to reflect.Type) bool {
+	target := reflect.Zero(to)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := value.Int()
+		switch {
+		case isIntKind(to.Kind()):
+			return !target.OverflowInt(n)
+		case isUintKind(to.Kind()):
+			return n >= 0 && !target.OverflowUint(uint64(n))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := value.Uint()
+		switch {
+		case isIntKind(to.Kind()):
+			return n <= math.MaxInt64 && !target.OverflowInt(int64(n))
+		case isUintKind(to.Kind()):
+			return !target.OverflowUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		switch {
+		case isIntKind(to.Kind()):
+			return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !target.OverflowInt(int64(f))
+		case isUintKind(to.Kind()):
+			return f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !target.OverflowUint(uint64(f))
+		default:
+			return !target.OverflowFloat(f)
+		}
+	}
+	return true // Integers fit into floating point numbers.
+}
+
+// samePropValue returns whether or not two prop values are the same. Slices
+// and maps are the same if they are identical, functions are never the same.
+func samePropValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	valueA, valueB := reflect.ValueOf(a), reflect.ValueOf(b)
+	if valueA.Type() != valueB.Type() {
+		return false
+	}
+	switch valueA.Kind() {
+	case reflect.Slice:
+		return valueA.Pointer() == valueB.Pointer() && valueA.Len() == valueB.Len()
+	case reflect.Map:
+		return valueA.Pointer() == valueB.Pointer()
+	case reflect.Func:
+		return false
+	}
+	if !valueA.Type().Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// isNumericKind returns whether or not the given kind is an integer or a
+// floating point number.
+func isNumericKind(kind reflect.Kind) bool {
+	return isIntKind(kind) || isUintKind(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// isIntKind returns whether or not the given kind is a signed integer.
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isUintKind returns whether or not the given kind is an unsigned integer.
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package tview
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetProp(t *testing.T) {
+	button := NewButton("OK")
+	var changes []interface{}
+	unsubscribe := button.SubscribeProp("label", func(prop string, value interface{}) {
+		changes = append(changes, value)
+	})
+
+	if err := button.SetProp("label", 3); err == nil {
+		t.Error("SetProp() accepted a value of the wrong type")
+	}
+	if err := button.SetProp("unknown", "x"); err == nil {
+		t.Error("SetProp() accepted an undeclared prop")
+	}
+	if err := button.SetProp("label", "Go"); err != nil {
+		t.Fatal(err)
+	}
+	if label := button.GetLabel(); label != "Go" {
+		t.Errorf("label is %q, want %q", label, "Go")
+	}
+	button.SetProp("label", "Go")
+	unsubscribe()
+	button.SetProp("label", "Stop")
+	if len(changes) != 1 || changes[0] != "Go" {
+		t.Errorf("subscriber was notified of %v, want [Go]", changes)
+	}
+}
+
+func TestUndeclaredProps(t *testing.T) {
+	box := NewBox()
+	if err := box.SetProp("count", 1); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := box.GetProp("count"); !ok || value != 1 {
+		t.Errorf("GetProp() = %v, %v", value, ok)
+	}
+	box.SetProps(map[string]interface{}{})
+	if _, ok := box.GetProp("count"); ok {
+		t.Error("SetProps() did not remove a missing prop")
+	}
+}
+
+func TestDeclareProps(t *testing.T) {
+	box := NewBox()
+	if err := box.DeclareProps(PropSpec{Name: "size", Type: reflect.TypeOf(0), Default: "large"}); err == nil {
+		t.Error("DeclareProps() accepted a default of the wrong type")
+	}
+	if err := box.DeclareProps(PropSpec{Name: "size", Type: reflect.TypeOf(0), Default: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := box.SetProp("size", 2.0); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := box.GetProp("size"); value != 2 {
+		t.Errorf("numeric value was converted to %#v, want 2", value)
+	}
+}
+
+func TestPropConversions(t *testing.T) {
+	for _, test := range []struct {
+		to    interface{}
+		value interface{}
+		ok    bool
+	}{
+		{int(0), 3.0, true},
+		{int(0), 3.5, false},
+		{int(0), int64(-7), true},
+		{int8(0), 127, true},
+		{int8(0), 128, false},
+		{uint8(0), 255, true},
+		{uint8(0), 300, false},
+		{uint(0), -1, false},
+		{uint(0), -1.0, false},
+		{int64(0), uint64(1) << 63, false},
+		{int64(0), 1e19, false},
+		{float32(0), 1e39, false},
+		{float32(0), 2, true},
+		{float64(0), uint64(1) << 63, true},
+	} {
+		spec := PropSpec{Name: "n", Type: reflect.TypeOf(test.to)}
+		value, err := checkPropValue(spec, test.value)
+		if test.ok && (err != nil || reflect.TypeOf(value) != spec.Type) {
+			t.Errorf("%T(%v) to %s: got %#v, %v", test.value, test.value, spec.Type, value, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("%T(%v) to %s: converted to %#v, want an error", test.value, test.value, spec.Type, value)
+		}
+	}
+}
+
+func TestSettersNotify(t *testing.T) {
+	// notifications returns a subscriber which records the values of the
+	// given prop.
+	notifications := func(p Primitive, prop string) *[]interface{} {
+		var values []interface{}
+		p.SubscribeProp(prop, func(prop string, value interface{}) {
+			values = append(values, value)
+		})
+		return &values
+	}
+
+	button := NewButton("OK")
+	labels := notifications(button, "label")
+	button.SetLabel("Go")
+	button.SetLabel("Go")
+	button.SetProp("label", "Stop")
+	if !reflect.DeepEqual(*labels, []interface{}{"Go", "Stop"}) {
+		t.Errorf("label subscriber was notified of %v, want [Go Stop]", *labels)
+	}
+
+	input := NewInputField()
+	texts := notifications(input, "text")
+	input.SetText("a")
+	input.SetProp("text", "b")
+	input.SetProp("text", "b")
+	if !reflect.DeepEqual(*texts, []interface{}{"a", "b"}) {
+		t.Errorf("text subscriber was notified of %v, want [a b]", *texts)
+	}
+
+	table := NewTable()
+	cells := notifications(table, "cells")
+	table.SetCell(0, 1, NewTableCell("a"))
+	table.SetProp("cells", [][]*TableCell{{NewTableCell("b")}})
+	if len(*cells) != 2 {
+		t.Fatalf("cells subscriber was notified %d times, want 2", len(*cells))
+	}
+	if rows := (*cells)[0].([][]*TableCell); len(rows) != 1 || len(rows[0]) != 2 || rows[0][1].Text != "a" {
+		t.Errorf("cells subscriber was notified of %v after SetCell()", rows)
+	}
+}
+
+func TestSetPropsResetsMissingProps(t *testing.T) {
+	button := NewButton("OK")
+	if err := button.SetProps(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if label := button.GetLabel(); label != "" {
+		t.Errorf("label is %q, want it to be reset", label)
+	}
+
+	input := NewInputField().SetText("text")
+	if err := input.SetProps(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if text := input.GetText(); text != "" {
+		t.Errorf("text is %q, want it to be reset", text)
+	}
+
+	// Props without a default are reset to their type's zero value.
+	box := NewBox()
+	var changed interface{}
+	box.DeclareProps(PropSpec{
+		Name: "count",
+		Type: reflect.TypeOf(0),
+		Get:  func() interface{} { return 5 },
+		Set:  func(value interface{}) { changed = value },
+	})
+	if err := box.SetProps(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if changed != 0 {
+		t.Errorf("prop without a default was reset to %#v, want 0", changed)
+	}
+}
+
+func TestTableCellsProp(t *testing.T) {
+	table := NewTable()
+	cells := [][]*TableCell{{NewTableCell("a"), nil, NewTableCell("b")}}
+	if err := table.SetProp("cells", cells); err != nil {
+		t.Fatal(err)
+	}
+	if table.GetColumnCount() != 3 || table.GetCell(0, 2).Text != "b" {
+		t.Error("cells prop did not set the table's cells")
+	}
+
+	// The returned cells are a copy of the table's rows.
+	value, _ := table.GetProp("cells")
+	value.([][]*TableCell)[0][0] = NewTableCell("c")
+	if table.GetCell(0, 0).Text != "a" {
+		t.Error("changing the cells prop's value changed the table")
+	}
+
+	if err := table.SetProps(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if table.GetRowCount() != 0 {
+		t.Errorf("table has %d rows after resetting its cells", table.GetRowCount())
+	}
+}
@@ -0,0 +1,314 @@
+package tview
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RouteMatch describes a path which was matched against a route.
+type RouteMatch struct {
+	Path   string            // The path that was navigated to, e.g. "/users/42".
+	Route  string            // The pattern of the matching route, e.g. "/users/:id".
+	Params map[string]string // The parameters parsed from the path, e.g. "id" -> "42".
+}
+
+// route is one route registered with a Router.
+type route struct {
+	pattern  string
+	segments []string
+	factory  func() Primitive
+}
+
+// match returns the parameters parsed from the given path segments and
+// whether or not they match the route.
+func (r *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for index, segment := range r.segments {
+		if strings.HasPrefix(segment, ":") {
+			if segments[index] == "" {
+				return nil, false
+			}
+			params[segment[1:]] = segments[index]
+		} else if segment != segments[index] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// samePath returns whether the two given paths consist of the same segments.
+func samePath(a, b string) bool {
+	return strings.Join(splitPath(a), "/") == strings.Join(splitPath(b), "/")
+}
+
+// routeContext returns the context passed to the primitive of the given route
+// when it is shown (see Router).
+func routeContext(match *RouteMatch) map[string]interface{} {
+	return map[string]interface{}{
+		"path":   match.Path,
+		"route":  match.Route,
+		"params": match.Params,
+	}
+}
+
+// splitPath splits a path into its segments, ignoring leading and trailing
+// slashes.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// Router is a Pages container which shows one page per route. Routes are
+// registered with a pattern such as "/users/:id" where segments starting with
+// a colon are parameters. Navigating to a path such as "/users/42" switches to
+// the page of the first matching route and passes the following keys in the
+// context given to the page's Mount() or Refresh() functions:
+//
+//   "path":   The path that was navigated to ("/users/42").
+//   "route":  The pattern of the matching route ("/users/:id").
+//   "params": The parsed parameters (map[string]string{"id": "42"}).
+//
+// The primitive of a route is created by the route's factory function the first
+// time the route is navigated to. Navigating to another path matching the same
+// route (e.g. from "/users/42" to "/users/7") calls Refresh() on that
+// primitive instead of mounting it again.
+//
+// The router keeps a history of visited paths which can be traversed with
+// Back() and Forward(). Route guards may veto navigation.
+type Router struct {
+	*Pages
+
+	// The registered routes, in the order they were added.
+	routes []*route
+
+	// The currently shown route or nil if there is none.
+	current *RouteMatch
+
+	// The visited paths and the index of the current path in that list.
+	history      []string
+	historyIndex int
+
+	// Functions which are called before navigating. If one of them returns an
+	// error, navigation is aborted.
+	guards []func(from, to *RouteMatch) error
+
+	// An optional handler which is called after the route changed.
+	routeChanged func(from, to *RouteMatch)
+}
+
+// NewRouter returns a new Router without any routes.
+func NewRouter() *Router {
+	r := &Router{
+		Pages:        NewPages(),
+		historyIndex: -1,
+	}
+	r.focus = r
+	return r
+}
+
+// AddRoute registers a route with the given pattern. The factory function
+// returns the route's primitive and is called when the route is first
+// navigated to. If a route with the same pattern already exists, it is
+// replaced. If that route is currently shown, its primitive is replaced, too.
+//
+// Routes are matched in the order they were added.
+func (r *Router) AddRoute(pattern string, factory func() Primitive) *Router {
+	//r.Lock()
+	//defer r.Unlock()
+
+	rt := &route{
+		pattern:  pattern,
+		segments: splitPath(pattern),
+		factory:  factory,
+	}
+	for index, existing := range r.routes {
+		if existing.pattern == pattern {
+			r.routes[index] = rt
+			r.replacePage(rt)
+			return r
+		}
+	}
+	r.routes = append(r.routes, rt)
+	return r
+}
+
+// replacePage removes the primitive of a route which was replaced. If the
+// route is currently shown, the primitive is recreated and shown instead.
+func (r *Router) replacePage(rt *route) {
+	shown := r.current != nil && r.current.Route == rt.pattern
+	if shown && r.curr != nil && r.curr.Name == rt.pattern {
+		// Make sure the new primitive is mounted instead of refreshing the old one.
+		r.curr.Item.Unmount()
+		r.curr = nil
+	}
+	r.RemovePage(rt.pattern)
+	if shown {
+		r.AddPage(rt.pattern, rt.factory(), true, false)
+		r.SwitchToPage(rt.pattern, routeContext(r.current))
+	}
+}
+
+// AddGuard adds a function which is called before every navigation with the
+// current route (nil if there is none) and the route about to be shown. If it
+// returns an error, navigation is aborted and the error is returned by the
+// navigating function (e.g. Navigate()). Guards are called in the order they
+// were added.
+func (r *Router) AddGuard(guard func(from, to *RouteMatch) error) *Router {
+	//r.Lock()
+	//defer r.Unlock()
+
+	r.guards = append(r.guards, guard)
+	return r
+}
+
+// SetRouteChangedFunc sets a handler which is called after the router switched
+// to a new path. It receives the previous route (nil if there is none) and the
+// new one.
+func (r *Router) SetRouteChangedFunc(handler func(from, to *RouteMatch)) *Router {
+	//r.Lock()
+	//defer r.Unlock()
+
+	r.routeChanged = handler
+	return r
+}
+
+// Match returns the route matching the given path without navigating to it.
+// It returns nil if no route matches.
+func (r *Router) Match(path string) *RouteMatch {
+	//r.RLock()
+	//defer r.RUnlock()
+
+	segments := splitPath(path)
+	for _, rt := range r.routes {
+		if params, ok := rt.match(segments); ok {
+			return &RouteMatch{
+				Path:   path,
+				Route:  rt.pattern,
+				Params: params,
+			}
+		}
+	}
+	return nil
+}
+
+// Navigate switches to the page of the route matching the given path and adds
+// the path to the history, discarding any paths which could have been reached
+// with Forward(). An error is returned if no route matches or if a guard
+// vetoed the navigation. Navigating to the current path does nothing.
+func (r *Router) Navigate(path string) error {
+	if r.current != nil && samePath(r.current.Path, path) {
+		return nil
+	}
+	if err := r.show(path); err != nil {
+		return err
+	}
+
+	//r.Lock()
+	//defer r.Unlock()
+
+	r.history = append(r.history[:r.historyIndex+1], path)
+	r.historyIndex++
+	return nil
+}
+
+// Back switches to the previous path in the history. An error is returned if
+// there is no previous path or if navigation was vetoed.
+func (r *Router) Back() error {
+	if !r.CanGoBack() {
+		return errors.New("no previous route in history")
+	}
+	if err := r.show(r.history[r.historyIndex-1]); err != nil {
+		return err
+	}
+	r.historyIndex--
+	return nil
+}
+
+// Forward switches to the next path in the history, i.e. the one left with
+// Back(). An error is returned if there is no next path or if navigation was
+// vetoed.
+func (r *Router) Forward() error {
+	if !r.CanGoForward() {
+		return errors.New("no next route in history")
+	}
+	if err := r.show(r.history[r.historyIndex+1]); err != nil {
+		return err
+	}
+	r.historyIndex++
+	return nil
+}
+
+// CanGoBack returns whether or not there is a previous path in the history.
+func (r *Router) CanGoBack() bool {
+	//r.RLock()
+	//defer r.RUnlock()
+
+	return r.historyIndex > 0
+}
+
+// CanGoForward returns whether or not there is a next path in the history.
+func (r *Router) CanGoForward() bool {
+	//r.RLock()
+	//defer r.RUnlock()
+
+	return r.historyIndex < len(r.history)-1
+}
+
+// GetHistory returns the visited paths and the index of the current path in
+// that list (-1 if there is none).
+func (r *Router) GetHistory() (paths []string, current int) {
+	//r.RLock()
+	//defer r.RUnlock()
+
+	return append([]string(nil), r.history...), r.historyIndex
+}
+
+// GetCurrentRoute returns the currently shown route or nil if the router has
+// not navigated anywhere yet.
+func (r *Router) GetCurrentRoute() *RouteMatch {
+	//r.RLock()
+	//defer r.RUnlock()
+
+	return r.current
+}
+
+// show switches to the page of the route matching the given path without
+// touching the history.
+func (r *Router) show(path string) error {
+	to := r.Match(path)
+	if to == nil {
+		return fmt.Errorf("no route matches path %q", path)
+	}
+
+	from := r.current
+	for _, guard := range r.guards {
+		if err := guard(from, to); err != nil {
+			return err
+		}
+	}
+
+	// Create the route's primitive if this is its first visit.
+	if !r.HasPage(to.Route) {
+		for _, rt := range r.routes {
+			if rt.pattern == to.Route {
+				r.AddPage(rt.pattern, rt.factory(), true, false)
+				break
+			}
+		}
+	}
+
+	r.current = to
+	r.SwitchToPage(to.Route, routeContext(to))
+
+	if r.routeChanged != nil {
+		r.routeChanged(from, to)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package tview
+
+import (
+	"errors"
+	"testing"
+)
+
+// routePage is a primitive which records how it was mounted by a router.
+type routePage struct {
+	*Box
+	mounts, refreshes, unmounts int
+	params                      map[string]string
+}
+
+func (p *routePage) Mount(context map[string]interface{}) error {
+	p.mounts++
+	p.params = context["params"].(map[string]string)
+	return nil
+}
+
+func (p *routePage) Refresh(context map[string]interface{}) error {
+	p.refreshes++
+	p.params = context["params"].(map[string]string)
+	return nil
+}
+
+func (p *routePage) Unmount() error {
+	p.unmounts++
+	return nil
+}
+
+func newRoutePage() *routePage {
+	return &routePage{Box: NewBox()}
+}
+
+func TestRouterNavigate(t *testing.T) {
+	home, users := newRoutePage(), newRoutePage()
+	router := NewRouter().
+		AddRoute("/", func() Primitive { return home }).
+		AddRoute("/users/:id", func() Primitive { return users })
+
+	if err := router.Navigate("/"); err != nil {
+		t.Fatal(err)
+	}
+	router.Navigate("/users/42")
+	router.Navigate("/users/7")
+	if users.mounts != 1 || users.refreshes != 1 {
+		t.Errorf("route primitive was mounted %d and refreshed %d times, want 1 and 1", users.mounts, users.refreshes)
+	}
+	if users.params["id"] != "7" {
+		t.Errorf("parameter id is %q, want %q", users.params["id"], "7")
+	}
+	if current := router.GetCurrentRoute(); current.Route != "/users/:id" || current.Path != "/users/7" {
+		t.Errorf("current route is %+v", current)
+	}
+	if err := router.Navigate("/nowhere"); err == nil {
+		t.Error("navigating to an unknown path did not fail")
+	}
+}
+
+func TestRouterHistory(t *testing.T) {
+	router := NewRouter().
+		AddRoute("/", func() Primitive { return newRoutePage() }).
+		AddRoute("/users/:id", func() Primitive { return newRoutePage() })
+	router.Navigate("/")
+	router.Navigate("/users/1")
+	router.Navigate("/users/2")
+
+	if err := router.Back(); err != nil {
+		t.Fatal(err)
+	}
+	router.Back()
+	if router.CanGoBack() || router.GetCurrentRoute().Path != "/" {
+		t.Errorf("Back() did not return to the first path")
+	}
+	if err := router.Back(); err == nil {
+		t.Error("Back() without a previous path did not fail")
+	}
+	router.Forward()
+	router.Navigate("/users/3")
+	paths, current := router.GetHistory()
+	if len(paths) != 3 || paths[2] != "/users/3" || current != 2 || router.CanGoForward() {
+		t.Errorf("history is %v at %d, want [/ /users/1 /users/3] at 2", paths, current)
+	}
+}
+
+func TestRouterNavigateToCurrentPath(t *testing.T) {
+	var changes int
+	router := NewRouter().
+		AddRoute("/", func() Primitive { return newRoutePage() }).
+		AddRoute("/about", func() Primitive { return newRoutePage() }).
+		SetRouteChangedFunc(func(from, to *RouteMatch) {
+			changes++
+		})
+	router.Navigate("/")
+	router.Navigate("/about")
+	router.Navigate("/about/")
+
+	if paths, _ := router.GetHistory(); len(paths) != 2 {
+		t.Errorf("history is %v, want [/ /about]", paths)
+	}
+	if changes != 2 {
+		t.Errorf("route changed %d times, want 2", changes)
+	}
+	router.Back()
+	if path := router.GetCurrentRoute().Path; path != "/" {
+		t.Errorf("Back() switched to %q, want %q", path, "/")
+	}
+}
+
+func TestRouterGuard(t *testing.T) {
+	router := NewRouter().
+		AddRoute("/", func() Primitive { return newRoutePage() }).
+		AddRoute("/admin", func() Primitive { return newRoutePage() }).
+		AddGuard(func(from, to *RouteMatch) error {
+			if to.Route == "/admin" {
+				return errors.New("forbidden")
+			}
+			return nil
+		})
+	router.Navigate("/")
+
+	if err := router.Navigate("/admin"); err == nil || err.Error() != "forbidden" {
+		t.Errorf("guard returned %v, want forbidden", err)
+	}
+	if paths, _ := router.GetHistory(); len(paths) != 1 || router.GetCurrentRoute().Path != "/" {
+		t.Errorf("vetoed navigation changed the history to %v", paths)
+	}
+}
+
+func TestRouterReplaceCurrentRoute(t *testing.T) {
+	old, replacement := newRoutePage(), newRoutePage()
+	router := NewRouter().AddRoute("/users/:id", func() Primitive { return old })
+	router.Navigate("/users/42")
+
+	router.AddRoute("/users/:id", func() Primitive { return replacement })
+	if old.unmounts != 1 {
+		t.Errorf("replaced primitive was unmounted %d times, want 1", old.unmounts)
+	}
+	if replacement.mounts != 1 || replacement.params["id"] != "42" {
+		t.Errorf("new primitive was mounted %d times with %v", replacement.mounts, replacement.params)
+	}
+	if page := router.GetCurrentPage(); page == nil || page.Item != replacement {
+		t.Error("new primitive is not shown")
+	}
+
+	router.Navigate("/users/7")
+	if replacement.refreshes != 1 || old.refreshes != 0 {
+		t.Errorf("navigation refreshed the new primitive %d times and the old one %d times", replacement.refreshes, old.refreshes)
+	}
+}
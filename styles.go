@@ -2,12 +2,12 @@ package tview
 
 import "github.com/gdamore/tcell"
 
-// Styles defines various colors used when primitives are initialized. These
-// may be changed to accommodate a different look and feel.
-//
-// The default is for applications with a black background and basic colors:
-// black, white, yellow, green, and blue.
-var Styles = struct {
+// Theme defines the colors and text attributes of primitives. Primitives are
+// initialized with the package's Styles theme when they are created. Themes
+// can be changed at runtime with Application.SetTheme() (see also Themable).
+type Theme struct {
+	Name string // The theme's name.
+
 	PrimitiveBackgroundColor    tcell.Color // Main background color for primitives.
 	ContrastBackgroundColor     tcell.Color // Background color for contrasting elements.
 	MoreContrastBackgroundColor tcell.Color // Background color for even more contrasting elements.
@@ -18,7 +18,17 @@ var Styles = struct {
 	SecondaryTextColor          tcell.Color // Secondary text (e.g. labels).
 	TertiaryTextColor           tcell.Color // Tertiary text (e.g. subtitles, notes).
 	InverseTextColor            tcell.Color // Text on primary-colored backgrounds.
-}{
+	ErrorTextColor              tcell.Color // Error messages (e.g. form validation errors).
+
+	BorderAttributes tcell.AttrMask // Box borders.
+	TitleAttributes  tcell.AttrMask // Box titles.
+	LabelAttributes  tcell.AttrMask // Labels of form items.
+}
+
+// DefaultTheme is the theme for applications with a black background and basic
+// colors: black, white, yellow, green, and blue.
+var DefaultTheme = Theme{
+	Name:                        "default",
 	PrimitiveBackgroundColor:    tcell.ColorBlack,
 	ContrastBackgroundColor:     tcell.ColorBlue,
 	MoreContrastBackgroundColor: tcell.ColorGreen,
@@ -29,4 +39,12 @@ var Styles = struct {
 	SecondaryTextColor:          tcell.ColorYellow,
 	TertiaryTextColor:           tcell.ColorGreen,
 	InverseTextColor:            tcell.ColorBlue,
+	ErrorTextColor:              tcell.ColorRed,
 }
+
+// Styles defines various colors and attributes used when primitives are
+// initialized. These may be changed to accommodate a different look and feel.
+// Application.SetTheme() replaces them with the application's theme.
+//
+// The default is DefaultTheme.
+var Styles = DefaultTheme
@@ -1,6 +1,8 @@
 package tview
 
 import (
+	"reflect"
+	"regexp"
 	"sort"
 	"sync"
 
@@ -167,6 +169,38 @@ func (c *TableCell) GetLastPosition() (x, y, width int) {
 // If it is set only for rows, entire rows can be selected. If both flags are
 // set, individual cells can be selected. The "selected" handler set via
 // SetSelectedFunc() is invoked when the user presses Enter on a selection.
+// After SetMultiSelect(true), the user may also select multiple rows, e.g. to
+// apply an action to all of them (see GetSelectedRows()).
+//
+// Sorting
+//
+// The rows below the fixed rows can be sorted by a column with SortBy(), using
+// a comparator per column which is set with SetComparator() (see CompareText,
+// CompareNumbers, and CompareTime()). After SetSortable(true), the user may
+// also sort the rows by clicking a header cell (a cell in a fixed row) or by
+// pressing "s". The header cell of the sorted column shows the sort order.
+//
+// Filtering
+//
+// The rows below the fixed rows can be filtered with a pattern which is
+// searched in the rows' cells (see SetFilter() and SetFilterColumns()) and/or
+// with a function (see SetFilterFunc()). After SetFilterable(true), the user
+// may enter a pattern by pressing "/". Row indices always refer to the rows of
+// the unfiltered table.
+//
+// Editing
+//
+// After SetEditable(true), the user may change the text of the selected cell
+// by pressing Enter, which opens an InputField (or a DropDown, see
+// SetEditOptions()) on top of the cell. See SetCellValidator() and
+// SetCellChangedFunc() to check and to receive the new texts.
+//
+// Virtual Content
+//
+// By default, a table keeps all of its cells in memory. For large data sets,
+// e.g. millions of rows from a database, implement the TableContent interface
+// and pass it to SetContent(). The table then only requests the cells it
+// actually shows (see TableContent for details).
 //
 // Navigation
 //
@@ -201,14 +235,15 @@ type Table struct {
 	// The color of the borders or the separator.
 	bordersColor tcell.Color
 
+	// The text color of cells created with NewTableCell() according to the
+	// theme last applied to the table (see ApplyTheme()).
+	cellTextColor tcell.Color
+
 	// If there are no borders, the column separator.
 	separator rune
 
-	// The cells of the table. Rows first, then columns.
-	cells [][]*TableCell
-
-	// The rightmost column in the data set.
-	lastColumn int
+	// The content of the table, i.e. its cells.
+	content TableContent
 
 	// The number of fixed rows / columns.
 	fixedRows, fixedColumns int
@@ -230,6 +265,78 @@ type Table struct {
 	// The number of visible rows the last time the table was drawn.
 	visibleRows int
 
+	// Whether or not the user may sort the rows.
+	sortable bool
+
+	// The comparators used to sort the rows, by column.
+	comparators map[int]TableComparator
+
+	// The column the rows were last sorted by (-1 for none) and whether they
+	// were sorted in descending order.
+	sortColumn     int
+	sortDescending bool
+
+	// Whether or not the user may filter the rows.
+	filterable bool
+
+	// An optional function which decides which rows are shown.
+	filter func(row int, cells []*TableCell) bool
+
+	// The filter pattern, its compiled form (nil if there is no pattern or it
+	// is invalid), and whether it is a regular expression.
+	filterPattern    string
+	filterExpression *regexp.Regexp
+	filterRegexp     bool
+
+	// The columns searched for the filter pattern. All columns if empty.
+	filterColumns []int
+
+	// Whether or not the user is currently entering a filter pattern.
+	filterPrompt bool
+
+	// The color of the filter bar.
+	filterColor tcell.Color
+
+	// The rows which passed the filter (nil if the table is not filtered) and
+	// whether they need to be determined again.
+	filteredContent *tableFilteredContent
+	filterDirty     bool
+
+	// Whether or not the user may select multiple rows.
+	multiSelect bool
+
+	// The selected rows, in addition to the current selection.
+	rowSelection multiSelection
+
+	// The background color of selected rows.
+	selectedRowsColor tcell.Color
+
+	// Whether or not the user may edit cells.
+	editable bool
+
+	// The options to choose from when editing a cell, by column.
+	editOptions map[int][]string
+
+	// The editor of the cell being edited (nil if no cell is being edited),
+	// the position of the cell, the error returned by the validator for the
+	// editor's text, and the function which returns the focus to the table.
+	editor              tableCellEditor
+	editRow, editColumn int
+	editError           string
+	editSetFocus        func(p Primitive)
+
+	// The theme last applied to the table, which is also applied to editors.
+	editTheme *Theme
+
+	// The color of errors returned by the validator.
+	editErrorColor tcell.Color
+
+	// The indices of the visible rows and columns, the widths of the visible
+	// columns, and the screen x-coordinate of the table, as of the last time
+	// the table was drawn.
+	visibleRowIndices, visibleColumnIndices, visibleColumnWidths []int
+	tableX                                                       int
+
 	// An optional function which gets called when the user presses Enter on a
 	// selected cell. If entire rows selected, the column value is undefined.
 	// Likewise for entire columns.
@@ -240,6 +347,17 @@ type Table struct {
 	// Likewise for entire columns.
 	selectionChanged func(row, column int)
 
+	// An optional function which gets called when the user changes which rows
+	// are selected.
+	selectedRowsChanged func(rows []int)
+
+	// An optional function which checks the text of an edited cell.
+	cellValidator func(row, column int, text string) error
+
+	// An optional function which gets called when the user changed the text of
+	// a cell.
+	cellChanged func(row, column int, text string)
+
 	// An optional function which gets called when the user presses Escape, Tab,
 	// or Backtab. Also when the user presses Enter if nothing is selectable.
 	done func(key tcell.Key)
@@ -247,21 +365,53 @@ type Table struct {
 
 // NewTable returns a new table.
 func NewTable() *Table {
-	return &Table{
-		Box:          NewBox(),
-		bordersColor: Styles.GraphicsColor,
-		separator:    ' ',
-		lastColumn:   -1,
+	t := &Table{
+		Box:           NewBox(),
+		bordersColor:  Styles.GraphicsColor,
+		separator:     ' ',
+		cellTextColor: Styles.PrimaryTextColor,
+		content:       &tableDefaultContent{lastColumn: -1},
+		sortColumn:    -1,
+		filterColor:   Styles.PrimaryTextColor,
+
+		selectedRowsColor: Styles.ContrastBackgroundColor,
+		editErrorColor:    Styles.ErrorTextColor,
 	}
+	t.focus = t
+	t.DeclareProps(PropSpec{
+		Name:    "cells",
+		Type:    reflect.TypeOf([][]*TableCell(nil)),
+		Default: [][]*TableCell(nil),
+		Get: func() interface{} {
+			t.RLock()
+			defer t.RUnlock()
+
+			// Return a copy so the table's rows cannot be modified behind
+			// its back. The cells themselves are shared.
+			content, ok := t.content.(*tableDefaultContent)
+			if !ok || content.cells == nil {
+				return [][]*TableCell(nil)
+			}
+			cells := make([][]*TableCell, len(content.cells))
+			for row, rowCells := range content.cells {
+				cells[row] = append([]*TableCell(nil), rowCells...)
+			}
+			return cells
+		},
+		Set: func(value interface{}) { t.SetCells(value.([][]*TableCell)) },
+	})
+	return t
 }
 
 // Clear removes all table data.
 func (t *Table) Clear() *Table {
+	defer t.cellsChanged()
 	// t.Lock()
 	// defer t.Unlock()
 
-	t.cells = nil
-	t.lastColumn = -1
+	t.content.Clear()
+	t.filterDirty = true
+	t.rowSelection.set(nil)
 	return t
 }
 
@@ -294,6 +444,36 @@ func (t *Table) SetBordersColor(color tcell.Color) *Table {
 	return t
 }
 
+// ApplyTheme sets the table's colors and attributes to those of the given
+// theme. Cells whose text color is still the primary text color of the
+// previous theme (e.g. cells created with NewTableCell()) receive the new
+// theme's primary text color. Other cell colors are not changed, nor are the
+// cells of content set with SetContent(), which should use the current Styles
+// when creating cells.
+func (t *Table) ApplyTheme(theme *Theme) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.Box.ApplyTheme(theme)
+	t.bordersColor = theme.GraphicsColor
+
+	// Cells which still have the previous theme's text color get the new one.
+	if content, ok := t.content.(*tableDefaultContent); ok {
+		for _, row := range content.cells {
+			for _, cell := range row {
+				if cell != nil && cell.Color == t.cellTextColor {
+					cell.SetTextColor(theme.PrimaryTextColor)
+				}
+			}
+		}
+	}
+	t.cellTextColor = theme.PrimaryTextColor
+	t.filterColor = theme.PrimaryTextColor
+	t.selectedRowsColor = theme.ContrastBackgroundColor
+	t.editErrorColor = theme.ErrorTextColor
+	t.editTheme = theme
+}
+
 // SetSeparator sets the character used to fill the space between two
 // neighboring cells. This is a space character ' ' per default but you may
 // want to set it to GraphicsVertBar (or any other rune) if the column
@@ -317,6 +497,7 @@ func (t *Table) SetFixed(rows, columns int) *Table {
 	defer t.Unlock()
 
 	t.fixedRows, t.fixedColumns = rows, columns
+	t.filterDirty = true
 	return t
 }
 
@@ -351,7 +532,7 @@ func (t *Table) GetSelection() (row, column int) {
 	t.RLock()
 	defer t.RUnlock()
 
-	return t.selectedRow, t.selectedColumn
+	return t.sourceRow(t.selectedRow), t.selectedColumn
 }
 
 // Select sets the selected cell. Depending on the selection settings
@@ -361,7 +542,8 @@ func (t *Table) Select(row, column int) *Table {
 	// t.Lock()
 	// defer t.Unlock()
 
-	t.selectedRow, t.selectedColumn = row, column
+	t.updateFilter()
+	t.selectedRow, t.selectedColumn = t.displayRow(row), column
 	return t
 }
 
@@ -414,14 +596,68 @@ func (t *Table) SetDoneFunc(handler func(key tcell.Key)) *Table {
 	return t
 }
 
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the table with Tab or Backtab.
+func (t *Table) hasDoneFunc() bool {
+	return t.done != nil
+}
+
+// SetCells replaces all cells of the table, rows first, then columns. Rows
+// may have different lengths and cells may be nil. A content set with
+// SetContent() is replaced with these cells.
 func (t *Table) SetCells(cells [][]*TableCell) *Table {
+	defer t.cellsChanged()
+	t.Lock()
+	defer t.Unlock()
+
+	content, ok := t.content.(*tableDefaultContent)
+	if !ok {
+		content = &tableDefaultContent{}
+		t.content = content
+	}
+	content.SetCells(cells)
+	t.filterDirty = true
+	t.rowSelection.set(nil)
+	return t
+}
+
+// cellsChanged notifies the subscribers of the "cells" prop that the table's
+// cells changed. The table must not be locked.
+func (t *Table) cellsChanged() {
+	if len(t.propSubscriptions) == 0 {
+		return // Don't copy the cells for nothing.
+	}
+	cells, _ := t.GetProp("cells")
+	t.notifyProp("cells", cells)
+}
+
+// SetContent sets the content of the table, replacing its cells. The table
+// then requests its cells from the given content (see TableContent) instead of
+// keeping them itself. SetCell(), RemoveRow(), and the other functions which
+// modify the table's cells are passed on to the content. A nil content
+// restores an empty table which keeps its own cells.
+func (t *Table) SetContent(content TableContent) *Table {
+	defer t.cellsChanged()
 	t.Lock()
 	defer t.Unlock()
 
-	t.cells = cells
+	if content == nil {
+		content = &tableDefaultContent{lastColumn: -1}
+	}
+	t.content = content
+	t.filterDirty = true
+	t.rowSelection.set(nil)
 	return t
 }
 
+// GetContent returns the content of the table (see SetContent()).
+func (t *Table) GetContent() TableContent {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.content
+}
+
 // SetCell sets the content of a cell the specified position. It is ok to
 // directly instantiate a TableCell object. If the cell has contain, at least
 // the Text and Color fields should be set.
@@ -432,23 +668,12 @@ func (t *Table) SetCells(cells [][]*TableCell) *Table {
 //
 // To avoid unnecessary garbage collection, fill columns from left to right.
 func (t *Table) SetCell(row, column int, cell *TableCell) *Table {
+	defer t.cellsChanged()
 	t.Lock()
 	defer t.Unlock()
 
-	if row >= len(t.cells) {
-		t.cells = append(t.cells, make([][]*TableCell, row-len(t.cells)+1)...)
-	}
-	rowLen := len(t.cells[row])
-	if column >= rowLen {
-		t.cells[row] = append(t.cells[row], make([]*TableCell, column-rowLen+1)...)
-		for c := rowLen; c < column; c++ {
-			t.cells[row][c] = &TableCell{}
-		}
-	}
-	t.cells[row][column] = cell
-	if column > t.lastColumn {
-		t.lastColumn = column
-	}
+	t.content.SetCell(row, column, cell)
+	t.filterDirty = true
 	return t
 }
 
@@ -465,10 +690,81 @@ func (t *Table) GetCell(row, column int) *TableCell {
 	t.RLock()
 	defer t.RUnlock()
 
-	if row >= len(t.cells) || column >= len(t.cells[row]) {
+	cell := t.content.GetCell(row, column)
+	if cell == nil {
 		return &TableCell{}
 	}
-	return t.cells[row][column]
+	return cell
+}
+
+// RemoveRow removes the row at the given position from the table. If there is
+// no such row, this has no effect.
+func (t *Table) RemoveRow(row int) *Table {
+	defer t.cellsChanged()
+	t.Lock()
+	defer t.Unlock()
+
+	if row >= 0 && row < t.content.GetRowCount() {
+		t.rowSelection.remap(func(index int) int {
+			if index == row {
+				return -1
+			} else if index > row {
+				return index - 1
+			}
+			return index
+		})
+	}
+	t.content.RemoveRow(row)
+	t.filterDirty = true
+	return t
+}
+
+// RemoveColumn removes the column at the given position from the table. If
+// there is no such column, this has no effect.
+func (t *Table) RemoveColumn(column int) *Table {
+	defer t.cellsChanged()
+	t.Lock()
+	defer t.Unlock()
+
+	t.content.RemoveColumn(column)
+	t.filterDirty = true
+	return t
+}
+
+// InsertRow inserts a row before the row with the given index. Cells on the
+// given row and below will be shifted to the bottom by one row. If "row" is
+// equal or larger than the current number of rows, this function has no
+// effect.
+func (t *Table) InsertRow(row int) *Table {
+	defer t.cellsChanged()
+	t.Lock()
+	defer t.Unlock()
+
+	if row >= 0 && row < t.content.GetRowCount() {
+		t.rowSelection.remap(func(index int) int {
+			if index >= row {
+				return index + 1
+			}
+			return index
+		})
+	}
+	t.content.InsertRow(row)
+	t.filterDirty = true
+	return t
+}
+
+// InsertColumn inserts a column before the column with the given index. Cells
+// in the given column and to its right will be shifted to the right by one
+// column. Rows that have fewer initialized cells than "column" will remain
+// unchanged.
+func (t *Table) InsertColumn(column int) *Table {
+	defer t.cellsChanged()
+	t.Lock()
+	defer t.Unlock()
+
+	t.content.InsertColumn(column)
+	t.filterDirty = true
+	return t
 }
 
 // GetRowCount returns the number of rows in the table.
@@ -476,7 +772,7 @@ func (t *Table) GetRowCount() int {
 	t.RLock()
 	defer t.RUnlock()
 
-	return len(t.cells)
+	return t.content.GetRowCount()
 }
 
 // GetColumnCount returns the (maximum) number of columns in the table.
@@ -484,10 +780,7 @@ func (t *Table) GetColumnCount() int {
 	t.RLock()
 	defer t.RUnlock()
 
-	if len(t.cells) == 0 {
-		return 0
-	}
-	return t.lastColumn + 1
+	return t.content.GetColumnCount()
 }
 
 // ScrollToBeginning scrolls the table to the beginning to that the top left
@@ -510,17 +803,44 @@ func (t *Table) ScrollToEnd() *Table {
 
 	t.trackEnd = true
 	t.columnOffset = 0
-	t.rowOffset = len(t.cells)
+	t.rowOffset = t.visibleContent().GetRowCount()
 	return t
 }
 
 // Draw draws this primitive onto the screen.
 func (t *Table) Draw(screen tcell.Screen) {
+	// Show the number of matching rows in the title while the table is
+	// filtered.
+	content := t.visibleContent()
+	title := t.title
+	t.title = t.filterTitle(title)
 	t.Box.Draw(screen)
+	t.title = title
+
+	// The editor of the cell being edited is drawn last, on top of the table.
+	// The selection may have moved away from that cell in the meantime (e.g.
+	// by scrolling with the mouse wheel).
+	editX, editY, editWidth := 0, 0, -1
+	defer func() {
+		if t.editor == nil {
+			return
+		}
+		if editWidth >= 0 {
+			t.drawEditor(screen, editX, editY, editWidth)
+		} else {
+			t.editor.SetRect(0, 0, 0, 0) // The cell is not visible.
+		}
+	}()
 
 	// What's our available screen space?
 	x, y, width, height := t.GetInnerRect()
 
+	// The filter bar takes up the last line.
+	if (t.filterPrompt || t.filterPattern != "") && height > 1 {
+		height--
+		t.drawFilterBar(screen, x, y+height, width)
+	}
+
 	// Lets lock things down because we set some values
 	//t.Lock()
 	//defer t.Unlock()
@@ -532,11 +852,12 @@ func (t *Table) Draw(screen tcell.Screen) {
 	}
 
 	// Return the cell at the specified position (nil if it doesn't exist).
+	rowCount, lastColumn := content.GetRowCount(), content.GetColumnCount()-1
 	getCell := func(row, column int) *TableCell {
-		if row < 0 || column < 0 || row >= len(t.cells) || column >= len(t.cells[row]) {
+		if row < 0 || column < 0 || row >= rowCount || column > lastColumn {
 			return nil
 		}
-		return t.cells[row][column]
+		return content.GetCell(row, column)
 	}
 
 	// If this cell is selectable, find the next one.
@@ -547,13 +868,13 @@ func (t *Table) Draw(screen tcell.Screen) {
 		if t.selectedRow < 0 {
 			t.selectedRow = 0
 		}
-		for t.selectedRow < len(t.cells) {
+		for t.selectedRow < rowCount {
 			cell := getCell(t.selectedRow, t.selectedColumn)
 			if cell == nil || !cell.NotSelectable {
 				break
 			}
 			t.selectedColumn++
-			if t.selectedColumn > t.lastColumn {
+			if t.selectedColumn > lastColumn {
 				t.selectedColumn = 0
 				t.selectedRow++
 			}
@@ -579,13 +900,13 @@ func (t *Table) Draw(screen tcell.Screen) {
 		}
 	}
 	if t.borders {
-		if 2*(len(t.cells)-t.rowOffset) < height {
+		if 2*(rowCount-t.rowOffset) < height {
 			t.trackEnd = true
 		} else {
 			t.trackEnd = false
 		}
 	} else {
-		if len(t.cells)-t.rowOffset < height {
+		if rowCount-t.rowOffset < height {
 			t.trackEnd = true
 		} else {
 			t.trackEnd = false
@@ -593,9 +914,9 @@ func (t *Table) Draw(screen tcell.Screen) {
 	}
 	if t.trackEnd {
 		if t.borders {
-			t.rowOffset = len(t.cells) - height/2
+			t.rowOffset = rowCount - height/2
 		} else {
-			t.rowOffset = len(t.cells) - height
+			t.rowOffset = rowCount - height
 		}
 	}
 	if t.rowOffset < 0 {
@@ -633,12 +954,12 @@ func (t *Table) Draw(screen tcell.Screen) {
 		tableHeight += rowStep
 		return true
 	}
-	for row := 0; row < t.fixedRows && row < len(t.cells); row++ { // Do the fixed rows first.
+	for row := 0; row < t.fixedRows && row < rowCount; row++ { // Do the fixed rows first.
 		if !indexRow(row) {
 			break
 		}
 	}
-	for row := t.fixedRows + t.rowOffset; row < len(t.cells); row++ { // Then the remaining rows.
+	for row := t.fixedRows + t.rowOffset; row < rowCount; row++ { // Then the remaining rows.
 		if !indexRow(row) {
 			break
 		}
@@ -678,7 +999,7 @@ ColumnLoop:
 		maxWidth := -1
 		for _, row := range rows {
 			if cell := getCell(row, column); cell != nil {
-				cellWidth := StringWidth(cell.Text)
+				cellWidth := StringWidth(t.cellText(row, column, cell))
 				if cell.MaxWidth > 0 && cell.MaxWidth < cellWidth {
 					cellWidth = cell.MaxWidth
 				}
@@ -709,6 +1030,10 @@ ColumnLoop:
 		}
 	}
 
+	// Remember the layout for mouse events.
+	t.visibleRowIndices, t.visibleColumnIndices, t.visibleColumnWidths = rows, columns, widths
+	t.tableX = x
+
 	// Helper function which draws border runes.
 	borderStyle := tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.bordersColor)
 	drawBorder := func(colX, rowY int, ch rune) {
@@ -751,19 +1076,23 @@ ColumnLoop:
 			}
 
 			// Get the cell.
+			finalWidth := columnWidth
+			if columnX+1+columnWidth >= width {
+				finalWidth = width - columnX - 1
+			}
+			if t.editor != nil && column == t.editColumn && t.sourceRow(row) == t.editRow {
+				editX, editY, editWidth = x+columnX+1, y+rowY, finalWidth
+			}
 			cell := getCell(row, column)
 			if cell == nil {
 				continue
 			}
 
 			// Draw text.
-			finalWidth := columnWidth
-			if columnX+1+columnWidth >= width {
-				finalWidth = width - columnX - 1
-			}
 			cell.x, cell.y, cell.width = x+columnX+1, y+rowY, finalWidth
-			_, printed := Print(screen, cell.Text, x+columnX+1, y+rowY, finalWidth, cell.Align, cell.Color)
-			if StringWidth(cell.Text)-printed > 0 && printed > 0 {
+			text := t.cellText(row, column, cell)
+			_, printed := Print(screen, text, x+columnX+1, y+rowY, finalWidth, cell.Align, cell.Color)
+			if StringWidth(text)-printed > 0 && printed > 0 {
 				_, _, style, _ := screen.GetContent(x+columnX+1+finalWidth-1, y+rowY)
 				fg, _, _ := style.Decompose()
 				Print(screen, string(GraphicsEllipsis), x+columnX+1+finalWidth-1, y+rowY, 1, AlignLeft, fg)
@@ -786,7 +1115,7 @@ ColumnLoop:
 	}
 
 	// Draw right border.
-	if t.borders && len(t.cells) > 0 && columnX < width {
+	if t.borders && rowCount > 0 && columnX < width {
 		for rowY := range rows {
 			rowY *= 2
 			if rowY+1 < height {
@@ -834,11 +1163,13 @@ ColumnLoop:
 		x, y, w, h int
 		text       tcell.Color
 		selected   bool
+		marked     bool
 	})
 	var backgroundColors []tcell.Color
 	for rowY, row := range rows {
 		columnX := 0
 		rowSelected := t.rowsSelectable && !t.columnsSelectable && row == t.selectedRow
+		rowMarked := t.rowSelection.has(t.sourceRow(row))
 		for columnIndex, column := range columns {
 			columnWidth := widths[columnIndex]
 			cell := getCell(row, column)
@@ -858,6 +1189,7 @@ ColumnLoop:
 				x, y, w, h int
 				text       tcell.Color
 				selected   bool
+				marked     bool
 			}{
 				x:        bx,
 				y:        by,
@@ -865,6 +1197,7 @@ ColumnLoop:
 				h:        bh,
 				text:     cell.Color,
 				selected: cellSelected,
+				marked:   rowMarked,
 			})
 			if !ok {
 				backgroundColors = append(backgroundColors, cell.BackgroundColor)
@@ -887,6 +1220,8 @@ ColumnLoop:
 		for _, cell := range entries {
 			if cell.selected {
 				defer colorBackground(cell.x, cell.y, cell.w, cell.h, bgColor, cell.text, true)
+			} else if cell.marked {
+				colorBackground(cell.x, cell.y, cell.w, cell.h, t.selectedRowsColor, cell.text, false)
 			} else {
 				colorBackground(cell.x, cell.y, cell.w, cell.h, bgColor, cell.text, false)
 			}
@@ -903,6 +1238,20 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 		case *tcell.EventKey:
 			key := evt.Key()
 
+			// Enter the filter pattern.
+			if t.filterPrompt {
+				t.filterInput(evt)
+				return
+			}
+			if t.filterable && key == tcell.KeyRune && evt.Rune() == '/' {
+				t.filterPrompt = true
+				return
+			}
+			if t.filterable && key == tcell.KeyEscape && t.filterPattern != "" {
+				t.setFilterPattern("")
+				return
+			}
+
 			if (!t.rowsSelectable && !t.columnsSelectable && key == tcell.KeyEnter) ||
 				key == tcell.KeyEscape ||
 				key == tcell.KeyTab ||
@@ -915,12 +1264,28 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 
 			// Movement functions.
 			previouslySelectedRow, previouslySelectedColumn := t.selectedRow, t.selectedColumn
+
+			// Movement keys with Shift select a range of rows.
+			multiSelect := t.multiSelect && t.rowsSelectable
+			var previouslySelectedRows []int
+			if multiSelect && t.selectedRowsChanged != nil {
+				previouslySelectedRows = t.rowSelection.list()
+			}
+			selectRange := multiSelect && evt.Modifiers()&tcell.ModShift != 0 &&
+				(key == tcell.KeyUp || key == tcell.KeyDown || key == tcell.KeyPgUp || key == tcell.KeyPgDn || key == tcell.KeyHome || key == tcell.KeyEnd)
+			if !selectRange {
+				t.rowSelection.endRange()
+			}
 			var (
+				content    = t.visibleContent()
+				rowCount   = content.GetRowCount()
+				lastColumn = content.GetColumnCount() - 1
+
 				getCell = func(row, column int) *TableCell {
-					if row < 0 || column < 0 || row >= len(t.cells) || column >= len(t.cells[row]) {
+					if row < 0 || column < 0 || row >= rowCount || column > lastColumn {
 						return nil
 					}
-					return t.cells[row][column]
+					return content.GetCell(row, column)
 				}
 
 				previous = func() {
@@ -931,33 +1296,33 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 						}
 						t.selectedColumn--
 						if t.selectedColumn < 0 {
-							t.selectedColumn = t.lastColumn
+							t.selectedColumn = lastColumn
 							t.selectedRow--
 						}
 					}
 				}
 
 				next = func() {
-					if t.selectedColumn > t.lastColumn {
+					if t.selectedColumn > lastColumn {
 						t.selectedColumn = 0
 						t.selectedRow++
-						if t.selectedRow >= len(t.cells) {
-							t.selectedRow = len(t.cells) - 1
+						if t.selectedRow >= rowCount {
+							t.selectedRow = rowCount - 1
 						}
 					}
-					for t.selectedRow < len(t.cells) {
+					for t.selectedRow < rowCount {
 						cell := getCell(t.selectedRow, t.selectedColumn)
 						if cell == nil || !cell.NotSelectable {
 							return
 						}
 						t.selectedColumn++
-						if t.selectedColumn > t.lastColumn {
+						if t.selectedColumn > lastColumn {
 							t.selectedColumn = 0
 							t.selectedRow++
 						}
 					}
-					t.selectedColumn = t.lastColumn
-					t.selectedRow = len(t.cells) - 1
+					t.selectedColumn = lastColumn
+					t.selectedRow = rowCount - 1
 					previous()
 				}
 
@@ -975,8 +1340,8 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 
 				end = func() {
 					if t.rowsSelectable {
-						t.selectedRow = len(t.cells) - 1
-						t.selectedColumn = t.lastColumn
+						t.selectedRow = rowCount - 1
+						t.selectedColumn = lastColumn
 						previous()
 					} else {
 						t.trackEnd = true
@@ -987,8 +1352,8 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 				down = func() {
 					if t.rowsSelectable {
 						t.selectedRow++
-						if t.selectedRow >= len(t.cells) {
-							t.selectedRow = len(t.cells) - 1
+						if t.selectedRow >= rowCount {
+							t.selectedRow = rowCount - 1
 						}
 						if t.columnsSelectable {
 							next()
@@ -1028,8 +1393,8 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 				right = func() {
 					if t.columnsSelectable {
 						t.selectedColumn++
-						if t.selectedColumn > t.lastColumn {
-							t.selectedColumn = t.lastColumn
+						if t.selectedColumn > lastColumn {
+							t.selectedColumn = lastColumn
 						}
 						next()
 					} else {
@@ -1040,8 +1405,8 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 				pageDown = func() {
 					if t.rowsSelectable {
 						t.selectedRow += 10
-						if t.selectedRow >= len(t.cells) {
-							t.selectedRow = len(t.cells) - 1
+						if t.selectedRow >= rowCount {
+							t.selectedRow = rowCount - 1
 						}
 						if t.columnsSelectable {
 							next()
@@ -1083,6 +1448,29 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 					left()
 				case 'l':
 					right()
+				case 's':
+					if !t.sortable {
+						break
+					}
+					if t.columnsSelectable {
+						t.toggleSort(t.selectedColumn)
+					} else if t.sortColumn >= 0 {
+						t.toggleSort(t.sortColumn)
+					} else {
+						t.toggleSort(0)
+					}
+				case ' ':
+					if multiSelect {
+						t.toggleRow(t.selectedRow)
+					}
+				case '*':
+					if multiSelect {
+						t.selectAllRows(true)
+					}
+				}
+			case tcell.KeyCtrlA:
+				if multiSelect {
+					t.selectAllRows(false)
 				}
 			case tcell.KeyHome:
 				home()
@@ -1101,17 +1489,213 @@ func (t *Table) InputHandler() func(tcell.Event, func(Primitive)) {
 			case tcell.KeyPgUp, tcell.KeyCtrlB:
 				pageUp()
 			case tcell.KeyEnter:
+				if t.startEditing(setFocus) {
+					break
+				}
 				if (t.rowsSelectable || t.columnsSelectable) && t.selected != nil {
-					go t.selected(t.selectedRow, t.selectedColumn)
+					go t.selected(t.sourceRow(t.selectedRow), t.selectedColumn)
 				}
 			}
+			if selectRange {
+				t.selectRowRange(previouslySelectedRow, t.selectedRow)
+			}
 
 			// If the selection has changed, notify the handler.
 			if t.selectionChanged != nil &&
 				((t.rowsSelectable && previouslySelectedRow != t.selectedRow) ||
 					(t.columnsSelectable && previouslySelectedColumn != t.selectedColumn)) {
-				go t.selectionChanged(t.selectedRow, t.selectedColumn)
+				go t.selectionChanged(t.sourceRow(t.selectedRow), t.selectedColumn)
+			}
+			if multiSelect && t.selectedRowsChanged != nil && !t.rowSelection.equal(previouslySelectedRows) {
+				go t.selectedRowsChanged(t.rowSelection.list())
+			}
+		}
+	})
+}
+
+// cellAt returns the row and column located at the given screen coordinates.
+// Each returned value may be negative if there is no row and/or cell. This
+// function will also process coordinates outside the table's inner rectangle
+// so callers will need to check for bounds themselves.
+func (t *Table) cellAt(x, y int) (row, column int) {
+	_, rectY, _, _ := t.GetInnerRect()
+
+	// Determine row as seen on screen.
+	if t.borders {
+		row = (y - rectY - 1) / 2
+	} else {
+		row = y - rectY
+	}
+
+	// Respect fixed rows and row offset.
+	if row >= 0 && row < len(t.visibleRowIndices) {
+		row = t.visibleRowIndices[row]
+	} else {
+		row = -1
+	}
+
+	// Search for the clicked column.
+	column = -1
+	columnX := t.tableX
+	if !t.borders {
+		columnX--
+	}
+	if x >= columnX {
+		for index, width := range t.visibleColumnWidths {
+			columnX += width + 1
+			if x < columnX {
+				column = t.visibleColumnIndices[index]
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// While a cell is being edited, the editor receives the mouse events on
+		// it. Clicking elsewhere confirms the editor's text.
+		if t.editor != nil {
+			if dropDown, ok := t.editor.(*DropDown); ok && dropDown.open || t.editor.InRect(x, y) {
+				return t.editor.MouseHandler()(action, event, setFocus)
+			}
+			if action == MouseLeftDown && t.InRect(x, y) {
+				t.finishEditing(true)
+				if t.editor != nil {
+					return true, nil
+				}
+			}
+		}
+
+		if !t.InRect(x, y) && action != MouseLeftDrag && action != MouseLeftUp {
+			return false, nil
+		}
+
+		// selectAt moves the selection to the cell at the mouse position.
+		content := t.visibleContent()
+		previouslySelectedRow, previouslySelectedColumn := t.selectedRow, t.selectedColumn
+		multiSelect := t.multiSelect && t.rowsSelectable
+		var previouslySelectedRows []int
+		if multiSelect && t.selectedRowsChanged != nil {
+			previouslySelectedRows = t.rowSelection.list()
+		}
+		selectAt := func() {
+			if !t.rowsSelectable && !t.columnsSelectable {
+				return
+			}
+			row, column := t.cellAt(x, y)
+			if row < 0 || column < 0 || row >= content.GetRowCount() {
+				return
+			}
+			if cell := content.GetCell(row, column); cell != nil && cell.NotSelectable {
+				return
+			}
+			if t.rowsSelectable {
+				t.selectedRow = row
+			}
+			if t.columnsSelectable {
+				t.selectedColumn = column
 			}
 		}
+
+		// sortAt sorts the rows by the column of the header cell at the mouse
+		// position. It returns false if there is no such header cell.
+		sortAt := func() bool {
+			row, column := t.cellAt(x, y)
+			if !t.sortable || row < 0 || row >= t.fixedRows || column < 0 {
+				return false
+			}
+			t.toggleSort(column)
+			return true
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(t)
+			capture = t
+			consumed = true
+		case MouseLeftDrag:
+			if t.InRect(x, y) {
+				selectAt()
+			}
+			capture = t
+			consumed = true
+		case MouseLeftUp:
+			consumed = true
+		case MouseLeftClick:
+			if sortAt() {
+				consumed = true
+				break
+			}
+			switch {
+			case multiSelect && event.Modifiers()&tcell.ModCtrl != 0:
+				// Select or unselect the clicked row.
+				row, _ := t.cellAt(x, y)
+				t.toggleRow(row)
+			case multiSelect && event.Modifiers()&tcell.ModShift != 0:
+				// Select the rows up to the clicked row.
+				selectAt()
+				t.selectRowRange(previouslySelectedRow, t.selectedRow)
+				t.rowSelection.endRange()
+			default:
+				selectAt()
+			}
+			consumed = true
+		case MouseLeftDoubleClick:
+			if !sortAt() {
+				selectAt()
+				if !t.startEditing(setFocus) && (t.rowsSelectable || t.columnsSelectable) && t.selected != nil {
+					go t.selected(t.sourceRow(t.selectedRow), t.selectedColumn)
+				}
+			}
+			consumed = true
+		case MouseScrollUp:
+			if t.rowsSelectable {
+				if t.selectedRow > 0 {
+					t.selectedRow--
+				}
+			} else {
+				t.trackEnd = false
+				t.rowOffset--
+			}
+			consumed = true
+		case MouseScrollDown:
+			if t.rowsSelectable {
+				if t.selectedRow < content.GetRowCount()-1 {
+					t.selectedRow++
+				}
+			} else {
+				t.rowOffset++
+			}
+			consumed = true
+		case MouseScrollLeft:
+			if !t.columnsSelectable {
+				t.columnOffset--
+			}
+			consumed = true
+		case MouseScrollRight:
+			if !t.columnsSelectable {
+				t.columnOffset++
+			}
+			consumed = true
+		}
+
+		// If the selection has changed, notify the handler.
+		if t.selectionChanged != nil &&
+			((t.rowsSelectable && previouslySelectedRow != t.selectedRow) ||
+				(t.columnsSelectable && previouslySelectedColumn != t.selectedColumn)) {
+			go t.selectionChanged(t.sourceRow(t.selectedRow), t.selectedColumn)
+		}
+		if multiSelect && t.selectedRowsChanged != nil && !t.rowSelection.equal(previouslySelectedRows) {
+			go t.selectedRowsChanged(t.rowSelection.list())
+		}
+
+		return
 	})
 }
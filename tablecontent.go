@@ -0,0 +1,194 @@
+package tview
+
+// TableContent provides the cells of a Table. By default, a table keeps its
+// cells in memory. A TableContent set with Table.SetContent() may instead
+// provide them from any other source, e.g. the result of a database query.
+// The table only requests the cells it shows on screen (and those it walks
+// while moving the selection) so the content may create them on the fly,
+// without keeping millions of TableCell structs in memory.
+//
+// Implementations which do not allow the table's cells to be modified may
+// embed TableContentReadOnly.
+type TableContent interface {
+	// GetCell returns the cell at the given position or nil if there is no
+	// cell there. The table may call this function often, e.g. for every
+	// visible cell each time it is drawn.
+	GetCell(row, column int) *TableCell
+
+	// GetRowCount returns the total number of rows.
+	GetRowCount() int
+
+	// GetColumnCount returns the total number of columns.
+	GetColumnCount() int
+
+	// SetCell sets the cell at the given position. Positions beyond the
+	// content's rows and columns may extend it.
+	SetCell(row, column int, cell *TableCell)
+
+	// RemoveRow removes the row at the given position, moving the rows below
+	// it up.
+	RemoveRow(row int)
+
+	// RemoveColumn removes the column at the given position, moving the
+	// columns to its right to the left.
+	RemoveColumn(column int)
+
+	// InsertRow inserts an empty row before the row at the given position.
+	InsertRow(row int)
+
+	// InsertColumn inserts an empty column before the column at the given
+	// position.
+	InsertColumn(column int)
+
+	// Clear removes all cells.
+	Clear()
+}
+
+// TableContentReadOnly implements the functions of TableContent which modify
+// the content as no-ops. Embed it in read-only implementations of
+// TableContent so only GetCell(), GetRowCount(), and GetColumnCount() need to
+// be implemented.
+type TableContentReadOnly struct{}
+
+// SetCell does nothing.
+func (t TableContentReadOnly) SetCell(row, column int, cell *TableCell) {}
+
+// RemoveRow does nothing.
+func (t TableContentReadOnly) RemoveRow(row int) {}
+
+// RemoveColumn does nothing.
+func (t TableContentReadOnly) RemoveColumn(column int) {}
+
+// InsertRow does nothing.
+func (t TableContentReadOnly) InsertRow(row int) {}
+
+// InsertColumn does nothing.
+func (t TableContentReadOnly) InsertColumn(column int) {}
+
+// Clear does nothing.
+func (t TableContentReadOnly) Clear() {}
+
+// TableContentSorter may be implemented by a TableContent whose rows can be
+// sorted, e.g. by querying them again in a different order. Table.SortBy()
+// calls SortRows() for contents other than the table's default content.
+type TableContentSorter interface {
+	// SortRows sorts the rows below the given number of fixed rows by the
+	// given column, in ascending or descending order. It returns the index
+	// the row with the index "row" has after sorting, or "row" if this is not
+	// known.
+	SortRows(fixedRows, column int, descending bool, row int) int
+}
+
+// tableDefaultContent is the content of a table which keeps its cells in
+// memory. It is used unless another content is set with Table.SetContent().
+type tableDefaultContent struct {
+	// The cells of the table. Rows first, then columns.
+	cells [][]*TableCell
+
+	// The rightmost column in the data set.
+	lastColumn int
+}
+
+// Clear removes all cells.
+func (t *tableDefaultContent) Clear() {
+	t.cells = nil
+	t.lastColumn = -1
+}
+
+// SetCells replaces all cells.
+func (t *tableDefaultContent) SetCells(cells [][]*TableCell) {
+	t.cells = cells
+	t.lastColumn = -1
+	for _, row := range cells {
+		if len(row)-1 > t.lastColumn {
+			t.lastColumn = len(row) - 1
+		}
+	}
+}
+
+// SetCell sets the cell at the given position, extending the rows and columns
+// as needed.
+func (t *tableDefaultContent) SetCell(row, column int, cell *TableCell) {
+	if row >= len(t.cells) {
+		t.cells = append(t.cells, make([][]*TableCell, row-len(t.cells)+1)...)
+	}
+	rowLen := len(t.cells[row])
+	if column >= rowLen {
+		t.cells[row] = append(t.cells[row], make([]*TableCell, column-rowLen+1)...)
+		for c := rowLen; c < column; c++ {
+			t.cells[row][c] = &TableCell{}
+		}
+	}
+	t.cells[row][column] = cell
+	if column > t.lastColumn {
+		t.lastColumn = column
+	}
+}
+
+// RemoveRow removes the row at the given position.
+func (t *tableDefaultContent) RemoveRow(row int) {
+	if row < 0 || row >= len(t.cells) {
+		return
+	}
+	t.cells = append(t.cells[:row], t.cells[row+1:]...)
+}
+
+// RemoveColumn removes the column at the given position.
+func (t *tableDefaultContent) RemoveColumn(column int) {
+	for row := range t.cells {
+		if column < 0 || column >= len(t.cells[row]) {
+			continue
+		}
+		t.cells[row] = append(t.cells[row][:column], t.cells[row][column+1:]...)
+	}
+	if column >= 0 && column <= t.lastColumn {
+		t.lastColumn--
+	}
+}
+
+// InsertRow inserts an empty row before the row at the given position.
+func (t *tableDefaultContent) InsertRow(row int) {
+	if row >= len(t.cells) {
+		return
+	}
+	t.cells = append(t.cells, nil)
+	copy(t.cells[row+1:], t.cells[row:])
+	t.cells[row] = nil
+}
+
+// InsertColumn inserts an empty column before the column at the given
+// position.
+func (t *tableDefaultContent) InsertColumn(column int) {
+	for row := range t.cells {
+		if column >= len(t.cells[row]) {
+			continue
+		}
+		t.cells[row] = append(t.cells[row], nil)
+		copy(t.cells[row][column+1:], t.cells[row][column:])
+		t.cells[row][column] = &TableCell{}
+	}
+	if column <= t.lastColumn {
+		t.lastColumn++
+	}
+}
+
+// GetCell returns the cell at the given position or nil if there is none.
+func (t *tableDefaultContent) GetCell(row, column int) *TableCell {
+	if row < 0 || column < 0 || row >= len(t.cells) || column >= len(t.cells[row]) {
+		return nil
+	}
+	return t.cells[row][column]
+}
+
+// GetRowCount returns the number of rows.
+func (t *tableDefaultContent) GetRowCount() int {
+	return len(t.cells)
+}
+
+// GetColumnCount returns the (maximum) number of columns.
+func (t *tableDefaultContent) GetColumnCount() int {
+	if len(t.cells) == 0 {
+		return 0
+	}
+	return t.lastColumn + 1
+}
@@ -0,0 +1,88 @@
+package tview_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// generatedContent is read-only table content with a million rows whose cells
+// are generated on demand.
+type generatedContent struct {
+	tview.TableContentReadOnly
+	calls      int  // The number of calls to GetCell().
+	descending bool // Whether the rows are in descending order.
+}
+
+func (c *generatedContent) GetCell(row, column int) *tview.TableCell {
+	c.calls++
+	if row == 0 {
+		return tview.NewTableCell(fmt.Sprintf("H%d", column))
+	}
+	if c.descending {
+		row = c.GetRowCount() - row
+	}
+	return tview.NewTableCell(fmt.Sprintf("r%d c%d", row, column))
+}
+
+func (c *generatedContent) GetRowCount() int {
+	return 1000000
+}
+
+func (c *generatedContent) GetColumnCount() int {
+	return 3
+}
+
+func (c *generatedContent) SortRows(fixedRows, column int, descending bool, selectedRow int) int {
+	c.descending = descending
+	return fixedRows
+}
+
+func TestTableContent(t *testing.T) {
+	content := &generatedContent{}
+	table := tview.NewTable().SetContent(content).SetFixed(1, 0).SetSelectable(true, false).SetSortable(true)
+	h := tviewtest.New(table, 40, 6)
+	defer h.Stop()
+
+	// Only the visible cells are requested.
+	var calls int
+	h.Do(func() {
+		calls = content.calls
+	})
+	if calls > 5000 {
+		t.Errorf("drawing the table requested %d cells", calls)
+	}
+
+	h.KeyPress(tcell.KeyEnd, 0, tcell.ModNone)
+	if !strings.Contains(h.Text(), "r999999 c2") {
+		t.Errorf("last row is not shown:\n%s", h.Text())
+	}
+
+	// Sorting is left to the content.
+	h.Type("ss")
+	h.KeyPress(tcell.KeyHome, 0, tcell.ModNone)
+	text := h.Text()
+	if !strings.Contains(text, "r999999 c0") || !strings.Contains(text, string(tview.GraphicsSortDescending)) {
+		t.Errorf("rows are not sorted in descending order:\n%s", text)
+	}
+}
+
+func TestTableDefaultContent(t *testing.T) {
+	table := tview.NewTable()
+	table.SetCellSimple(0, 0, "a").SetCellSimple(1, 0, "b").SetCellSimple(1, 1, "c")
+	table.InsertRow(1).InsertColumn(0).RemoveRow(0)
+	if rows, columns := table.GetRowCount(), table.GetColumnCount(); rows != 2 || columns != 3 {
+		t.Errorf("table has %d rows and %d columns, want 2 and 3", rows, columns)
+	}
+	if text := table.GetCell(1, 2).Text; text != "c" {
+		t.Errorf("moved cell contains %q, want %q", text, "c")
+	}
+	table.SetContent(nil)
+	if rows := table.GetRowCount(); rows != 0 {
+		t.Errorf("table has %d rows after removing its content", rows)
+	}
+}
@@ -0,0 +1,244 @@
+package tview
+
+import "github.com/gdamore/tcell"
+
+// SetEditable sets the flag that, if true, lets the user edit the table's
+// cells. This requires individual cells to be selectable (see
+// SetSelectable()). Pressing Enter on the selected cell (or double-clicking
+// it) opens an editor on top of the cell instead of calling the "selected"
+// handler. The editor is an InputField or, for columns with options (see
+// SetEditOptions()), a DropDown. Cells in fixed rows and cells which are not
+// selectable cannot be edited.
+//
+// Press Enter (or Tab) to confirm the new text, Escape to cancel editing.
+// Clicking outside of the editor also confirms the new text. The new text is
+// first checked by the function set with SetCellValidator(). If it is
+// accepted, the cell's text is changed and the handler set with
+// SetCellChangedFunc() is called.
+//
+// The cell is changed with TableCell.SetText() and then passed to the
+// content's SetCell() function. Cells which do not exist are created.
+func (t *Table) SetEditable(editable bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.editable = editable
+	return t
+}
+
+// SetEditOptions sets the options from which the user chooses the new text of
+// the cells in the given column when editing them (see SetEditable()). The
+// cells are then edited with a DropDown instead of an InputField. If no
+// options are given, the column's cells are edited with an InputField again.
+func (t *Table) SetEditOptions(column int, options ...string) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.editOptions == nil {
+		t.editOptions = make(map[int][]string)
+	}
+	if len(options) == 0 {
+		delete(t.editOptions, column)
+	} else {
+		t.editOptions[column] = options
+	}
+	return t
+}
+
+// SetCellValidator sets a function which checks the new text of an edited
+// cell (see SetEditable()) before it is changed. It receives the cell's
+// position and the new text. If it returns an error, the cell is not changed.
+// The error is shown underneath the editor, which stays open so the user may
+// correct the text or cancel editing. Provide nil to accept any text.
+func (t *Table) SetCellValidator(validator func(row, column int, text string) error) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cellValidator = validator
+	return t
+}
+
+// SetCellChangedFunc sets a handler which is called whenever the user changed
+// the text of a cell by editing it (see SetEditable()). The handler receives
+// the cell's position and its new text.
+func (t *Table) SetCellChangedFunc(handler func(row, column int, text string)) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cellChanged = handler
+	return t
+}
+
+// IsEditing returns whether the user is currently editing a cell.
+func (t *Table) IsEditing() bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.editor != nil
+}
+
+// Focus is called by the application when the primitive receives focus.
+func (t *Table) Focus(delegate func(p Primitive)) {
+	t.Box.Focus(delegate)
+	if t.editor != nil {
+		delegate(t.editor)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *Table) HasFocus() bool {
+	if t.editor != nil {
+		return t.editor.GetFocusable().HasFocus()
+	}
+	return t.hasFocus
+}
+
+// tableCellEditor is implemented by the primitives used to edit a table cell.
+type tableCellEditor interface {
+	Primitive
+	GetFocusable() Focusable
+	InRect(x, y int) bool
+	ApplyTheme(theme *Theme)
+}
+
+// startEditing opens the editor for the selected cell, if it can be edited.
+// It returns false if it cannot.
+func (t *Table) startEditing(setFocus func(p Primitive)) bool {
+	if !t.editable || !t.rowsSelectable || !t.columnsSelectable || t.selectedRow < t.fixedRows {
+		return false
+	}
+	content := t.visibleContent()
+	if t.selectedRow >= content.GetRowCount() || t.selectedColumn < 0 || t.selectedColumn >= content.GetColumnCount() {
+		return false
+	}
+	var text string
+	if cell := content.GetCell(t.selectedRow, t.selectedColumn); cell != nil {
+		if cell.NotSelectable {
+			return false
+		}
+		text = cell.Text
+	}
+	t.editRow, t.editColumn = t.sourceRow(t.selectedRow), t.selectedColumn
+	t.editError = ""
+	t.editSetFocus = setFocus
+
+	if options := t.editOptions[t.editColumn]; len(options) > 0 {
+		// Choose one of the options.
+		dropDown := NewDropDown()
+		current := 0
+		for index, option := range options {
+			if option == text {
+				current = index
+			}
+			dropDown.AddOption(option, nil, func() {
+				// This is called by the list of options first and then again
+				// by the drop-down once it closed the list.
+				if !dropDown.open {
+					t.finishEditing(true)
+				}
+			})
+		}
+		dropDown.SetCurrentOption(current)
+		dropDown.SetDoneFunc(func(key tcell.Key) {
+			t.finishEditing(key != tcell.KeyEscape)
+		})
+		t.setEditor(dropDown)
+		setFocus(dropDown)
+		dropDown.openList(setFocus)
+		dropDown.list.SetDoneFunc(func() {
+			t.finishEditing(false)
+		})
+		return true
+	}
+
+	// Enter the new text.
+	inputField := NewInputField().SetText(text)
+	inputField.SetDoneFunc(func(key tcell.Key) {
+		t.finishEditing(key != tcell.KeyEscape)
+	})
+	t.setEditor(inputField)
+	setFocus(inputField)
+	return true
+}
+
+// setEditor sets the primitive used to edit the selected cell, applying the
+// table's theme to it.
+func (t *Table) setEditor(editor tableCellEditor) {
+	if t.editTheme != nil {
+		editor.ApplyTheme(t.editTheme)
+	}
+	t.editor = editor
+}
+
+// editorText returns the text entered into or chosen with the editor.
+func (t *Table) editorText() string {
+	switch editor := t.editor.(type) {
+	case *InputField:
+		return editor.GetText()
+	case *DropDown:
+		if _, option := editor.GetCurrentOption(); option != nil {
+			return option.Text
+		}
+	}
+	return ""
+}
+
+// finishEditing closes the editor and returns the focus to the table. If
+// "commit" is true, the edited cell is changed to the editor's text first,
+// unless the validator rejects it. The editor stays open in that case.
+func (t *Table) finishEditing(commit bool) {
+	if t.editor == nil {
+		return
+	}
+	if commit {
+		text := t.editorText()
+		if t.cellValidator != nil {
+			if err := t.cellValidator(t.editRow, t.editColumn, text); err != nil {
+				t.editError = err.Error()
+				return
+			}
+		}
+		cell := t.content.GetCell(t.editRow, t.editColumn)
+		if cell == nil {
+			cell = NewTableCell("")
+		}
+		previousText := cell.Text
+		cell.SetText(text)
+		t.content.SetCell(t.editRow, t.editColumn, cell)
+		t.filterDirty = true
+		if text != previousText {
+			t.cellsChanged()
+			if t.cellChanged != nil {
+				go t.cellChanged(t.editRow, t.editColumn, text)
+			}
+		}
+	}
+	t.editor = nil
+	t.editError = ""
+	t.editSetFocus(t)
+}
+
+// drawEditor draws the editor on top of the cell at the given position (see
+// TableCell.GetLastPosition()) and, if the editor's text was rejected, the
+// error message underneath it.
+func (t *Table) drawEditor(screen tcell.Screen, x, y, width int) {
+	if width < 1 {
+		width = 1
+	}
+	switch editor := t.editor.(type) {
+	case *InputField:
+		editor.SetFieldWidth(width)
+	case *DropDown:
+		editor.SetFieldWidth(width)
+	}
+	t.editor.SetRect(x, y, width, 1)
+	t.editor.Draw(screen)
+	if t.editError != "" {
+		_, screenHeight := screen.Size()
+		errorY := y + 1
+		if errorY >= screenHeight {
+			errorY = y - 1
+		}
+		Print(screen, t.editError, x, errorY, StringWidth(t.editError), AlignLeft, t.editErrorColor)
+	}
+}
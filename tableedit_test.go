@@ -0,0 +1,136 @@
+package tview_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// cellText returns the text of the given table cell.
+func cellText(h *tviewtest.Harness, table *tview.Table, row, column int) (text string) {
+	h.Do(func() {
+		if cell := table.GetCell(row, column); cell != nil {
+			text = cell.Text
+		}
+	})
+	return
+}
+
+// editing returns whether a cell of the table is being edited.
+func editing(h *tviewtest.Harness, table *tview.Table) (editing bool) {
+	h.Do(func() {
+		editing = table.IsEditing()
+	})
+	return
+}
+
+func TestTableEdit(t *testing.T) {
+	table := tview.NewTable().SetFixed(1, 0).SetSelectable(true, true).SetEditable(true)
+	for row, texts := range [][]string{
+		{"Key", "Value", "Level"},
+		{"port", "80", "info"},
+		{"host", "localhost", "debug"},
+	} {
+		for column, text := range texts {
+			table.SetCellSimple(row, column, text)
+		}
+	}
+	table.SetEditOptions(2, "debug", "info", "warn")
+	table.SetCellValidator(func(row, column int, text string) error {
+		if column == 1 && strings.Trim(text, "0123456789") != "" {
+			return errors.New("not a number")
+		}
+		return nil
+	})
+	changed := make(chan string, 10)
+	table.SetCellChangedFunc(func(row, column int, text string) {
+		changed <- text
+	})
+	table.Select(1, 1)
+	h := tviewtest.New(table, 30, 6)
+	defer h.Stop()
+
+	// Rejected text keeps the editor open.
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if !editing(h, table) {
+		t.Fatal("Enter did not open the editor")
+	}
+	h.Type("x")
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if !editing(h, table) || !strings.Contains(h.Text(), "not a number") {
+		t.Fatalf("rejected text closed the editor:\n%s", h.Text())
+	}
+	h.KeyPress(tcell.KeyBackspace2, 0, tcell.ModNone)
+	h.Type("80")
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if editing(h, table) || cellText(h, table, 1, 1) != "8080" {
+		t.Fatalf("cell contains %q after editing", cellText(h, table, 1, 1))
+	}
+	if text := <-changed; text != "8080" {
+		t.Errorf("changed handler received %q", text)
+	}
+
+	// Escape cancels editing.
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	h.Type("0")
+	h.KeyPress(tcell.KeyEscape, 0, tcell.ModNone)
+	if editing(h, table) || cellText(h, table, 1, 1) != "8080" {
+		t.Fatalf("cell contains %q after cancelling", cellText(h, table, 1, 1))
+	}
+
+	// Columns with options are edited with a drop-down.
+	h.KeyPress(tcell.KeyRight, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if editing(h, table) || cellText(h, table, 1, 2) != "warn" {
+		t.Fatalf("cell contains %q after choosing an option", cellText(h, table, 1, 2))
+	}
+	if text := <-changed; text != "warn" {
+		t.Errorf("changed handler received %q", text)
+	}
+	if p := focus(h); p != table {
+		t.Errorf("focus is on %T after editing, want the table", p)
+	}
+}
+
+func TestTableEditScroll(t *testing.T) {
+	table := tview.NewTable().SetSelectable(true, true).SetEditable(true)
+	for row := 0; row < 20; row++ {
+		table.SetCellSimple(row, 0, fmt.Sprintf("row %d", row))
+	}
+	table.Select(1, 0)
+	h := tviewtest.New(table, 20, 5)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.EnableMouse(true)
+	})
+
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	h.Type("x")
+	for count := 0; count < 10; count++ {
+		h.Mouse(0, 3, tcell.WheelDown, tcell.ModNone)
+	}
+	h.Mouse(0, 3, tcell.ButtonNone, tcell.ModNone)
+	if strings.Contains(h.Text(), "1x") {
+		t.Errorf("editor was drawn although its cell was scrolled away:\n%s", h.Text())
+	}
+
+	for count := 0; count < 20; count++ {
+		h.Mouse(0, 3, tcell.WheelUp, tcell.ModNone)
+	}
+	h.Mouse(0, 3, tcell.ButtonNone, tcell.ModNone)
+	if !strings.Contains(h.Text(), "1x") || strings.Contains(h.Text(), "row 1\n") {
+		t.Errorf("editor was not drawn on its cell:\n%s", h.Text())
+	}
+
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if text := cellText(h, table, 1, 0); text != "row 1x" {
+		t.Errorf("edited cell contains %q, want %q", text, "row 1x")
+	}
+}
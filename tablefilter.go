@@ -0,0 +1,355 @@
+package tview
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// tableFilteredContent is the content shown by a filtered table: the rows of
+// the table's content which passed the filter, in their original order.
+type tableFilteredContent struct {
+	TableContentReadOnly
+
+	// The table's content.
+	content TableContent
+
+	// The indices of the rows of the content which are shown, including the
+	// fixed rows.
+	rows []int
+}
+
+// GetCell returns the cell at the given position or nil if there is none.
+func (t *tableFilteredContent) GetCell(row, column int) *TableCell {
+	if row < 0 || row >= len(t.rows) {
+		return nil
+	}
+	return t.content.GetCell(t.rows[row], column)
+}
+
+// GetRowCount returns the number of rows which passed the filter.
+func (t *tableFilteredContent) GetRowCount() int {
+	return len(t.rows)
+}
+
+// GetColumnCount returns the number of columns of the content.
+func (t *tableFilteredContent) GetColumnCount() int {
+	return t.content.GetColumnCount()
+}
+
+// SetFilterFunc sets a function which decides which of the table's rows are
+// shown. It receives the index of each row below the fixed rows and its cells
+// (missing cells are passed as empty cells) and returns true if the row is to
+// be shown. Fixed rows are always shown. A nil function removes the filter.
+//
+// While the table is filtered, row indices passed to and returned from the
+// table's functions, e.g. Select(), GetSelection(), and the handlers set with
+// SetSelectedFunc() and SetSelectionChangedFunc(), still refer to the rows of
+// the unfiltered table. The function is only called again when the table's
+// cells are changed through the table (e.g. with SetCell()) or when the rows
+// are sorted. If the content set with SetContent() changes by other means,
+// call SetFilterFunc() again.
+//
+// The filter may be combined with a filter pattern (see SetFilter()). Rows
+// are then only shown if they pass both filters.
+func (t *Table) SetFilterFunc(filter func(row int, cells []*TableCell) bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filter = filter
+	t.filterDirty = true
+	return t
+}
+
+// SetFilter filters the table's rows with the given pattern: only rows whose
+// cells contain the pattern (in the columns set with SetFilterColumns()) are
+// shown, in addition to the fixed rows. The pattern is a regular expression
+// if SetFilterRegexp() was set to true. Otherwise, it is plain text. An empty
+// pattern removes the filter. An error is returned if the pattern is not a
+// valid regular expression.
+//
+// The number of matching rows is shown in the table's title. See
+// SetFilterFunc() for details on how row indices are handled.
+func (t *Table) SetFilter(pattern string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.setFilterPattern(pattern)
+}
+
+// GetFilter returns the current filter pattern.
+func (t *Table) GetFilter() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.filterPattern
+}
+
+// SetFilterColumns sets the columns which are searched for the filter pattern
+// (see SetFilter()). If no columns are given, all columns are searched.
+func (t *Table) SetFilterColumns(columns ...int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterColumns = columns
+	t.filterDirty = true
+	return t
+}
+
+// SetFilterRegexp sets the flag that, if true, causes filter patterns to be
+// interpreted as regular expressions (see the regexp package). Otherwise, they
+// are matched as plain text.
+func (t *Table) SetFilterRegexp(filterRegexp bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterRegexp = filterRegexp
+	if t.filterPattern != "" {
+		t.setFilterPattern(t.filterPattern)
+	}
+	return t
+}
+
+// SetFilterable sets the flag that, if true, lets the user filter the table's
+// rows:
+//
+//   - /: Enter a filter pattern.
+//   - Escape: Remove the filter.
+//
+// The table is filtered while the pattern is being entered. A line at the
+// bottom of the table shows the pattern. Press Enter to confirm the pattern or
+// Escape to remove the filter.
+func (t *Table) SetFilterable(filterable bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterable = filterable
+	if !filterable {
+		t.filterPrompt = false
+	}
+	return t
+}
+
+// capturesTextInput returns whether the table currently uses printable
+// characters, i.e. while the filter prompt is open.
+func (t *Table) capturesTextInput() bool {
+	return t.filterPrompt
+}
+
+// ClearFilter removes the filter pattern and the filter function so that all
+// rows are shown again.
+func (t *Table) ClearFilter() *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filter = nil
+	t.filterPrompt = false
+	t.setFilterPattern("")
+	return t
+}
+
+// GetFilterCount returns the number of rows below the fixed rows which pass
+// the filter and the total number of rows below the fixed rows. If the table
+// is not filtered, both values are the same.
+func (t *Table) GetFilterCount() (matching, total int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.updateFilter()
+	return t.filterCount()
+}
+
+// setFilterPattern sets and compiles the filter pattern.
+func (t *Table) setFilterPattern(pattern string) error {
+	t.filterPattern = pattern
+	t.filterExpression = nil
+	t.filterDirty = true
+	if pattern == "" {
+		return nil
+	}
+	if !t.filterRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	t.filterExpression = expression
+	return nil
+}
+
+// visibleContent returns the content as it is shown, i.e. only the rows which
+// pass the filter.
+func (t *Table) visibleContent() TableContent {
+	t.updateFilter()
+	if t.filteredContent == nil {
+		return t.content
+	}
+	return t.filteredContent
+}
+
+// updateFilter filters the rows again if the filter or the table's content
+// changed since the rows were last filtered.
+func (t *Table) updateFilter() {
+	if t.filterDirty {
+		t.applyFilter(t.sourceRow(t.selectedRow))
+	}
+}
+
+// applyFilter determines the rows which pass the filter. The selection is set
+// to the given row of the table's content or, if it did not pass the filter,
+// to the next row which did.
+func (t *Table) applyFilter(selectedRow int) {
+	t.filterDirty = false
+	if t.filter == nil && t.filterExpression == nil {
+		t.filteredContent = nil
+		t.selectedRow = selectedRow
+		return
+	}
+
+	rowCount, columnCount := t.content.GetRowCount(), t.content.GetColumnCount()
+	rows := make([]int, 0, rowCount)
+	cells := make([]*TableCell, columnCount)
+	for row := 0; row < rowCount; row++ {
+		if row < t.fixedRows {
+			rows = append(rows, row)
+			continue
+		}
+		for column := range cells {
+			cells[column] = t.content.GetCell(row, column)
+			if cells[column] == nil {
+				cells[column] = &TableCell{}
+			}
+		}
+		if t.filterExpression != nil && !t.matchFilter(cells) {
+			continue
+		}
+		if t.filter != nil && !t.filter(row, cells) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	t.filteredContent = &tableFilteredContent{content: t.content, rows: rows}
+	t.selectedRow = t.displayRow(selectedRow)
+}
+
+// matchFilter returns whether the filter pattern is found in any of the given
+// cells of a row, searching only the filter columns if they were set.
+func (t *Table) matchFilter(cells []*TableCell) bool {
+	if len(t.filterColumns) == 0 {
+		for _, cell := range cells {
+			if t.filterExpression.MatchString(plainCellText(cell)) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, column := range t.filterColumns {
+		if column >= 0 && column < len(cells) && t.filterExpression.MatchString(plainCellText(cells[column])) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCount returns the number of rows below the fixed rows which passed the
+// filter and the total number of rows below the fixed rows.
+func (t *Table) filterCount() (matching, total int) {
+	total = t.content.GetRowCount() - t.fixedRows
+	if total < 0 {
+		total = 0
+	}
+	if t.filteredContent == nil {
+		return total, total
+	}
+	matching = len(t.filteredContent.rows) - t.fixedRows
+	if matching < 0 {
+		matching = 0
+	}
+	return
+}
+
+// sourceRow returns the index in the table's content of the row with the given
+// index in the filtered table.
+func (t *Table) sourceRow(row int) int {
+	if t.filteredContent == nil || row < 0 {
+		return row
+	}
+	rows := t.filteredContent.rows
+	if row >= len(rows) {
+		return row - len(rows) + t.content.GetRowCount()
+	}
+	return rows[row]
+}
+
+// displayRow returns the index in the filtered table of the row with the given
+// index in the table's content. If that row did not pass the filter, the index
+// of the next row which did is returned (or the last row if there is none).
+func (t *Table) displayRow(row int) int {
+	if t.filteredContent == nil || row < 0 {
+		return row
+	}
+	rows := t.filteredContent.rows
+	index := sort.SearchInts(rows, row)
+	if index >= len(rows) {
+		index = len(rows) - 1
+	}
+	return index
+}
+
+// filterInput processes a key event while the filter prompt is open. The
+// table is filtered as the pattern changes.
+func (t *Table) filterInput(event *tcell.EventKey) {
+	pattern := t.filterPattern
+	switch event.Key() {
+	case tcell.KeyRune:
+		pattern += string(event.Rune())
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if pattern == "" {
+			t.filterPrompt = false
+			return
+		}
+		_, size := utf8.DecodeLastRuneInString(pattern)
+		pattern = pattern[:len(pattern)-size]
+	case tcell.KeyEnter:
+		t.filterPrompt = false
+		return
+	case tcell.KeyEscape:
+		t.filterPrompt = false
+		pattern = ""
+	default:
+		return
+	}
+	t.setFilterPattern(pattern)
+}
+
+// drawFilterBar draws the filter pattern into the screen line at the given
+// position.
+func (t *Table) drawFilterBar(screen tcell.Screen, x, y, width int) {
+	var status string
+	if t.filterPattern != "" && t.filterExpression == nil {
+		status = "invalid pattern"
+	} else if t.filterExpression != nil {
+		matching, total := t.filterCount()
+		status = fmt.Sprintf("%d/%d", matching, total)
+	}
+	_, statusWidth := Print(screen, status, x, y, width, AlignRight, t.filterColor)
+	_, promptWidth := Print(screen, "/"+Escape(t.filterPattern), x, y, width-statusWidth-1, AlignLeft, t.filterColor)
+	if t.filterPrompt && t.HasFocus() {
+		screen.ShowCursor(x+promptWidth, y)
+	}
+}
+
+// filterTitle returns the table's title followed by the number of matching
+// rows if the table is filtered.
+func (t *Table) filterTitle(title string) string {
+	if t.filteredContent == nil {
+		return title
+	}
+	matching, total := t.filterCount()
+	return strings.TrimRight(title, " ") + fmt.Sprintf(" (%d/%d) ", matching, total)
+}
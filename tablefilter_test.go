@@ -0,0 +1,99 @@
+package tview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// filterCount returns the number of rows which pass the table's filter and
+// the total number of rows.
+func filterCount(h *tviewtest.Harness, table *tview.Table) (matching, total int) {
+	h.Do(func() {
+		matching, total = table.GetFilterCount()
+	})
+	return
+}
+
+func TestTableFilter(t *testing.T) {
+	table := newTable([][]string{
+		{"Name", "Color"},
+		{"apple", "red"},
+		{"banana", "yellow"},
+		{"cherry", "red"},
+		{"date", "brown"},
+	})
+	table.SetFixed(1, 0).SetSelectable(true, false).SetFilterable(true)
+	table.SetBorder(true).SetTitle(" Fruits ")
+	selected := make(chan int, 1)
+	table.SetSelectedFunc(func(row, column int) {
+		selected <- row
+	})
+	h := tviewtest.New(table, 30, 10)
+	defer h.Stop()
+
+	// The rows are filtered while the pattern is entered.
+	h.Type("/red")
+	text := h.Text()
+	if strings.Contains(text, "banana") || !strings.Contains(text, "cherry") {
+		t.Errorf("filter did not hide the rows without a match:\n%s", text)
+	}
+	if !strings.Contains(text, "(2/4)") || !strings.Contains(text, "/red") {
+		t.Errorf("filter count or pattern is missing:\n%s", text)
+	}
+
+	// Rows are selected by their index in the table's content.
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if row := <-selected; row != 3 {
+		t.Errorf("selected handler received row %d, want 3", row)
+	}
+
+	// Escape removes the filter but keeps the selection.
+	h.KeyPress(tcell.KeyEscape, 0, tcell.ModNone)
+	if !strings.Contains(h.Text(), "banana") {
+		t.Errorf("Escape did not remove the filter:\n%s", h.Text())
+	}
+	if name := selectedText(h, table); name != "cherry" {
+		t.Errorf("selection moved to %s, want it to stay on cherry", name)
+	}
+
+	// Regular expressions on some columns.
+	var err error
+	h.Do(func() {
+		err = table.SetFilterColumns(0).SetFilterRegexp(true).SetFilter("^[ab]")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matching, total := filterCount(h, table); matching != 2 || total != 4 {
+		t.Errorf("regular expression matched %d of %d rows, want 2 of 4", matching, total)
+	}
+
+	// Filter functions.
+	h.Do(func() {
+		table.SetFilter("")
+		table.SetFilterFunc(func(row int, cells []*tview.TableCell) bool {
+			return cells[1].Text == "brown"
+		})
+	})
+	if matching, _ := filterCount(h, table); matching != 1 {
+		t.Errorf("filter function matched %d rows, want 1", matching)
+	}
+	h.Sync()
+	if text := h.Text(); !strings.Contains(text, "date") || strings.Contains(text, "apple") {
+		t.Errorf("filter function did not hide the rows:\n%s", text)
+	}
+	h.Do(func() {
+		table.ClearFilter()
+	})
+	h.Sync()
+	if !strings.Contains(h.Text(), "apple") {
+		t.Errorf("ClearFilter() did not show all rows:\n%s", h.Text())
+	}
+}
@@ -0,0 +1,150 @@
+package tview
+
+import "github.com/gdamore/tcell"
+
+// SetMultiSelect sets the flag that, if true, lets the user select multiple
+// rows of a table whose rows are selectable (see SetSelectable()). The
+// selected rows are independent of the current row, i.e. the selection
+// returned by GetSelection(). The following keys are available:
+//
+//   - Space: Select or unselect the current row.
+//   - Shift-up/down arrow, Shift-page up/down, Shift-home/end: Move the
+//     current row and select all rows between the row where this started and
+//     the new current row.
+//   - Ctrl-A: Select all rows.
+//   - *: Invert the selection.
+//
+// Clicking a row with Ctrl pressed selects or unselects it. Clicking it with
+// Shift pressed selects all rows between the current row and the clicked row.
+// Fixed rows cannot be selected. Selected rows are shown with the background
+// color set with SetSelectedRowsColor().
+func (t *Table) SetMultiSelect(multiSelect bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.multiSelect = multiSelect
+	return t
+}
+
+// GetSelectedRows returns the indices of the selected rows (see
+// SetMultiSelect()) in ascending order. This includes rows hidden by a filter
+// (see SetFilter()).
+func (t *Table) GetSelectedRows() []int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.rowSelection.list()
+}
+
+// SetSelectedRows selects the rows with the given indices, replacing the
+// previously selected rows.
+func (t *Table) SetSelectedRows(rows ...int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.rowSelection.set(rows)
+	return t
+}
+
+// SelectAllRows selects all rows below the fixed rows which are shown, i.e.
+// which were not hidden by a filter.
+func (t *Table) SelectAllRows() *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectAllRows(false)
+	return t
+}
+
+// InvertSelectedRows selects all rows below the fixed rows which are shown and
+// not selected and unselects those which are selected.
+func (t *Table) InvertSelectedRows() *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectAllRows(true)
+	return t
+}
+
+// ClearSelectedRows unselects all rows.
+func (t *Table) ClearSelectedRows() *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.rowSelection.set(nil)
+	return t
+}
+
+// SetSelectedRowsChangedFunc sets a handler which is called whenever the user
+// changes which rows are selected (see SetMultiSelect()). The handler
+// receives the indices of the selected rows as returned by GetSelectedRows().
+func (t *Table) SetSelectedRowsChangedFunc(handler func(rows []int)) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedRowsChanged = handler
+	return t
+}
+
+// SetSelectedRowsColor sets the background color of selected rows (see
+// SetMultiSelect()).
+func (t *Table) SetSelectedRowsColor(color tcell.Color) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedRowsColor = color
+	return t
+}
+
+// selectAllRows selects all rows below the fixed rows which are shown. If
+// "invert" is true, those which are already selected are unselected instead.
+func (t *Table) selectAllRows(invert bool) {
+	content := t.visibleContent()
+	rows := make([]int, 0, content.GetRowCount())
+	for row := t.fixedRows; row < content.GetRowCount(); row++ {
+		if source := t.sourceRow(row); !invert || !t.rowSelection.has(source) {
+			rows = append(rows, source)
+		}
+	}
+	if invert {
+		// Keep the selected rows which are hidden.
+		for _, source := range t.rowSelection.list() {
+			if row := t.displayRow(source); t.sourceRow(row) != source {
+				rows = append(rows, source)
+			}
+		}
+	} else {
+		rows = append(rows, t.rowSelection.list()...)
+	}
+	t.rowSelection.set(rows)
+}
+
+// toggleRow selects the row with the given index in the (filtered) table if
+// it is not selected and unselects it otherwise. Fixed rows are ignored.
+func (t *Table) toggleRow(row int) {
+	if row >= t.fixedRows && row < t.visibleContent().GetRowCount() {
+		t.rowSelection.toggle(t.sourceRow(row))
+	}
+}
+
+// selectRowRange selects all rows between the row with the given index in the
+// (filtered) table and the row where the range selection started, which is
+// "from" if no range selection was started yet. Fixed rows are ignored.
+func (t *Table) selectRowRange(from, to int) {
+	t.rowSelection.startRange(t.sourceRow(from))
+	from = t.displayRow(t.rowSelection.anchor)
+	if from > to {
+		from, to = to, from
+	}
+	if from < t.fixedRows {
+		from = t.fixedRows
+	}
+	if rowCount := t.visibleContent().GetRowCount(); to >= rowCount {
+		to = rowCount - 1
+	}
+	var rows []int
+	for row := from; row <= to; row++ {
+		rows = append(rows, t.sourceRow(row))
+	}
+	t.rowSelection.setRange(rows)
+}
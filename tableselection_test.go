@@ -0,0 +1,79 @@
+package tview_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// selectedRows returns the table's selected rows as text.
+func selectedRows(h *tviewtest.Harness, table *tview.Table) (rows string) {
+	h.Do(func() {
+		rows = fmt.Sprint(table.GetSelectedRows())
+	})
+	return
+}
+
+func TestTableMultiSelect(t *testing.T) {
+	table := newTable([][]string{{"Name"}, {"e"}, {"d"}, {"c"}, {"b"}, {"a"}})
+	table.SetFixed(1, 0).SetSelectable(true, false).SetMultiSelect(true)
+	changed := make(chan []int, 10)
+	table.SetSelectedRowsChangedFunc(func(rows []int) {
+		changed <- rows
+	})
+	table.Select(1, 0)
+	h := tviewtest.New(table, 20, 8)
+	defer h.Stop()
+
+	// Space toggles a row, Shift extends the selection.
+	h.Type(" ")
+	if rows := fmt.Sprint(<-changed); rows != "[1]" {
+		t.Errorf("changed handler received %s, want [1]", rows)
+	}
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModShift)
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModShift)
+	h.KeyPress(tcell.KeyUp, 0, tcell.ModShift)
+	if rows := selectedRows(h, table); rows != "[1 2 3]" {
+		t.Errorf("selected rows are %s, want [1 2 3]", rows)
+	}
+
+	// Invert the selection.
+	h.Type("*")
+	if rows := selectedRows(h, table); rows != "[4 5]" {
+		t.Errorf("selected rows are %s after inverting, want [4 5]", rows)
+	}
+
+	// The selection follows sorted rows.
+	h.Do(func() {
+		table.SortBy(0, false)
+	})
+	if rows := selectedRows(h, table); rows != "[1 2]" {
+		t.Errorf("selected rows are %s after sorting, want [1 2]", rows)
+	}
+
+	// Only rows which pass the filter are selected and inverted.
+	h.Do(func() {
+		table.SetFilter("a")
+	})
+	h.KeyPress(tcell.KeyCtrlA, 0, tcell.ModCtrl)
+	if rows := selectedRows(h, table); rows != "[1 2]" {
+		t.Errorf("selected rows are %s after selecting all filtered rows, want [1 2]", rows)
+	}
+	h.Type("*")
+	if rows := selectedRows(h, table); rows != "[2]" {
+		t.Errorf("selected rows are %s after inverting filtered rows, want [2]", rows)
+	}
+
+	// Removing rows shifts the selection.
+	h.Do(func() {
+		table.SetFilter("")
+		table.RemoveRow(1)
+	})
+	if rows := selectedRows(h, table); rows != "[1]" {
+		t.Errorf("selected rows are %s after removing a row, want [1]", rows)
+	}
+}
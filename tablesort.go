@@ -0,0 +1,220 @@
+package tview
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableComparator compares two cells of a table column for sorting. It returns
+// a negative value if cell a comes before cell b, a positive value if it comes
+// after cell b, and 0 if their order does not matter. Missing cells are passed
+// as empty cells.
+type TableComparator func(a, b *TableCell) int
+
+// Predefined table comparators. Color tags are ignored when comparing cells.
+var (
+	// CompareText compares the cells' texts lexically.
+	CompareText TableComparator = func(a, b *TableCell) int {
+		return strings.Compare(plainCellText(a), plainCellText(b))
+	}
+
+	// CompareTextFold compares the cells' texts lexically, ignoring case.
+	CompareTextFold TableComparator = func(a, b *TableCell) int {
+		return strings.Compare(strings.ToLower(plainCellText(a)), strings.ToLower(plainCellText(b)))
+	}
+
+	// CompareNumbers compares the cells' texts as floating-point numbers. Cells
+	// which do not contain a number come last.
+	CompareNumbers TableComparator = func(a, b *TableCell) int {
+		textA, textB := plainCellText(a), plainCellText(b)
+		numberA, errA := strconv.ParseFloat(strings.TrimSpace(textA), 64)
+		numberB, errB := strconv.ParseFloat(strings.TrimSpace(textB), 64)
+		switch {
+		case errA != nil && errB != nil:
+			return strings.Compare(textA, textB)
+		case errA != nil:
+			return 1
+		case errB != nil:
+			return -1
+		case numberA < numberB:
+			return -1
+		case numberA > numberB:
+			return 1
+		}
+		return 0
+	}
+)
+
+// CompareTime returns a comparator which compares the cells' texts as times in
+// the given layout (see time.Parse()). Cells which do not contain a valid time
+// come last.
+func CompareTime(layout string) TableComparator {
+	return func(a, b *TableCell) int {
+		textA, textB := plainCellText(a), plainCellText(b)
+		timeA, errA := time.Parse(layout, strings.TrimSpace(textA))
+		timeB, errB := time.Parse(layout, strings.TrimSpace(textB))
+		switch {
+		case errA != nil && errB != nil:
+			return strings.Compare(textA, textB)
+		case errA != nil:
+			return 1
+		case errB != nil:
+			return -1
+		case timeA.Before(timeB):
+			return -1
+		case timeA.After(timeB):
+			return 1
+		}
+		return 0
+	}
+}
+
+// plainCellText returns the text of the given cell without color tags.
+func plainCellText(cell *TableCell) string {
+	return escapePattern.ReplaceAllString(colorPattern.ReplaceAllString(cell.Text, ""), "[$1$2]")
+}
+
+// SetSortable sets the flag which determines whether the user may sort the
+// table's rows. If true, clicking a cell in a fixed row sorts the rows by the
+// cell's column, and pressing "s" sorts them by the selected column (or, if
+// only rows are selectable, by the column the table is already sorted by, the
+// first column otherwise). Sorting by the same column again reverses the
+// order. See SortBy() for details.
+func (t *Table) SetSortable(sortable bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.sortable = sortable
+	return t
+}
+
+// SetComparator sets the comparator used to sort the rows by the given column.
+// Columns without a comparator are sorted with CompareText.
+func (t *Table) SetComparator(column int, comparator TableComparator) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.comparators == nil {
+		t.comparators = make(map[int]TableComparator)
+	}
+	if comparator == nil {
+		delete(t.comparators, column)
+	} else {
+		t.comparators[column] = comparator
+	}
+	return t
+}
+
+// SortBy sorts all rows except for the fixed rows by the given column, in
+// ascending or descending order, using the column's comparator (see
+// SetComparator()). Rows which compare equal keep their order. If a row is
+// selected, the selection moves with it.
+//
+// The header cell of the sorted column, i.e. its cell in the last fixed row,
+// shows an arrow (see GraphicsSortAscending and GraphicsSortDescending). Rows
+// added or changed later are not sorted until SortBy() is called again. A
+// negative column removes the indicator without changing the order of the
+// rows.
+//
+// If the table's content was set with SetContent(), the rows are sorted by the
+// content if it implements TableContentSorter. Otherwise, only the indicator is
+// updated. Rows selected with SetMultiSelect() stay selected unless the rows are
+// sorted by the content; they are then unselected.
+func (t *Table) SortBy(column int, descending bool) *Table {
+	t.sortColumn, t.sortDescending = column, descending
+	if column < 0 || t.fixedRows >= t.content.GetRowCount() {
+		return t
+	}
+
+	// Sort the unfiltered rows and filter them again afterwards.
+	t.updateFilter()
+	t.selectedRow = t.sourceRow(t.selectedRow)
+	defer func() {
+		t.applyFilter(t.selectedRow)
+	}()
+
+	content, ok := t.content.(*tableDefaultContent)
+	if !ok {
+		if sorter, ok := t.content.(TableContentSorter); ok {
+			t.selectedRow = sorter.SortRows(t.fixedRows, column, descending, t.selectedRow)
+			t.rowSelection.set(nil)
+		}
+		return t
+	}
+
+	comparator := t.comparators[column]
+	if comparator == nil {
+		comparator = CompareText
+	}
+	cell := func(row int) *TableCell {
+		if cell := content.GetCell(row, column); cell != nil {
+			return cell
+		}
+		return &TableCell{}
+	}
+
+	// Sort the row indices so we can find the selected row afterwards.
+	order := make([]int, len(content.cells)-t.fixedRows)
+	for index := range order {
+		order[index] = t.fixedRows + index
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		result := comparator(cell(order[i]), cell(order[j]))
+		if descending {
+			return result > 0
+		}
+		return result < 0
+	})
+
+	rows := make([][]*TableCell, len(order))
+	selectedRow := t.selectedRow
+	for index, row := range order {
+		rows[index] = content.cells[row]
+		if row == selectedRow {
+			t.selectedRow = t.fixedRows + index
+		}
+	}
+
+	// Selected rows move with the sorted rows.
+	if len(t.rowSelection.indices) > 0 {
+		sorted := make(map[int]int, len(order))
+		for index, row := range order {
+			sorted[row] = t.fixedRows + index
+		}
+		t.rowSelection.remap(func(row int) int {
+			if index, ok := sorted[row]; ok {
+				return index
+			}
+			return row
+		})
+	}
+	copy(content.cells[t.fixedRows:], rows)
+	return t
+}
+
+// GetSortColumn returns the column the rows were last sorted by with SortBy()
+// (a negative value if they were not sorted) and whether they were sorted in
+// descending order.
+func (t *Table) GetSortColumn() (column int, descending bool) {
+	return t.sortColumn, t.sortDescending
+}
+
+// toggleSort sorts the rows by the given column, in ascending order unless
+// they are already sorted in ascending order by that column.
+func (t *Table) toggleSort(column int) {
+	t.SortBy(column, column == t.sortColumn && !t.sortDescending)
+}
+
+// cellText returns the text shown for the given cell, i.e. its text followed
+// by the sort indicator if it is the header cell of the sorted column.
+func (t *Table) cellText(row, column int, cell *TableCell) string {
+	if row != t.fixedRows-1 || column != t.sortColumn {
+		return cell.Text
+	}
+	if t.sortDescending {
+		return cell.Text + " " + string(GraphicsSortDescending)
+	}
+	return cell.Text + " " + string(GraphicsSortAscending)
+}
@@ -0,0 +1,92 @@
+package tview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// newTable returns a table with the given cell texts.
+func newTable(texts [][]string) *tview.Table {
+	table := tview.NewTable()
+	for row, rowTexts := range texts {
+		for column, text := range rowTexts {
+			table.SetCellSimple(row, column, text)
+		}
+	}
+	return table
+}
+
+// column returns the texts of the given column's cells below the header row,
+// separated by commas.
+func column(h *tviewtest.Harness, table *tview.Table, column int) string {
+	var texts []string
+	h.Do(func() {
+		for row := 1; row < table.GetRowCount(); row++ {
+			texts = append(texts, table.GetCell(row, column).Text)
+		}
+	})
+	return strings.Join(texts, ",")
+}
+
+// selectedText returns the text of the first cell of the selected row.
+func selectedText(h *tviewtest.Harness, table *tview.Table) (text string) {
+	h.Do(func() {
+		row, _ := table.GetSelection()
+		text = table.GetCell(row, 0).Text
+	})
+	return
+}
+
+func TestTableSort(t *testing.T) {
+	table := newTable([][]string{
+		{"Name", "Size", "Date"},
+		{"bob", "10", "2020-01-03"},
+		{"alice", "9", "2019-05-01"},
+		{"carol", "100", "2021-02-02"},
+		{"dave", "n/a", "bad"},
+	})
+	table.SetFixed(1, 0).SetSelectable(true, false).SetSortable(true)
+	table.SetComparator(1, tview.CompareNumbers).SetComparator(2, tview.CompareTime("2006-01-02"))
+	table.Select(2, 0)
+	h := tviewtest.New(table, 30, 6)
+	defer h.Stop()
+	h.Do(func() {
+		h.App.EnableMouse(true)
+	})
+
+	// Clicking a header sorts by its column, then reverses the order.
+	h.Click(6, 0)
+	if sizes := column(h, table, 1); sizes != "9,10,100,n/a" {
+		t.Errorf("sizes sorted to %s", sizes)
+	}
+	if !strings.Contains(h.Text(), "Size "+string(tview.GraphicsSortAscending)) {
+		t.Errorf("sort indicator is missing:\n%s", h.Text())
+	}
+	if name := selectedText(h, table); name != "alice" {
+		t.Errorf("selection moved to %s, want it to stay on alice", name)
+	}
+	h.Click(6, 0)
+	if sizes := column(h, table, 1); sizes != "n/a,100,10,9" {
+		t.Errorf("sizes sorted to %s in descending order", sizes)
+	}
+
+	// The sort key is available on the keyboard.
+	h.Type("s")
+	if sizes := column(h, table, 1); sizes != "9,10,100,n/a" {
+		t.Errorf("sizes sorted to %s with the keyboard", sizes)
+	}
+
+	// Invalid dates come last, i.e. first in descending order.
+	h.Do(func() {
+		table.SortBy(2, true)
+	})
+	if names := column(h, table, 0); names != "dave,carol,bob,alice" {
+		t.Errorf("rows sorted to %s by date", names)
+	}
+	if name := selectedText(h, table); name != "alice" {
+		t.Errorf("selection moved to %s, want it to stay on alice", name)
+	}
+}
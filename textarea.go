@@ -0,0 +1,935 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// textAreaEdit is a change of a text area's text which can be undone.
+type textAreaEdit struct {
+	pos      int    // The byte position of the change.
+	deleted  string // The text which was removed at this position.
+	inserted string // The text which was inserted at this position.
+	cursor   int    // The cursor position before the change.
+	typing   bool   // Whether or not characters typed next are added to this change.
+}
+
+// TextArea is a box (with an optional label) in which the user can enter and
+// edit multi-line text. The following keys are available:
+//
+//   - Left / right / up / down arrow: Move the cursor.
+//   - Ctrl-left / ctrl-right arrow: Move the cursor to the previous / next word.
+//   - Home, Ctrl-A / End, Ctrl-E: Move to the start / end of the line.
+//   - Ctrl-Home / Ctrl-End: Move to the start / end of the text.
+//   - Page up / page down: Move the cursor by one page.
+//   - Shift and any of the keys above: Select text.
+//   - Enter: Insert a new line.
+//   - Backspace / Delete: Delete the selection or the character before / after
+//     the cursor.
+//   - Ctrl-K / Ctrl-U: Delete to the end / start of the line.
+//   - Ctrl-W: Delete the word before the cursor.
+//   - Ctrl-Z / Ctrl-Y: Undo / redo the last change.
+//   - Ctrl-C / Ctrl-X: Copy / cut the selection to the clipboard.
+//   - Ctrl-V: Paste text from the clipboard.
+//   - Tab: Insert spaces (see SetTabSize()) or, if a done handler was set, move
+//     to the next field.
+//   - Backtab, Escape: Move to the previous field / abort text input.
+//
+// The text can also be selected by dragging the mouse across it. Text is
+// wrapped at word boundaries (see WordWrap()) unless wrapping is turned off
+// with SetWrap(), in which case long lines are scrolled horizontally.
+//
+// Unlike other primitives, a text area shows its text as it is, color tags are
+// not interpreted.
+type TextArea struct {
+	*Box
+
+	// The text that was entered.
+	text string
+
+	// The text to be displayed before the input area.
+	label string
+
+	// The label color.
+	labelColor tcell.Color
+
+	// The text attributes of the label.
+	labelAttributes tcell.AttrMask
+
+	// The background color of the input area.
+	fieldBackgroundColor tcell.Color
+
+	// The text color of the input area.
+	fieldTextColor tcell.Color
+
+	// The screen width and height of the input area. A value of 0 means extend
+	// as much as possible.
+	fieldWidth, fieldHeight int
+
+	// If set to true, lines are wrapped at word boundaries.
+	wrap bool
+
+	// The number of spaces inserted for a tab character. A value of 0 means
+	// TabSize.
+	tabSize int
+
+	// The cursor position (a byte position in the text).
+	cursor int
+
+	// The position at which the selection starts (the other end being the
+	// cursor). A negative value means that nothing is selected.
+	selectionStart int
+
+	// The screen column the cursor moves to when moving up or down, -1 to use
+	// the cursor's current column.
+	column int
+
+	// The index of the first line shown and the number of screen cells each
+	// line is scrolled to the left (only if lines are not wrapped).
+	lineOffset, columnOffset int
+
+	// A temporary flag which, when true, will bring the cursor into the
+	// visible area.
+	scrollToCursor bool
+
+	// The start and end byte positions of the displayed lines (nil if they
+	// need to be calculated) and the width for which they were calculated.
+	lines       [][2]int
+	layoutWidth int
+
+	// The screen position of the input area the last time it was drawn.
+	fieldX, fieldY int
+
+	// The cursor position at which the left mouse button was pressed.
+	dragStart int
+
+	// The changes which can be undone and those which can be redone.
+	undo, redo []textAreaEdit
+
+	// The clipboard used for copying and pasting (nil for DefaultClipboard).
+	clipboard Clipboard
+
+	// An optional function which is called when the text has changed.
+	changed func(text string)
+
+	// An optional function which is called when the user indicated that they
+	// are done entering text. The key which was pressed is provided (tab,
+	// shift-tab, or escape).
+	done func(tcell.Key)
+}
+
+// NewTextArea returns a new text area.
+func NewTextArea() *TextArea {
+	t := &TextArea{
+		Box:                  NewBox(),
+		labelColor:           Styles.SecondaryTextColor,
+		labelAttributes:      Styles.LabelAttributes,
+		fieldBackgroundColor: Styles.ContrastBackgroundColor,
+		fieldTextColor:       Styles.PrimaryTextColor,
+		wrap:                 true,
+		selectionStart:       -1,
+		column:               -1,
+	}
+	t.DeclareProps(PropSpec{
+		Name:    "text",
+		Type:    reflect.TypeOf(""),
+		Default: "",
+		Get:     func() interface{} { return t.GetText() },
+		Set:     func(value interface{}) { t.SetText(value.(string)) },
+	})
+	return t
+}
+
+func (t *TextArea) GetValues() map[string]interface{} {
+	return map[string]interface{}{
+		t.name: t.text,
+	}
+}
+
+// SetValue sets the text of the text area. The value must be a string.
+func (t *TextArea) SetValue(value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	t.SetText(text)
+	return nil
+}
+
+// SetText sets the text of the text area and moves the cursor to its end. Tab
+// characters are replaced with spaces (see SetTabSize()). The changes made so
+// far can no longer be undone. Subscribers of the "text" prop are notified if
+// the text changed.
+func (t *TextArea) SetText(text string) *TextArea {
+	t.Lock()
+	previous := t.text
+	text = t.expandTabs(text)
+	t.text = text
+	t.cursor = len(t.text)
+	t.selectionStart = -1
+	t.column = -1
+	t.lines = nil
+	t.undo, t.redo = nil, nil
+	t.scrollToCursor = true
+	t.Unlock()
+
+	if text != previous {
+		t.notifyProp("text", text)
+	}
+	if t.changed != nil {
+		t.changed(text)
+	}
+	return t
+}
+
+// GetText returns the text of the text area.
+func (t *TextArea) GetText() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.text
+}
+
+// GetSelectedText returns the selected text or an empty string if nothing is
+// selected.
+func (t *TextArea) GetSelectedText() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	from, to := t.selection()
+	return t.text[from:to]
+}
+
+// SetLabel sets the text to be displayed before the input area.
+func (t *TextArea) SetLabel(label string) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.label = label
+	return t
+}
+
+// GetLabel returns the text to be displayed before the input area.
+func (t *TextArea) GetLabel() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.label
+}
+
+// SetLabelColor sets the color of the label.
+func (t *TextArea) SetLabelColor(color tcell.Color) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.labelColor = color
+	return t
+}
+
+// SetLabelAttributes sets the text attributes of the label, e.g.
+// tcell.AttrBold.
+func (t *TextArea) SetLabelAttributes(attributes tcell.AttrMask) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.labelAttributes = attributes
+	return t
+}
+
+// SetFieldBackgroundColor sets the background color of the input area.
+func (t *TextArea) SetFieldBackgroundColor(color tcell.Color) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldBackgroundColor = color
+	return t
+}
+
+// SetFieldTextColor sets the text color of the input area.
+func (t *TextArea) SetFieldTextColor(color tcell.Color) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldTextColor = color
+	return t
+}
+
+// ApplyTheme sets the colors and attributes of the text area to those of the
+// given theme.
+func (t *TextArea) ApplyTheme(theme *Theme) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.Box.ApplyTheme(theme)
+	t.labelColor = theme.SecondaryTextColor
+	t.labelAttributes = theme.LabelAttributes
+	t.fieldBackgroundColor = theme.ContrastBackgroundColor
+	t.fieldTextColor = theme.PrimaryTextColor
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (t *TextArea) SetFormAttributes(label string, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	t.Lock()
+	defer t.Unlock()
+
+	t.label = label
+	t.labelColor = labelColor
+	t.backgroundColor = bgColor
+	t.fieldTextColor = fieldTextColor
+	t.fieldBackgroundColor = fieldBgColor
+	return t
+}
+
+// SetFieldWidth sets the screen width of the input area. A value of 0 means
+// extend as much as possible.
+func (t *TextArea) SetFieldWidth(width int) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldWidth = width
+	return t
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (t *TextArea) GetFieldWidth() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.fieldWidth
+}
+
+// SetFieldHeight sets the number of lines of the input area. A value of 0
+// means extend as much as possible.
+func (t *TextArea) SetFieldHeight(height int) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldHeight = height
+	return t
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (t *TextArea) GetFieldHeight() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.fieldHeight
+}
+
+// SetWrap sets the flag that, if true (the default), wraps lines which are
+// longer than the input area at word boundaries. If false, long lines are
+// scrolled horizontally.
+func (t *TextArea) SetWrap(wrap bool) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.wrap = wrap
+	t.lines = nil
+	return t
+}
+
+// SetTabSize sets the number of spaces which are inserted for tab characters,
+// both when the Tab key is pressed and when text containing tab characters is
+// set or pasted. A value of 0 (the default) uses the package's TabSize.
+func (t *TextArea) SetTabSize(size int) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.tabSize = size
+	return t
+}
+
+// SetClipboard sets the clipboard used for copying and pasting text. If it is
+// nil (the default), DefaultClipboard is used.
+func (t *TextArea) SetClipboard(clipboard Clipboard) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.clipboard = clipboard
+	return t
+}
+
+// SetChangedFunc sets a handler which is called whenever the text of the text
+// area has changed. It receives the current text (after the change).
+func (t *TextArea) SetChangedFunc(handler func(text string)) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.changed = handler
+	return t
+}
+
+// SetDoneFunc sets a handler which is called when the user is done entering
+// text. The callback function is provided with the key that was pressed, which
+// is one of the following:
+//
+//   - KeyEscape: Abort text input.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+//
+// As long as no such handler is set, the Tab key inserts spaces.
+func (t *TextArea) SetDoneFunc(handler func(key tcell.Key)) *TextArea {
+	t.Lock()
+	defer t.Unlock()
+
+	t.done = handler
+	return t
+}
+
+// SetFinishedFunc calls SetDoneFunc().
+func (t *TextArea) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	return t.SetDoneFunc(handler)
+}
+
+// SetFinishedFunction calls SetDoneFunc().
+func (t *TextArea) SetFinishedFunction(handler func(key tcell.Key)) {
+	t.SetDoneFunc(handler)
+}
+
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the text area with Tab or Backtab.
+func (t *TextArea) hasDoneFunc() bool {
+	return t.done != nil
+}
+
+// capturesTextInput returns true as the text area uses all printable
+// characters.
+func (t *TextArea) capturesTextInput() bool {
+	return true
+}
+
+// usesTab returns whether the given key (Tab or Backtab) is used by the text
+// area itself, i.e. to insert a tab character.
+func (t *TextArea) usesTab(key tcell.Key) bool {
+	return key == tcell.KeyTab && t.done == nil
+}
+
+// undoChange reverts the last change of the text. Characters typed in a row
+// are reverted together.
+func (t *TextArea) undoChange() {
+	if len(t.undo) == 0 {
+		return
+	}
+	edit := t.undo[len(t.undo)-1]
+	t.undo = t.undo[:len(t.undo)-1]
+	edit.typing = false
+	t.redo = append(t.redo, edit)
+
+	t.text = t.text[:edit.pos] + edit.deleted + t.text[edit.pos+len(edit.inserted):]
+	t.cursor = edit.cursor
+	t.textChanged()
+}
+
+// redoChange applies the last change which was reverted with undoChange()
+// again.
+func (t *TextArea) redoChange() {
+	if len(t.redo) == 0 {
+		return
+	}
+	edit := t.redo[len(t.redo)-1]
+	t.redo = t.redo[:len(t.redo)-1]
+	t.undo = append(t.undo, edit)
+
+	t.text = t.text[:edit.pos] + edit.inserted + t.text[edit.pos+len(edit.deleted):]
+	t.cursor = edit.pos + len(edit.inserted)
+	t.textChanged()
+}
+
+// expandTabs replaces tab characters in the given text with spaces and
+// Windows line endings with newline characters.
+func (t *TextArea) expandTabs(text string) string {
+	size := t.tabSize
+	if size <= 0 {
+		size = TabSize
+	}
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	return strings.Replace(text, "\t", strings.Repeat(" ", size), -1)
+}
+
+// textChanged resets the state which depends on the text after it was
+// changed.
+func (t *TextArea) textChanged() {
+	t.selectionStart = -1
+	t.column = -1
+	t.lines = nil
+	t.scrollToCursor = true
+}
+
+// replace replaces the text between the given byte positions with the given
+// text and moves the cursor to the end of the new text. The change can be
+// undone. If "typing" is true, the change is merged with the previous change
+// if that was made by typing, too, unless a new word is started.
+func (t *TextArea) replace(from, to int, text string, typing bool) {
+	if from == to && text == "" {
+		return
+	}
+	edit := textAreaEdit{
+		pos:      from,
+		deleted:  t.text[from:to],
+		inserted: text,
+		cursor:   t.cursor,
+		typing:   typing,
+	}
+	last := len(t.undo) - 1
+	if typing && from == to && last >= 0 && t.undo[last].typing &&
+		t.undo[last].pos+len(t.undo[last].inserted) == from &&
+		!(text == " " && !strings.HasSuffix(t.undo[last].inserted, " ")) {
+		t.undo[last].inserted += text
+	} else {
+		t.undo = append(t.undo, edit)
+	}
+	t.redo = nil
+
+	t.text = t.text[:from] + text + t.text[to:]
+	t.cursor = from + len(text)
+	t.textChanged()
+}
+
+// insert replaces the selection (or inserts at the cursor, if nothing is
+// selected) with the given text.
+func (t *TextArea) insert(text string, typing bool) {
+	from, to := t.selection()
+	t.replace(from, to, t.expandTabs(text), typing)
+}
+
+// selection returns the start and end byte positions of the selection. Both
+// are the cursor position if nothing is selected.
+func (t *TextArea) selection() (from, to int) {
+	if t.selectionStart < 0 || t.selectionStart > len(t.text) {
+		return t.cursor, t.cursor
+	}
+	if t.selectionStart < t.cursor {
+		return t.selectionStart, t.cursor
+	}
+	return t.cursor, t.selectionStart
+}
+
+// copy copies the selection to the clipboard.
+func (t *TextArea) copy() {
+	from, to := t.selection()
+	if from == to {
+		return
+	}
+	clipboard := t.clipboard
+	if clipboard == nil {
+		clipboard = DefaultClipboard
+	}
+	clipboard.Copy(t.text[from:to])
+}
+
+// paste inserts the text on the clipboard.
+func (t *TextArea) paste() {
+	clipboard := t.clipboard
+	if clipboard == nil {
+		clipboard = DefaultClipboard
+	}
+	if text, err := clipboard.Paste(); err == nil && text != "" {
+		t.insert(text, false)
+	}
+}
+
+// layout returns the start and end byte positions of the displayed lines.
+func (t *TextArea) layout() [][2]int {
+	if t.lines != nil {
+		return t.lines
+	}
+	if t.wrap && t.layoutWidth > 1 {
+		// Leave one cell for the cursor at the end of the line.
+		t.lines = wordWrapPositions(t.text, t.layoutWidth-1, false)
+		return t.lines
+	}
+	var start int
+	for pos := 0; pos < len(t.text); pos++ {
+		if t.text[pos] == '\n' {
+			t.lines = append(t.lines, [2]int{start, pos})
+			start = pos + 1
+		}
+	}
+	t.lines = append(t.lines, [2]int{start, len(t.text)})
+	return t.lines
+}
+
+// cursorPosition returns the index of the displayed line with the cursor and
+// the screen column of the cursor in that line.
+func (t *TextArea) cursorPosition() (line, column int) {
+	lines := t.layout()
+	line = sort.Search(len(lines), func(index int) bool {
+		return lines[index][0] > t.cursor
+	}) - 1
+	if line < 0 {
+		line = 0
+	}
+	column = runewidth.StringWidth(t.text[lines[line][0]:t.cursor])
+	return
+}
+
+// positionInLine returns the byte position of the character shown at the
+// given screen column of the displayed line with the given index. Columns
+// beyond the end of the line refer to the line's end.
+func (t *TextArea) positionInLine(index, column int) int {
+	lines := t.layout()
+	if index < 0 {
+		index = 0
+	} else if index >= len(lines) {
+		index = len(lines) - 1
+	}
+	line := lines[index]
+	var x int
+	for pos, ch := range t.text[line[0]:line[1]] {
+		chWidth := runewidth.RuneWidth(ch)
+		if x+chWidth > column {
+			return line[0] + pos
+		}
+		x += chWidth
+	}
+
+	// If a word continues on the next line, the line's end is the start of the
+	// next line. Stay on this line.
+	if index+1 < len(lines) && lines[index+1][0] == line[1] && line[1] > line[0] {
+		_, size := utf8.DecodeLastRuneInString(t.text[line[0]:line[1]])
+		return line[1] - size
+	}
+	return line[1]
+}
+
+// moveCursor calls the given function to move the cursor. If "shift" is true,
+// the selection is extended to the new cursor position. Otherwise, the
+// selection is removed.
+func (t *TextArea) moveCursor(shift bool, move func()) {
+	if !shift {
+		t.selectionStart = -1
+	} else if t.selectionStart < 0 {
+		t.selectionStart = t.cursor
+	}
+	move()
+	if last := len(t.undo) - 1; last >= 0 {
+		t.undo[last].typing = false // Typing somewhere else starts a new change.
+	}
+	t.scrollToCursor = true
+}
+
+// moveVertically moves the cursor by the given number of displayed lines,
+// keeping its screen column if possible.
+func (t *TextArea) moveVertically(lines int) {
+	line, column := t.cursorPosition()
+	if t.column >= 0 {
+		column = t.column
+	}
+	t.cursor = t.positionInLine(line+lines, column)
+	t.column = column
+}
+
+// wordStart returns the start of the word before the given byte position.
+func (t *TextArea) wordStart(pos int) int {
+	return strings.LastIndexFunc(strings.TrimRightFunc(t.text[:pos], unicode.IsSpace), unicode.IsSpace) + 1
+}
+
+// wordEnd returns the position after the whitespace following the word after
+// the given byte position.
+func (t *TextArea) wordEnd(pos int) int {
+	text := t.text[pos:]
+	index := strings.IndexFunc(text, unicode.IsSpace)
+	if index < 0 {
+		return len(t.text)
+	}
+	text = text[index:]
+	if index := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsSpace(r) }); index >= 0 {
+		return len(t.text) - len(text) + index
+	}
+	return len(t.text)
+}
+
+// positionAt returns the byte position of the character shown at the given
+// screen position. Positions outside the input area are moved into it.
+func (t *TextArea) positionAt(x, y int) int {
+	return t.positionInLine(y-t.fieldY+t.lineOffset, x-t.fieldX+t.columnOffset)
+}
+
+// Draw draws this primitive onto the screen.
+func (t *TextArea) Draw(screen tcell.Screen) {
+	t.Box.Draw(screen)
+
+	t.Lock()
+	defer t.Unlock()
+
+	// Prepare
+	x, y, width, height := t.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, drawnWidth := printWithAttributes(screen, t.label, x, y, rightLimit-x, AlignLeft, t.labelColor, t.labelAttributes)
+	x += drawnWidth
+
+	// Draw input area.
+	fieldWidth := t.fieldWidth
+	if fieldWidth == 0 {
+		fieldWidth = math.MaxInt32
+	}
+	if rightLimit-x < fieldWidth {
+		fieldWidth = rightLimit - x
+	}
+	fieldHeight := t.fieldHeight
+	if fieldHeight == 0 || fieldHeight > height {
+		fieldHeight = height
+	}
+	if fieldWidth < 1 {
+		return
+	}
+	t.fieldX, t.fieldY = x, y
+	fieldStyle := tcell.StyleDefault.Background(t.fieldBackgroundColor).Foreground(t.fieldTextColor)
+	for row := 0; row < fieldHeight; row++ {
+		for column := 0; column < fieldWidth; column++ {
+			screen.SetContent(x+column, y+row, ' ', nil, fieldStyle)
+		}
+	}
+
+	// Lay out the text.
+	if fieldWidth != t.layoutWidth {
+		t.layoutWidth = fieldWidth
+		t.lines = nil
+	}
+	lines := t.layout()
+
+	// Keep the cursor visible.
+	cursorLine, cursorColumn := t.cursorPosition()
+	if t.scrollToCursor {
+		if cursorLine < t.lineOffset {
+			t.lineOffset = cursorLine
+		} else if cursorLine >= t.lineOffset+fieldHeight {
+			t.lineOffset = cursorLine - fieldHeight + 1
+		}
+		if cursorColumn < t.columnOffset {
+			t.columnOffset = cursorColumn
+		} else if cursorColumn >= t.columnOffset+fieldWidth {
+			t.columnOffset = cursorColumn - fieldWidth + 1
+		}
+		t.scrollToCursor = false
+	}
+	if t.lineOffset > len(lines)-fieldHeight {
+		t.lineOffset = len(lines) - fieldHeight
+	}
+	if t.lineOffset < 0 {
+		t.lineOffset = 0
+	}
+	if t.wrap || t.columnOffset < 0 {
+		t.columnOffset = 0
+	}
+
+	// Draw the text.
+	from, to := t.selection()
+	selectedStyle := tcell.StyleDefault.Background(t.fieldTextColor).Foreground(t.fieldBackgroundColor)
+	for row := 0; row < fieldHeight && t.lineOffset+row < len(lines); row++ {
+		line := lines[t.lineOffset+row]
+		posX := -t.columnOffset
+		for pos, ch := range t.text[line[0]:line[1]] {
+			chWidth := runewidth.RuneWidth(ch)
+			if chWidth == 0 {
+				continue
+			}
+			if posX < 0 {
+				posX += chWidth
+				continue
+			}
+			if posX+chWidth > fieldWidth {
+				break
+			}
+			style := fieldStyle
+			if line[0]+pos >= from && line[0]+pos < to {
+				style = selectedStyle
+			}
+			for offset := 0; offset < chWidth; offset++ {
+				screen.SetContent(x+posX+offset, y+row, ch, nil, style)
+			}
+			posX += chWidth
+		}
+	}
+
+	// Set cursor.
+	if t.focus.HasFocus() && cursorLine >= t.lineOffset && cursorLine < t.lineOffset+fieldHeight {
+		column := cursorColumn - t.columnOffset
+		if column >= fieldWidth {
+			column = fieldWidth - 1
+		}
+		screen.ShowCursor(x+column, y+cursorLine-t.lineOffset)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *TextArea) InputHandler() func(tcell.Event, func(Primitive)) {
+	return t.wrapInputHandler(func(event tcell.Event, setFocus func(p Primitive)) {
+		switch evt := event.(type) {
+		case *tcell.EventKey:
+			// Trigger changed events.
+			currentText := t.text
+			defer func() {
+				if t.text != currentText {
+					t.notifyProp("text", t.text)
+				}
+				if t.text != currentText && t.changed != nil {
+					t.changed(t.text)
+				}
+			}()
+
+			// Process key event.
+			shift := evt.Modifiers()&tcell.ModShift != 0
+			word := evt.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) != 0
+			switch key := evt.Key(); key {
+			case tcell.KeyRune: // Regular character.
+				t.insert(string(evt.Rune()), true)
+			case tcell.KeyEnter: // New line.
+				t.insert("\n", false)
+			case tcell.KeyTab:
+				if t.done != nil {
+					t.done(key)
+				} else {
+					t.insert("\t", false)
+				}
+			case tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+				if t.done != nil {
+					t.done(key)
+				}
+			case tcell.KeyLeft:
+				t.moveCursor(shift, func() {
+					if word {
+						t.cursor = t.wordStart(t.cursor)
+					} else if t.cursor > 0 {
+						_, size := utf8.DecodeLastRuneInString(t.text[:t.cursor])
+						t.cursor -= size
+					}
+					t.column = -1
+				})
+			case tcell.KeyRight:
+				t.moveCursor(shift, func() {
+					if word {
+						t.cursor = t.wordEnd(t.cursor)
+					} else if t.cursor < len(t.text) {
+						_, size := utf8.DecodeRuneInString(t.text[t.cursor:])
+						t.cursor += size
+					}
+					t.column = -1
+				})
+			case tcell.KeyUp:
+				t.moveCursor(shift, func() { t.moveVertically(-1) })
+			case tcell.KeyDown:
+				t.moveCursor(shift, func() { t.moveVertically(1) })
+			case tcell.KeyPgUp:
+				t.moveCursor(shift, func() { t.moveVertically(-t.height) })
+			case tcell.KeyPgDn:
+				t.moveCursor(shift, func() { t.moveVertically(t.height) })
+			case tcell.KeyHome, tcell.KeyCtrlA:
+				t.moveCursor(shift, func() {
+					if word {
+						t.cursor = 0
+					} else {
+						line, _ := t.cursorPosition()
+						t.cursor = t.layout()[line][0]
+					}
+					t.column = -1
+				})
+			case tcell.KeyEnd, tcell.KeyCtrlE:
+				t.moveCursor(shift, func() {
+					if word {
+						t.cursor = len(t.text)
+					} else {
+						line, _ := t.cursorPosition()
+						t.cursor = t.positionInLine(line, math.MaxInt32)
+					}
+					t.column = -1
+				})
+			case tcell.KeyBackspace, tcell.KeyBackspace2: // Delete the selection or the previous character.
+				if from, to := t.selection(); from < to {
+					t.replace(from, to, "", false)
+				} else if t.cursor > 0 {
+					_, size := utf8.DecodeLastRuneInString(t.text[:t.cursor])
+					t.replace(t.cursor-size, t.cursor, "", false)
+				}
+			case tcell.KeyDelete: // Delete the selection or the next character.
+				if from, to := t.selection(); from < to {
+					t.replace(from, to, "", false)
+				} else if t.cursor < len(t.text) {
+					_, size := utf8.DecodeRuneInString(t.text[t.cursor:])
+					t.replace(t.cursor, t.cursor+size, "", false)
+				}
+			case tcell.KeyCtrlK: // Delete to the end of the line.
+				end := strings.IndexByte(t.text[t.cursor:], '\n')
+				if end < 0 {
+					end = len(t.text)
+				} else if end == 0 {
+					end = t.cursor + 1 // Join lines.
+				} else {
+					end += t.cursor
+				}
+				t.replace(t.cursor, end, "", false)
+			case tcell.KeyCtrlU: // Delete to the start of the line.
+				t.replace(strings.LastIndexByte(t.text[:t.cursor], '\n')+1, t.cursor, "", false)
+			case tcell.KeyCtrlW: // Delete the previous word.
+				t.replace(t.wordStart(t.cursor), t.cursor, "", false)
+			case tcell.KeyCtrlZ:
+				t.undoChange()
+			case tcell.KeyCtrlY:
+				t.redoChange()
+			case tcell.KeyCtrlC:
+				t.copy()
+			case tcell.KeyCtrlX:
+				t.copy()
+				if from, to := t.selection(); from < to {
+					t.replace(from, to, "", false)
+				}
+			case tcell.KeyCtrlV:
+				t.paste()
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *TextArea) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !t.InRect(x, y) && action != MouseLeftDrag && action != MouseLeftUp {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(t)
+			t.moveCursor(false, func() {
+				t.cursor = t.positionAt(x, y)
+				t.column = -1
+			})
+			t.dragStart = t.cursor
+			capture = t
+			consumed = true
+		case MouseLeftDrag:
+			t.moveCursor(true, func() {
+				t.selectionStart = t.dragStart
+				t.cursor = t.positionAt(x, y)
+				t.column = -1
+			})
+			capture = t
+			consumed = true
+		case MouseLeftUp:
+			consumed = true
+		case MouseScrollUp:
+			t.lineOffset--
+			consumed = true
+		case MouseScrollDown:
+			t.lineOffset++
+			consumed = true
+		}
+
+		return
+	})
+}
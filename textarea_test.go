@@ -0,0 +1,98 @@
+package tview_test
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+// areaText returns the text of the given text area.
+func areaText(h *tviewtest.Harness, area *tview.TextArea) (text string) {
+	h.Do(func() {
+		text = area.GetText()
+	})
+	return
+}
+
+func TestTextAreaUndo(t *testing.T) {
+	area := tview.NewTextArea()
+	h := tviewtest.New(area, 20, 4)
+	defer h.Stop()
+
+	// Words typed in a row are undone together.
+	h.Type("hello world")
+	for index, want := range []string{"hello", "", ""} {
+		h.KeyPress(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+		if text := areaText(h, area); text != want {
+			t.Fatalf("undo %d left %q, want %q", index+1, text, want)
+		}
+	}
+	for index, want := range []string{"hello", "hello world", "hello world"} {
+		h.KeyPress(tcell.KeyCtrlY, 0, tcell.ModCtrl)
+		if text := areaText(h, area); text != want {
+			t.Fatalf("redo %d left %q, want %q", index+1, text, want)
+		}
+	}
+
+	// Typing elsewhere starts a new change.
+	h.KeyPress(tcell.KeyLeft, 0, tcell.ModNone)
+	h.Type("X")
+	if text := areaText(h, area); text != "hello worlXd" {
+		t.Fatalf("text is %q after typing", text)
+	}
+	h.KeyPress(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	if text := areaText(h, area); text != "hello world" {
+		t.Fatalf("undo left %q, want %q", text, "hello world")
+	}
+
+	// A new change discards the changes which could be redone.
+	h.Type("!")
+	h.KeyPress(tcell.KeyCtrlY, 0, tcell.ModCtrl)
+	if text := areaText(h, area); text != "hello worl!d" {
+		t.Fatalf("redo after a new change left %q", text)
+	}
+
+	// Replacing the text discards the history.
+	h.Do(func() {
+		area.SetText("new")
+	})
+	h.KeyPress(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	if text := areaText(h, area); text != "new" {
+		t.Errorf("undo after SetText() left %q, want %q", text, "new")
+	}
+}
+
+func TestTextAreaClipboard(t *testing.T) {
+	clipboard := tview.NewMemoryClipboard()
+	area := tview.NewTextArea().SetClipboard(clipboard)
+	h := tviewtest.New(area, 20, 4)
+	defer h.Stop()
+
+	h.Type("hello world")
+	h.KeyPress(tcell.KeyHome, 0, tcell.ModCtrl)
+	h.KeyPress(tcell.KeyRight, 0, tcell.ModShift|tcell.ModCtrl)
+	var selected string
+	h.Do(func() {
+		selected = area.GetSelectedText()
+	})
+	if selected != "hello " {
+		t.Fatalf("selected text is %q, want %q", selected, "hello ")
+	}
+	h.KeyPress(tcell.KeyCtrlX, 0, tcell.ModCtrl)
+	h.KeyPress(tcell.KeyEnd, 0, tcell.ModCtrl)
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyCtrlV, 0, tcell.ModCtrl)
+	if text := areaText(h, area); text != "world\nhello " {
+		t.Errorf("text is %q after cut and paste", text)
+	}
+
+	// Cut and paste are undone like other changes.
+	h.KeyPress(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	h.KeyPress(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	h.KeyPress(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	if text := areaText(h, area); text != "hello world" {
+		t.Errorf("text is %q after undoing cut and paste", text)
+	}
+}
@@ -2,9 +2,9 @@ package tview
 
 import (
 	"bytes"
+	"fmt"
 	"regexp"
 	"strings"
-	"sync"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell"
@@ -14,15 +14,61 @@ import (
 // TabSize is the number of spaces with which a tab character will be replaced.
 var TabSize = 4
 
+// textViewState is the state of color and region tags at a position in the
+// text view's buffer.
+type textViewState struct {
+	ForegroundColor string // The foreground color ("" = default color).
+	BackgroundColor string // The background color ("" = default color).
+	Attributes      string // The style attributes ("" = no attributes).
+	Region          string // The region ID.
+}
+
 // textViewIndex contains information about each line displayed in the text
 // view.
 type textViewIndex struct {
-	Line    int         // The index into the "buffer" variable.
-	Pos     int         // The index into the "buffer" string (byte position).
-	NextPos int         // The (byte) index of the next character in this buffer line.
-	Width   int         // The screen width of this line.
-	Color   tcell.Color // The starting color.
-	Region  string      // The starting region ID.
+	textViewState // The state at the start of this line.
+
+	Line    int // The index into the "buffer" variable.
+	Pos     int // The index into the "buffer" string (byte position).
+	NextPos int // The (byte) index of the next character in this buffer line.
+	Width   int // The screen width of this line.
+}
+
+// textViewLine contains information about a line of the text view's buffer.
+// The lines displayed for it are calculated when it is first drawn.
+type textViewLine struct {
+	// The state at the start and at the end of the line.
+	start, end textViewState
+
+	// The lines displayed for this buffer line and the width and the wrapping
+	// generation for which they were calculated.
+	wrapped        []*textViewIndex
+	wrapWidth      int
+	wrapGeneration int
+
+	// The matches of the search pattern in this line and the search generation
+	// for which they were calculated.
+	matches          []textViewMatch
+	searchGeneration int
+}
+
+// textViewMatch is a match of the search pattern in a buffer line.
+type textViewMatch struct {
+	start, end    int // The byte positions of the match in the buffer line.
+	column, width int // The screen position and width of the match (without wrapping).
+}
+
+// textViewPosition is a position in the text view's buffer.
+type textViewPosition struct {
+	line, pos int // The buffer line and the byte position in it.
+}
+
+// textViewRow contains the buffer positions drawn in a screen row of the text
+// view.
+type textViewRow struct {
+	line      int   // The buffer line.
+	start     int   // The byte position at which the displayed line starts.
+	positions []int // The byte position drawn at each column, -1 for none.
 }
 
 // TextView is a box which displays text. It implements the io.Writer interface
@@ -47,6 +93,17 @@ type textViewIndex struct {
 // If the text is not scrollable, any text above the top visible line is
 // discarded.
 //
+// The text of scrollable text views can also be searched with "/" and "?" if
+// this was enabled with SetSearchable(). Search() does the same
+// programmatically.
+//
+// Text can be selected and copied to the clipboard with the mouse and the
+// keyboard if this was enabled with SetSelectable().
+//
+// Text views may receive large amounts of text, e.g. continuous log output. Use
+// SetMaxLines() to limit the number of lines kept in the buffer and
+// SetFollow() to keep the newest text in view unless the user scrolls up.
+//
 // Use SetInputCapture() to override or modify keyboard input.
 //
 // Colors
@@ -79,42 +136,54 @@ type textViewIndex struct {
 //
 // See https://github.com/rivo/tview/wiki/TextView for an example.
 type TextView struct {
-	sync.Mutex
 	*Box
 
 	// The text buffer.
 	buffer []string
 
+	// The maximum number of lines kept in the buffer. The oldest lines are
+	// removed when new lines are added. 0 means no limit.
+	maxLines int
+
 	// The last bytes that have been received but are not part of the buffer yet.
 	recentBytes []byte
 
-	// The processed line index. This is nil if the buffer has changed and needs
-	// to be re-indexed.
-	index []*textViewIndex
+	// Information about the lines of the buffer, added as lines are written.
+	// This may be shorter than the buffer, in which case the missing lines
+	// still need to be indexed.
+	lines []*textViewLine
+
+	// The state at the start of the first line of the buffer.
+	firstState textViewState
+
+	// Incremented when the wrapped lines of all buffer lines need to be
+	// calculated again.
+	wrapGeneration int
 
 	// The text alignment, one of AlignLeft, AlignCenter, or AlignRight.
 	align int
 
-	// Indices into the "index" slice which correspond to the first line of the
-	// first highlight and the last line of the last highlight. This is calculated
-	// during re-indexing. Set to -1 if there is no current highlight.
-	fromHighlight, toHighlight int
-
 	// A set of region IDs that are currently highlighted.
 	highlights map[string]struct{}
 
-	// The last width for which the current table is drawn.
-	lastWidth int
-
-	// The screen width of the longest line in the index (not the buffer).
+	// The screen width of the longest line currently shown.
 	longestLine int
 
-	// The index of the first line shown in the text view.
-	lineOffset int
+	// The buffer line and the index of its displayed line shown at the top of
+	// the text view.
+	lineOffset, subLineOffset int
+
+	// The number of displayed lines to scroll down (or up, if negative) the next
+	// time the text view is drawn.
+	scrollLines int
 
 	// If set to true, the text view will always remain at the end of the content.
 	trackEnd bool
 
+	// If set to true, the text view is kept at the end of the content (see
+	// SetFollow()).
+	follow bool
+
 	// The number of characters to be skipped on each line (not in wrap mode).
 	columnOffset int
 
@@ -155,6 +224,76 @@ type TextView struct {
 	// An optional function which is called when the user presses one of the
 	// following keys: Escape, Enter, Tab, Backtab.
 	done func(tcell.Key)
+
+	// The screen row at which the mouse was last seen while dragging the text.
+	dragY int
+
+	// If set to true, the user may search the text with "/" and "?".
+	searchable bool
+
+	// If set to true, search patterns are regular expressions. Otherwise, they
+	// are plain text.
+	searchRegexp bool
+
+	// The current search pattern and its compiled form. The latter is nil if
+	// there is no pattern or if the pattern is invalid.
+	searchPattern    string
+	searchExpression *regexp.Regexp
+
+	// Incremented when the search pattern changes.
+	searchGeneration int
+
+	// Whether or not the user is currently entering a search pattern.
+	searchPrompt bool
+
+	// Whether or not the search runs backwards, i.e. was started with "?".
+	searchBackwards bool
+
+	// The buffer line of the current match (-1 if there is none) and the index
+	// of the match in that line.
+	searchLine, searchMatch int
+
+	// A temporary flag which, when true, will bring the current match into the
+	// visible screen.
+	scrollToMatch bool
+
+	// The position of the text view when the user started entering the search
+	// pattern.
+	searchOriginLine, searchOriginSubLine, searchOriginColumn int
+	searchOriginTrackEnd                                      bool
+
+	// The background colors of matches and of the current match.
+	searchMatchColor, searchCurrentColor tcell.Color
+
+	// If set to true, the user may select text.
+	selectable bool
+
+	// Whether or not the user is currently selecting text with the keyboard.
+	selecting bool
+
+	// Whether or not entire lines are selected.
+	selectLines bool
+
+	// The position at which the selection started and its current end (the
+	// cursor), both inclusive. Nothing is selected if the anchor's line is
+	// negative.
+	selectAnchor, selectCursor textViewPosition
+
+	// The position at which the left mouse button was pressed. A selection
+	// starts there when the mouse is dragged.
+	dragStart textViewPosition
+
+	// A temporary flag which, when true, will bring the selection cursor into
+	// the visible screen.
+	scrollToCursor bool
+
+	// The buffer positions drawn in each screen row the last time the text view
+	// was drawn.
+	rows []textViewRow
+
+	// The clipboard to which selected text is copied (nil for
+	// DefaultClipboard).
+	clipboard Clipboard
 }
 
 // NewTextView returns a new text view.
@@ -162,12 +301,16 @@ func NewTextView() *TextView {
 	return &TextView{
 		Box:           NewBox(),
 		highlights:    make(map[string]struct{}),
-		lineOffset:    -1,
 		scrollable:    true,
 		align:         AlignLeft,
 		wrap:          true,
 		textColor:     Styles.PrimaryTextColor,
 		dynamicColors: false,
+		searchLine:    -1,
+		selectAnchor:  textViewPosition{line: -1},
+
+		searchMatchColor:   Styles.ContrastBackgroundColor,
+		searchCurrentColor: Styles.MoreContrastBackgroundColor,
 	}
 }
 
@@ -190,7 +333,7 @@ func (t *TextView) SetScrollable(scrollable bool) *TextView {
 // beyond the available width are not displayed.
 func (t *TextView) SetWrap(wrap bool) *TextView {
 	if t.wrap != wrap {
-		t.index = nil
+		t.wrapGeneration++
 	}
 	t.wrap = wrap
 	return t
@@ -203,7 +346,7 @@ func (t *TextView) SetWrap(wrap bool) *TextView {
 // This flag is ignored if the "wrap" flag is false.
 func (t *TextView) SetWordWrap(wrapOnWords bool) *TextView {
 	if t.wordWrap != wrapOnWords {
-		t.index = nil
+		t.wrapGeneration++
 	}
 	t.wordWrap = wrapOnWords
 	return t
@@ -212,9 +355,6 @@ func (t *TextView) SetWordWrap(wrapOnWords bool) *TextView {
 // SetTextAlign sets the text alignment within the text view. This must be
 // either AlignLeft, AlignCenter, or AlignRight.
 func (t *TextView) SetTextAlign(align int) *TextView {
-	if t.align != align {
-		t.index = nil
-	}
 	t.align = align
 	return t
 }
@@ -227,11 +367,20 @@ func (t *TextView) SetTextColor(color tcell.Color) *TextView {
 	return t
 }
 
+// ApplyTheme sets the text view's colors and attributes to those of the given
+// theme. Text which was already written keeps its colors.
+func (t *TextView) ApplyTheme(theme *Theme) {
+	t.Box.ApplyTheme(theme)
+	t.textColor = theme.PrimaryTextColor
+	t.searchMatchColor = theme.ContrastBackgroundColor
+	t.searchCurrentColor = theme.MoreContrastBackgroundColor
+}
+
 // SetDynamicColors sets the flag that allows the text color to be changed
 // dynamically. See class description for details.
 func (t *TextView) SetDynamicColors(dynamic bool) *TextView {
 	if t.dynamicColors != dynamic {
-		t.index = nil
+		t.lines = nil
 	}
 	t.dynamicColors = dynamic
 	return t
@@ -241,7 +390,7 @@ func (t *TextView) SetDynamicColors(dynamic bool) *TextView {
 // description for details.
 func (t *TextView) SetRegions(regions bool) *TextView {
 	if t.regions != regions {
-		t.index = nil
+		t.lines = nil
 	}
 	t.regions = regions
 	return t
@@ -263,6 +412,19 @@ func (t *TextView) SetDoneFunc(handler func(key tcell.Key)) *TextView {
 	return t
 }
 
+// hasDoneFunc returns whether a handler was set which is called when the user
+// leaves the text view with Tab or Backtab.
+func (t *TextView) hasDoneFunc() bool {
+	return t.done != nil
+}
+
+// capturesTextInput returns whether the text view currently uses printable
+// characters, i.e. while the search prompt is open or while selecting text
+// with the keyboard.
+func (t *TextView) capturesTextInput() bool {
+	return t.searchPrompt || t.selecting
+}
+
 // ScrollToBeginning scrolls to the top left corner of the text if the text view
 // is scrollable.
 func (t *TextView) ScrollToBeginning() *TextView {
@@ -270,7 +432,7 @@ func (t *TextView) ScrollToBeginning() *TextView {
 		return t
 	}
 	t.trackEnd = false
-	t.lineOffset = 0
+	t.lineOffset, t.subLineOffset, t.scrollLines = 0, 0, 0
 	t.columnOffset = 0
 	return t
 }
@@ -287,11 +449,50 @@ func (t *TextView) ScrollToEnd() *TextView {
 	return t
 }
 
+// SetMaxLines sets the maximum number of lines kept in the buffer. When more
+// lines are written, the oldest lines are removed. This keeps the memory used
+// by text views which receive a continuous stream of text (e.g. log output)
+// bounded. A value of 0 (the default) means no limit.
+func (t *TextView) SetMaxLines(maxLines int) *TextView {
+	t.Lock()
+	defer t.Unlock()
+
+	t.maxLines = maxLines
+	t.evictLines()
+	return t
+}
+
+// SetFollow sets the flag which, if true, keeps the text view scrolled to the
+// end of its content so that newly written text is always visible ("tail"
+// mode). When the user scrolls up, the text view stops following the content
+// until the user scrolls back to its end. Calling ScrollToBeginning() or
+// ScrollToEnd() has the same effect.
+func (t *TextView) SetFollow(follow bool) *TextView {
+	t.follow = follow
+	if t.scrollable {
+		t.trackEnd = follow
+	}
+	return t
+}
+
+// IsFollowing returns whether or not the text view is currently scrolled to
+// the end of its content and will scroll along with newly written text.
+func (t *TextView) IsFollowing() bool {
+	return t.trackEnd
+}
+
 // Clear removes all text from the buffer.
 func (t *TextView) Clear() *TextView {
 	t.buffer = nil
 	t.recentBytes = nil
-	t.index = nil
+	t.lines = nil
+	t.firstState = textViewState{}
+	t.lineOffset, t.subLineOffset, t.scrollLines = 0, 0, 0
+	t.searchLine = -1
+	t.ClearSelection()
+	if t.follow {
+		t.trackEnd = true
+	}
 	return t
 }
 
@@ -311,7 +512,6 @@ func (t *TextView) Highlight(regionIDs ...string) *TextView {
 		}
 		t.highlights[id] = struct{}{}
 	}
-	t.index = nil
 	return t
 }
 
@@ -335,7 +535,6 @@ func (t *TextView) ScrollToHighlight() *TextView {
 	if len(t.highlights) == 0 || !t.scrollable || !t.regions {
 		return t
 	}
-	t.index = nil
 	t.scrollToHighlights = true
 	t.trackEnd = false
 	return t
@@ -413,9 +612,704 @@ func (t *TextView) GetRegionText(regionID string) string {
 	return escapePattern.ReplaceAllString(buffer.String(), `[$1$2]`)
 }
 
+// SetSearchable sets the flag that, if true, lets the user search the text of
+// a scrollable text view:
+//
+//   - /: Enter a pattern and search forward.
+//   - ?: Enter a pattern and search backward.
+//   - n: Move to the next match (in the direction of the search).
+//   - N: Move to the previous match.
+//   - Escape: Clear the search.
+//
+// The text view is searched while the pattern is being entered. All matches
+// are highlighted and a line at the bottom of the text view shows the pattern
+// and the number of matches. Press Enter to confirm the pattern or Escape to
+// cancel the search.
+func (t *TextView) SetSearchable(searchable bool) *TextView {
+	t.searchable = searchable
+	if !searchable {
+		t.ClearSearch()
+	}
+	return t
+}
+
+// SetSearchRegexp sets the flag that, if true, causes search patterns to be
+// interpreted as regular expressions (see the regexp package). Otherwise, they
+// are searched as plain text.
+func (t *TextView) SetSearchRegexp(searchRegexp bool) *TextView {
+	t.searchRegexp = searchRegexp
+	if t.searchPattern != "" {
+		t.setSearchPattern(t.searchPattern)
+	}
+	return t
+}
+
+// SetSearchColors sets the background colors of the search matches and of
+// the current search match.
+func (t *TextView) SetSearchColors(match, current tcell.Color) *TextView {
+	t.searchMatchColor = match
+	t.searchCurrentColor = current
+	return t
+}
+
+// Search searches the text for the given pattern (a regular expression if
+// SetSearchRegexp() was set to true), highlights all matches, and scrolls to
+// the first match at or below the top of the text view. An empty pattern
+// clears the search. An error is returned if the pattern is not a valid
+// regular expression.
+//
+// Matches are found in the text as it is displayed, i.e. color and region tags
+// are ignored.
+func (t *TextView) Search(pattern string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searchBackwards = false
+	if err := t.setSearchPattern(pattern); err != nil {
+		return err
+	}
+	t.searchFrom(t.lineOffset, false)
+	return nil
+}
+
+// NextMatch scrolls to the next match of the current search, continuing at
+// the start of the text after the last match.
+func (t *TextView) NextMatch() *TextView {
+	t.Lock()
+	defer t.Unlock()
+
+	t.nextMatch(false)
+	return t
+}
+
+// PreviousMatch scrolls to the previous match of the current search,
+// continuing at the end of the text before the first match.
+func (t *TextView) PreviousMatch() *TextView {
+	t.Lock()
+	defer t.Unlock()
+
+	t.nextMatch(true)
+	return t
+}
+
+// ClearSearch removes the current search and its highlights.
+func (t *TextView) ClearSearch() *TextView {
+	t.searchPattern = ""
+	t.searchExpression = nil
+	t.searchGeneration++
+	t.searchPrompt = false
+	t.searchLine = -1
+	t.scrollToMatch = false
+	return t
+}
+
+// GetSearchMatches returns the number of the current search match (starting
+// at 1, 0 if there is no current match) and the total number of matches.
+func (t *TextView) GetSearchMatches() (current, total int) {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.countMatches()
+}
+
+// setSearchPattern sets and compiles the search pattern. The current match is
+// reset.
+func (t *TextView) setSearchPattern(pattern string) error {
+	t.searchPattern = pattern
+	t.searchExpression = nil
+	t.searchGeneration++
+	t.searchLine = -1
+	if pattern == "" {
+		return nil
+	}
+	if !t.searchRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	t.searchExpression = expression
+	return nil
+}
+
+// lineMatches returns the matches of the search pattern in the buffer line
+// with the given index. They are calculated on first use.
+func (t *TextView) lineMatches(index int) []textViewMatch {
+	line := t.lines[index]
+	if t.searchExpression == nil {
+		return nil
+	}
+	if line.searchGeneration == t.searchGeneration {
+		return line.matches
+	}
+
+	text, positions := t.plainText(index)
+
+	// Find the matches.
+	line.matches = nil
+	line.searchGeneration = t.searchGeneration
+	for _, location := range t.searchExpression.FindAllIndex(text, -1) {
+		if location[0] == location[1] {
+			continue // Ignore empty matches.
+		}
+		line.matches = append(line.matches, textViewMatch{
+			start:  positions[location[0]],
+			end:    positions[location[1]-1] + 1,
+			column: runewidth.StringWidth(string(text[:location[0]])),
+			width:  runewidth.StringWidth(string(text[location[0]:location[1]])),
+		})
+	}
+	return line.matches
+}
+
+// plainText returns the text of the buffer line with the given index as it is
+// displayed, i.e. without color and region tags, and the byte position in the
+// buffer line of each of its bytes.
+func (t *TextView) plainText(index int) (text []byte, positions []int) {
+	str := t.buffer[index]
+	var colorTagIndices, regionIndices, escapeIndices [][]int
+	if t.dynamicColors {
+		colorTagIndices = colorPattern.FindAllStringIndex(str, -1)
+	}
+	if t.regions {
+		regionIndices = regionPattern.FindAllStringIndex(str, -1)
+	}
+	if t.dynamicColors || t.regions {
+		escapeIndices = escapePattern.FindAllStringIndex(str, -1)
+	}
+	var currentTag, currentRegion, currentEscapeTag int
+	for pos, ch := range str {
+		// Skip any color tags.
+		if currentTag < len(colorTagIndices) && pos >= colorTagIndices[currentTag][0] && pos < colorTagIndices[currentTag][1] {
+			if pos == colorTagIndices[currentTag][1]-1 {
+				currentTag++
+			}
+			continue
+		}
+
+		// Skip any regions.
+		if currentRegion < len(regionIndices) && pos >= regionIndices[currentRegion][0] && pos < regionIndices[currentRegion][1] {
+			if pos == regionIndices[currentRegion][1]-1 {
+				currentRegion++
+			}
+			continue
+		}
+
+		// Skip the second-to-last character of an escape tag.
+		if currentEscapeTag < len(escapeIndices) && pos >= escapeIndices[currentEscapeTag][0] && pos < escapeIndices[currentEscapeTag][1] {
+			if pos == escapeIndices[currentEscapeTag][1]-1 {
+				currentEscapeTag++
+			} else if pos == escapeIndices[currentEscapeTag][1]-2 {
+				continue
+			}
+		}
+
+		// Add this rune.
+		size := utf8.RuneLen(ch)
+		if size < 0 {
+			size = 1 // Invalid UTF-8.
+		}
+		for offset := 0; offset < size; offset++ {
+			positions = append(positions, pos+offset)
+		}
+		text = append(text, str[pos:pos+size]...)
+	}
+
+	return
+}
+
+// searchFrom makes the first match at or after the start of the buffer line
+// with the given index the current match. If backwards is true, the last
+// match before the end of that line is chosen. The search continues at the
+// other end of the text. If there are no matches, there is no current match.
+func (t *TextView) searchFrom(index int, backwards bool) {
+	t.indexLines()
+	t.searchLine = -1
+	if t.searchExpression == nil || len(t.buffer) == 0 {
+		return
+	}
+	if index < 0 || index >= len(t.buffer) {
+		index = 0
+	}
+	for count := 0; count < len(t.buffer); count++ {
+		line := (index + count) % len(t.buffer)
+		if backwards {
+			line = (index - count + len(t.buffer)) % len(t.buffer)
+		}
+		if matches := t.lineMatches(line); len(matches) > 0 {
+			t.searchLine, t.searchMatch = line, 0
+			if backwards {
+				t.searchMatch = len(matches) - 1
+			}
+			t.scrollToMatch = true
+			t.trackEnd = false
+			return
+		}
+	}
+}
+
+// nextMatch moves to the next match after the current one, or to the previous
+// one if the search runs backwards. The direction is reversed if "reverse" is
+// true.
+func (t *TextView) nextMatch(reverse bool) {
+	backwards := t.searchBackwards != reverse
+	if t.searchLine < 0 || t.searchLine >= len(t.lines) {
+		t.searchFrom(t.lineOffset, backwards)
+		return
+	}
+
+	// Is there another match in the same line?
+	matches := t.lineMatches(t.searchLine)
+	if !backwards && t.searchMatch+1 < len(matches) {
+		t.searchMatch++
+		t.scrollToMatch = true
+		t.trackEnd = false
+		return
+	}
+	if backwards && t.searchMatch > 0 && t.searchMatch <= len(matches) {
+		t.searchMatch--
+		t.scrollToMatch = true
+		t.trackEnd = false
+		return
+	}
+
+	// No. Continue with the next line.
+	if backwards {
+		t.searchFrom((t.searchLine-1+len(t.buffer))%len(t.buffer), true)
+	} else {
+		t.searchFrom(t.searchLine+1, false)
+	}
+}
+
+// countMatches returns the number of the current match (starting at 1, 0 if
+// there is no current match) and the total number of matches.
+func (t *TextView) countMatches() (current, total int) {
+	if t.searchExpression == nil {
+		return
+	}
+	t.indexLines()
+	for index := range t.buffer {
+		if index == t.searchLine {
+			current = total + t.searchMatch + 1
+		}
+		total += len(t.lineMatches(index))
+	}
+	return
+}
+
+// scrollToPosition scrolls the text view such that the text at the given
+// buffer position is visible. If it is not visible yet, it is centered
+// vertically. The column and the width of the text are used to scroll
+// horizontally if lines are not wrapped.
+func (t *TextView) scrollToPosition(position textViewPosition, column, textWidth, width, height int) {
+	// Find the displayed line with the position.
+	wrapped := t.wrappedLines(position.line, width)
+	subLine := 0
+	for subLine+1 < len(wrapped) && wrapped[subLine+1].Pos <= position.pos {
+		subLine++
+	}
+	var visible bool
+	for _, line := range t.visibleLines(width, height) {
+		if line == wrapped[subLine] {
+			visible = true
+			break
+		}
+	}
+	if !visible {
+		t.lineOffset, t.subLineOffset = position.line, subLine
+		t.scroll(-(height-1)/2, width)
+	}
+
+	// Unwrapped lines may need to be scrolled horizontally.
+	if !t.wrap && t.align == AlignLeft {
+		if column < t.columnOffset || column+textWidth > t.columnOffset+width {
+			t.columnOffset = column - width/2
+		}
+	}
+}
+
+// startSearch opens the search prompt. The current position is remembered so
+// that it can be restored if the search is cancelled.
+func (t *TextView) startSearch(backwards bool) {
+	t.setSearchPattern("")
+	t.searchPrompt = true
+	t.searchBackwards = backwards
+	t.searchOriginLine, t.searchOriginSubLine, t.searchOriginColumn = t.lineOffset, t.subLineOffset, t.columnOffset
+	t.searchOriginTrackEnd = t.trackEnd
+}
+
+// searchInput processes a key event while the search prompt is open. The text
+// view is searched as the pattern changes.
+func (t *TextView) searchInput(event *tcell.EventKey) {
+	pattern := t.searchPattern
+	switch event.Key() {
+	case tcell.KeyRune:
+		pattern += string(event.Rune())
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if pattern == "" {
+			t.cancelSearch()
+			return
+		}
+		_, size := utf8.DecodeLastRuneInString(pattern)
+		pattern = pattern[:len(pattern)-size]
+	case tcell.KeyEnter:
+		t.searchPrompt = false
+		if pattern == "" {
+			t.ClearSearch()
+		}
+		return
+	case tcell.KeyEscape:
+		t.cancelSearch()
+		return
+	default:
+		return
+	}
+
+	// Search from where we started.
+	t.setSearchPattern(pattern)
+	t.lineOffset, t.subLineOffset, t.columnOffset = t.searchOriginLine, t.searchOriginSubLine, t.searchOriginColumn
+	t.trackEnd = t.searchOriginTrackEnd
+	t.searchFrom(t.searchOriginLine, t.searchBackwards)
+}
+
+// cancelSearch clears the search and returns to the position at which the
+// search prompt was opened.
+func (t *TextView) cancelSearch() {
+	t.ClearSearch()
+	t.lineOffset, t.subLineOffset, t.columnOffset = t.searchOriginLine, t.searchOriginSubLine, t.searchOriginColumn
+	t.trackEnd = t.searchOriginTrackEnd
+}
+
+// drawSearchBar draws the search pattern and the number of matches into the
+// screen line at the given position.
+func (t *TextView) drawSearchBar(screen tcell.Screen, x, y, width int) {
+	prompt := "/"
+	if t.searchBackwards {
+		prompt = "?"
+	}
+	var status string
+	if t.searchExpression == nil {
+		if t.searchPattern != "" {
+			status = "invalid pattern"
+		}
+	} else if current, total := t.countMatches(); total == 0 {
+		status = "no matches"
+	} else {
+		status = fmt.Sprintf("%d/%d", current, total)
+	}
+	_, statusWidth := Print(screen, status, x, y, width, AlignRight, t.textColor)
+	_, promptWidth := Print(screen, prompt+Escape(t.searchPattern), x, y, width-statusWidth-1, AlignLeft, t.textColor)
+	if t.searchPrompt && t.focus.HasFocus() {
+		screen.ShowCursor(x+promptWidth, y)
+	}
+}
+
+// SetSelectable sets the flag that, if true, lets the user select text with
+// the mouse and the keyboard and copy it to the clipboard (see
+// SetClipboard()). Dragging the mouse selects characters, a double click
+// selects a line. The selection is copied when the mouse button is released.
+// The following keys are available:
+//
+//   - v: Start (or end) selecting characters at the top of the text view.
+//   - V: Start (or end) selecting lines.
+//   - y, Enter: Copy the selection and end selecting.
+//   - Escape: End selecting without copying.
+//
+// While selecting with the keyboard, the navigation keys move the end of the
+// selection. The selection is shown in reverse video.
+func (t *TextView) SetSelectable(selectable bool) *TextView {
+	t.selectable = selectable
+	if !selectable {
+		t.ClearSelection()
+	}
+	return t
+}
+
+// SetClipboard sets the clipboard to which selected text is copied. If it is
+// nil (the default), DefaultClipboard is used.
+func (t *TextView) SetClipboard(clipboard Clipboard) *TextView {
+	t.clipboard = clipboard
+	return t
+}
+
+// GetSelectedText returns the selected text. Color and region tags are
+// stripped from the text. Lines are separated by '\n' runes. An empty string
+// is returned if nothing is selected.
+func (t *TextView) GetSelectedText() string {
+	from, to, ok := t.selection()
+	if !ok {
+		return ""
+	}
+
+	var buffer bytes.Buffer
+	for line := from.line; line <= to.line; line++ {
+		if line > from.line {
+			buffer.WriteByte('\n')
+		}
+		text, positions := t.plainText(line)
+		for index, pos := range positions {
+			if t.isSelected(line, pos, from, to) {
+				buffer.WriteByte(text[index])
+			}
+		}
+	}
+	return buffer.String()
+}
+
+// Copy copies the selected text (see GetSelectedText()) to the clipboard.
+// Nothing is copied if nothing is selected.
+func (t *TextView) Copy() error {
+	text := t.GetSelectedText()
+	if text == "" {
+		return nil
+	}
+	clipboard := t.clipboard
+	if clipboard == nil {
+		clipboard = DefaultClipboard
+	}
+	return clipboard.Copy(text)
+}
+
+// ClearSelection removes the selection.
+func (t *TextView) ClearSelection() *TextView {
+	t.selecting = false
+	t.selectAnchor.line = -1
+	return t
+}
+
+// selection returns the start (inclusive) and the end (exclusive) of the
+// selection, in this order. "ok" is false if nothing is selected.
+func (t *TextView) selection() (from, to textViewPosition, ok bool) {
+	from, to = t.selectAnchor, t.selectCursor
+	if from.line < 0 || from.line >= len(t.buffer) || to.line < 0 || to.line >= len(t.buffer) {
+		return
+	}
+	if to.line < from.line || to.line == from.line && to.pos < from.pos {
+		from, to = to, from
+	}
+	if t.selectLines {
+		from.pos = 0
+		to.pos = len(t.buffer[to.line])
+	} else if to.pos < len(t.buffer[to.line]) {
+		_, size := utf8.DecodeRuneInString(t.buffer[to.line][to.pos:])
+		to.pos += size
+	}
+	return from, to, true
+}
+
+// isSelected returns whether the character at the given byte position of the
+// given buffer line lies within the selection described by "from" and "to"
+// (see selection()).
+func (t *TextView) isSelected(line, pos int, from, to textViewPosition) bool {
+	if line < from.line || line > to.line {
+		return false
+	}
+	return (line > from.line || pos >= from.pos) && (line < to.line || pos < to.pos)
+}
+
+// startSelection starts selecting text with the keyboard at the first
+// character shown at the top of the text view.
+func (t *TextView) startSelection(lines bool) {
+	t.selecting = true
+	t.selectLines = lines
+	t.selectCursor = textViewPosition{line: t.lineOffset}
+	if len(t.rows) > 0 {
+		t.selectCursor = t.rows[0].position(0)
+	}
+	t.selectAnchor = t.selectCursor
+}
+
+// runePositions returns the byte positions of the displayed runes of the
+// buffer line with the given index.
+func (t *TextView) runePositions(line int) []int {
+	text, positions := t.plainText(line)
+	var runes []int
+	for index, pos := range positions {
+		if utf8.RuneStart(text[index]) {
+			runes = append(runes, pos)
+		}
+	}
+	return runes
+}
+
+// moveCursor moves the end of the keyboard selection by the given number of
+// lines and runes. The cursor keeps its rune index when moving between lines.
+// The rune index -1 refers to the last rune of the line.
+func (t *TextView) moveCursor(lines, runes int) {
+	if len(t.buffer) == 0 {
+		return
+	}
+
+	// Where are we now?
+	positions := t.runePositions(t.selectCursor.line)
+	index := 0
+	for index < len(positions) && positions[index] < t.selectCursor.pos {
+		index++
+	}
+
+	// Move.
+	line := t.selectCursor.line + lines
+	if line < 0 {
+		line = 0
+	} else if line >= len(t.buffer) {
+		line = len(t.buffer) - 1
+	}
+	if line != t.selectCursor.line {
+		positions = t.runePositions(line)
+	}
+	if runes == -1 {
+		index = len(positions) - 1
+	} else {
+		index += runes
+	}
+	if index >= len(positions) {
+		index = len(positions) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	t.selectCursor = textViewPosition{line: line}
+	if index < len(positions) {
+		t.selectCursor.pos = positions[index]
+	}
+	t.scrollToCursor = true
+	t.trackEnd = false
+}
+
+// selectionInput processes a key event while the user selects text with the
+// keyboard.
+func (t *TextView) selectionInput(event *tcell.EventKey) {
+	key := event.Key()
+	if key == tcell.KeyRune {
+		switch event.Rune() {
+		case 'h':
+			key = tcell.KeyLeft
+		case 'l':
+			key = tcell.KeyRight
+		case 'j':
+			key = tcell.KeyDown
+		case 'k':
+			key = tcell.KeyUp
+		case '0':
+			key = tcell.KeyHome
+		case '$':
+			key = tcell.KeyEnd
+		case 'g':
+			t.moveCursor(-len(t.buffer), 0)
+			return
+		case 'G':
+			t.moveCursor(len(t.buffer), 0)
+			return
+		case 'y':
+			key = tcell.KeyEnter
+		case 'v', 'V':
+			if lines := event.Rune() == 'V'; lines != t.selectLines {
+				t.selectLines = lines
+			} else {
+				t.ClearSelection()
+			}
+			return
+		}
+	}
+
+	switch key {
+	case tcell.KeyLeft:
+		t.moveCursor(0, -1)
+	case tcell.KeyRight:
+		t.moveCursor(0, 1)
+	case tcell.KeyUp:
+		t.moveCursor(-1, 0)
+	case tcell.KeyDown:
+		t.moveCursor(1, 0)
+	case tcell.KeyHome:
+		t.moveCursor(0, -len(t.buffer[t.selectCursor.line]))
+	case tcell.KeyEnd:
+		t.moveCursor(0, -1)
+	case tcell.KeyPgUp, tcell.KeyCtrlB:
+		t.moveCursor(-t.pageSize, 0)
+	case tcell.KeyPgDn, tcell.KeyCtrlF:
+		t.moveCursor(t.pageSize, 0)
+	case tcell.KeyEnter:
+		t.Copy()
+		t.ClearSelection()
+	case tcell.KeyEscape:
+		t.ClearSelection()
+	}
+}
+
+// positionAt returns the buffer position of the character which was drawn at
+// the given screen position, or of the character closest to it. Screen
+// positions outside the text view are moved into it. "ok" is false if no text
+// was drawn.
+func (t *TextView) positionAt(x, y int) (position textViewPosition, ok bool) {
+	if len(t.rows) == 0 {
+		return
+	}
+	rectX, rectY, _, _ := t.GetInnerRect()
+	row := y - rectY
+	if row < 0 {
+		row = 0
+	} else if row >= len(t.rows) {
+		row = len(t.rows) - 1
+	}
+	return t.rows[row].position(x - rectX), true
+}
+
+// position returns the buffer position of the character drawn at the given
+// column of this screen row or, if there is none, of the closest character to
+// the left or, failing that, to the right.
+func (r textViewRow) position(column int) textViewPosition {
+	if column >= len(r.positions) {
+		column = len(r.positions) - 1
+	}
+	for c := column; c >= 0; c-- {
+		if r.positions[c] >= 0 {
+			return textViewPosition{line: r.line, pos: r.positions[c]}
+		}
+	}
+	for c := column + 1; c < len(r.positions); c++ {
+		if c >= 0 && r.positions[c] >= 0 {
+			return textViewPosition{line: r.line, pos: r.positions[c]}
+		}
+	}
+	return textViewPosition{line: r.line, pos: r.start}
+}
+
+// shiftLines updates the line numbers of the current search match and the
+// selection after the given number of lines were removed from the start of
+// the buffer.
+func (t *TextView) shiftLines(removed int) {
+	if t.searchLine >= 0 {
+		t.searchLine -= removed
+		if t.searchLine < 0 {
+			t.searchLine = -1
+		}
+	}
+	if t.selectAnchor.line >= 0 {
+		t.selectAnchor.line -= removed
+		t.selectCursor.line -= removed
+		if t.selectAnchor.line < 0 || t.selectCursor.line < 0 {
+			t.ClearSelection()
+		}
+	}
+}
+
+// Patterns used when text is written to a text view.
+var (
+	openColorPattern  = regexp.MustCompile(`\[([a-zA-Z0-9#:\-]*)$`)
+	openRegionPattern = regexp.MustCompile(`\["[a-zA-Z0-9_,;: \-\.]*"?$`)
+	newLinePattern    = regexp.MustCompile(`\r?\n`)
+)
+
 // Write lets us implement the io.Writer interface. Tab characters will be
 // replaced with TabSize space characters. A "\n" or "\r\n" will be interpreted
 // as a new line.
+//
+// Only the newly written lines are processed. Lines are wrapped when they are
+// drawn for the first time so writing large amounts of text is cheap. If a
+// maximum number of lines was set with SetMaxLines(), the oldest lines are
+// removed as needed.
 func (t *TextView) Write(p []byte) (n int, err error) {
 	// Notify at the end.
 	if t.changed != nil {
@@ -437,8 +1331,7 @@ func (t *TextView) Write(p []byte) (n int, err error) {
 
 	// If we have a trailing open dynamic color, exclude it.
 	if t.dynamicColors {
-		openColor := regexp.MustCompile(`\[([a-zA-Z]*|#[0-9a-zA-Z]*)$`)
-		location := openColor.FindIndex(newBytes)
+		location := openColorPattern.FindIndex(newBytes)
 		if location != nil {
 			t.recentBytes = newBytes[location[0]:]
 			newBytes = newBytes[:location[0]]
@@ -447,18 +1340,21 @@ func (t *TextView) Write(p []byte) (n int, err error) {
 
 	// If we have a trailing open region, exclude it.
 	if t.regions {
-		openRegion := regexp.MustCompile(`\["[a-zA-Z0-9_,;: \-\.]*"?$`)
-		location := openRegion.FindIndex(newBytes)
+		location := openRegionPattern.FindIndex(newBytes)
 		if location != nil {
 			t.recentBytes = newBytes[location[0]:]
 			newBytes = newBytes[:location[0]]
 		}
 	}
 
+	// The last line will change. It needs to be indexed again.
+	if last := len(t.buffer) - 1; last >= 0 && last < len(t.lines) {
+		t.lines = t.lines[:last]
+	}
+
 	// Transform the new bytes into strings.
-	newLine := regexp.MustCompile(`\r?\n`)
 	newBytes = bytes.Replace(newBytes, []byte{'\t'}, bytes.Repeat([]byte{' '}, TabSize), -1)
-	for index, line := range newLine.Split(string(newBytes), -1) {
+	for index, line := range newLinePattern.Split(string(newBytes), -1) {
 		if index == 0 {
 			if len(t.buffer) == 0 {
 				t.buffer = []string{line}
@@ -470,213 +1366,372 @@ func (t *TextView) Write(p []byte) (n int, err error) {
 		}
 	}
 
-	// Reset the index.
-	t.index = nil
+	// Index the new lines and remove old ones.
+	t.indexLines()
+	t.evictLines()
 
 	return len(p), nil
 }
 
-// reindexBuffer re-indexes the buffer such that we can use it to easily draw
-// the buffer onto the screen. Each line in the index will contain a pointer
-// into the buffer from which on we will print text. It will also contain the
-// color with which the line starts.
-func (t *TextView) reindexBuffer(width int) {
-	if t.index != nil {
-		return // Nothing has changed. We can still use the current index.
+// indexLines adds information about the buffer lines which have not been
+// indexed yet, i.e. the color and region state at their start and end.
+func (t *TextView) indexLines() {
+	state := t.firstState
+	if !t.dynamicColors {
+		state.ForegroundColor, state.BackgroundColor, state.Attributes = "", "", ""
+	}
+	if !t.regions {
+		state.Region = ""
+	}
+	if len(t.lines) > 0 {
+		state = t.lines[len(t.lines)-1].end
+	}
+
+	for index := len(t.lines); index < len(t.buffer); index++ {
+		line := &textViewLine{start: state}
+		str := t.buffer[index]
+		if t.dynamicColors {
+			for _, tag := range colorPattern.FindAllStringSubmatch(str, -1) {
+				state.ForegroundColor, state.BackgroundColor, state.Attributes = styleFromTag(state.ForegroundColor, state.BackgroundColor, state.Attributes, tag)
+			}
+		}
+		if t.regions {
+			if regions := regionPattern.FindAllStringSubmatch(str, -1); len(regions) > 0 {
+				state.Region = regions[len(regions)-1][1]
+			}
+		}
+		line.end = state
+		t.lines = append(t.lines, line)
 	}
-	t.index = nil
-	t.fromHighlight, t.toHighlight = -1, -1
+}
 
-	// If there's no space, there's no index.
-	if width < 1 {
+// evictLines removes the oldest lines from the buffer if it contains more
+// lines than allowed by SetMaxLines().
+func (t *TextView) evictLines() {
+	if t.maxLines <= 0 || len(t.buffer) <= t.maxLines {
 		return
 	}
+	t.indexLines()
+	evict := len(t.buffer) - t.maxLines
+	t.firstState = t.lines[evict].start
+
+	// Release the removed lines' memory.
+	for index := 0; index < evict; index++ {
+		t.buffer[index] = ""
+		t.lines[index] = nil
+	}
+	t.buffer = t.buffer[evict:]
+	t.lines = t.lines[evict:]
 
-	// Initial states.
-	regionID := ""
-	var highlighted bool
-	color := t.textColor
+	// Keep showing the same text.
+	t.lineOffset -= evict
+	if t.lineOffset < 0 {
+		t.lineOffset, t.subLineOffset = 0, 0
+	}
+	t.shiftLines(evict)
+}
 
-	// Go through each line in the buffer.
-	for bufferIndex, str := range t.buffer {
-		// Find all color tags in this line. Then remove them.
-		var (
-			colorTagIndices [][]int
-			colorTags       [][]string
-		)
-		if t.dynamicColors {
-			colorTagIndices = colorPattern.FindAllStringIndex(str, -1)
-			colorTags = colorPattern.FindAllStringSubmatch(str, -1)
-			str = colorPattern.ReplaceAllString(str, "")
+// wrappedLines returns the lines displayed for the buffer line with the given
+// index. They are calculated when the line is drawn for the first time or
+// when the width or the wrapping settings have changed. Each displayed line
+// contains a pointer into the buffer from which on we will print text. It
+// will also contain the colors and the region with which the line starts.
+func (t *TextView) wrappedLines(index, width int) []*textViewIndex {
+	line := t.lines[index]
+	if line.wrapped != nil && line.wrapWidth == width && line.wrapGeneration == t.wrapGeneration {
+		for _, wrapped := range line.wrapped {
+			wrapped.Line = index // Lines may have been removed in the meantime.
 		}
+		return line.wrapped
+	}
 
-		// Find all regions in this line. Then remove them.
-		var (
-			regionIndices [][]int
-			regions       [][]string
-		)
-		if t.regions {
-			regionIndices = regionPattern.FindAllStringIndex(str, -1)
-			regions = regionPattern.FindAllStringSubmatch(str, -1)
-			str = regionPattern.ReplaceAllString(str, "")
-		}
+	str := t.buffer[index]
+	state := line.start
 
-		// Find all replace tags in this line. Then replace them.
-		var escapeIndices [][]int
-		if t.dynamicColors || t.regions {
-			escapeIndices = escapePattern.FindAllStringIndex(str, -1)
-			str = escapePattern.ReplaceAllString(str, "[$1$2]")
-		}
-
-		// Split the line if required.
-		var splitLines []string
-		if t.wrap && len(str) > 0 {
-			for len(str) > 0 {
-				extract := runewidth.Truncate(str, width, "")
-				if t.wordWrap && len(extract) < len(str) {
-					// Add any spaces from the next line.
-					if spaces := spacePattern.FindStringIndex(str[len(extract):]); spaces != nil && spaces[0] == 0 {
-						extract = str[:len(extract)+spaces[1]]
-					}
+	// Find all color tags in this line. Then remove them.
+	var (
+		colorTagIndices [][]int
+		colorTags       [][]string
+	)
+	if t.dynamicColors {
+		colorTagIndices = colorPattern.FindAllStringIndex(str, -1)
+		colorTags = colorPattern.FindAllStringSubmatch(str, -1)
+		str = colorPattern.ReplaceAllString(str, "")
+	}
 
-					// Can we split before the mandatory end?
-					matches := boundaryPattern.FindAllStringIndex(extract, -1)
-					if len(matches) > 0 {
-						// Yes. Let's split there.
-						extract = extract[:matches[len(matches)-1][1]]
-					}
+	// Find all regions in this line. Then remove them.
+	var (
+		regionIndices [][]int
+		regions       [][]string
+	)
+	if t.regions {
+		regionIndices = regionPattern.FindAllStringIndex(str, -1)
+		regions = regionPattern.FindAllStringSubmatch(str, -1)
+		str = regionPattern.ReplaceAllString(str, "")
+	}
+
+	// Find all replace tags in this line. Then replace them.
+	var escapeIndices [][]int
+	if t.dynamicColors || t.regions {
+		escapeIndices = escapePattern.FindAllStringIndex(str, -1)
+		str = escapePattern.ReplaceAllString(str, "[$1$2]")
+	}
+
+	// Split the line if required.
+	var splitLines []string
+	if t.wrap && len(str) > 0 {
+		for len(str) > 0 {
+			extract := runewidth.Truncate(str, width, "")
+			if t.wordWrap && len(extract) < len(str) {
+				// Add any spaces from the next line.
+				if spaces := spacePattern.FindStringIndex(str[len(extract):]); spaces != nil && spaces[0] == 0 {
+					extract = str[:len(extract)+spaces[1]]
 				}
-				splitLines = append(splitLines, extract)
-				str = str[len(extract):]
-			}
-		} else {
-			// No need to split the line.
-			splitLines = []string{str}
-		}
-
-		// Create index from split lines.
-		var originalPos, colorPos, regionPos, escapePos int
-		for _, splitLine := range splitLines {
-			line := &textViewIndex{
-				Line:   bufferIndex,
-				Pos:    originalPos,
-				Color:  color,
-				Region: regionID,
-			}
-
-			// Shift original position with tags.
-			lineLength := len(splitLine)
-			for {
-				if colorPos < len(colorTagIndices) && colorTagIndices[colorPos][0] <= originalPos+lineLength {
-					// Process color tags.
-					originalPos += colorTagIndices[colorPos][1] - colorTagIndices[colorPos][0]
-					color = tcell.GetColor(colorTags[colorPos][1])
-					colorPos++
-				} else if regionPos < len(regionIndices) && regionIndices[regionPos][0] <= originalPos+lineLength {
-					// Process region tags.
-					originalPos += regionIndices[regionPos][1] - regionIndices[regionPos][0]
-					regionID = regions[regionPos][1]
-					_, highlighted = t.highlights[regionID]
-
-					// Update highlight range.
-					if highlighted {
-						line := len(t.index)
-						if t.fromHighlight < 0 {
-							t.fromHighlight, t.toHighlight = line, line
-						} else if line > t.toHighlight {
-							t.toHighlight = line
-						}
-					}
 
-					regionPos++
-				} else if escapePos < len(escapeIndices) && escapeIndices[escapePos][0] <= originalPos+lineLength {
-					// Process escape tags.
-					originalPos++
-					escapePos++
-				} else {
-					break
+				// Can we split before the mandatory end?
+				matches := boundaryPattern.FindAllStringIndex(extract, -1)
+				if len(matches) > 0 {
+					// Yes. Let's split there.
+					extract = extract[:matches[len(matches)-1][1]]
 				}
 			}
+			if len(extract) == 0 {
+				// Not even one character fits. Show it anyway.
+				_, size := utf8.DecodeRuneInString(str)
+				extract = str[:size]
+			}
+			splitLines = append(splitLines, extract)
+			str = str[len(extract):]
+		}
+	} else {
+		// No need to split the line.
+		splitLines = []string{str}
+	}
 
-			// Advance to next line.
-			originalPos += lineLength
+	// Create index from split lines.
+	var wrapped []*textViewIndex
+	var originalPos, colorPos, regionPos, escapePos int
+	for _, splitLine := range splitLines {
+		subLine := &textViewIndex{
+			textViewState: state,
+			Line:          index,
+			Pos:           originalPos,
+		}
 
-			// Append this line.
-			line.NextPos = originalPos
-			line.Width = runewidth.StringWidth(splitLine)
-			t.index = append(t.index, line)
+		// Shift original position with tags.
+		lineLength := len(splitLine)
+		for {
+			if colorPos < len(colorTagIndices) && colorTagIndices[colorPos][0] <= originalPos+lineLength {
+				// Process color tags.
+				originalPos += colorTagIndices[colorPos][1] - colorTagIndices[colorPos][0]
+				state.ForegroundColor, state.BackgroundColor, state.Attributes = styleFromTag(state.ForegroundColor, state.BackgroundColor, state.Attributes, colorTags[colorPos])
+				colorPos++
+			} else if regionPos < len(regionIndices) && regionIndices[regionPos][0] <= originalPos+lineLength {
+				// Process region tags.
+				originalPos += regionIndices[regionPos][1] - regionIndices[regionPos][0]
+				state.Region = regions[regionPos][1]
+				regionPos++
+			} else if escapePos < len(escapeIndices) && escapeIndices[escapePos][0] <= originalPos+lineLength {
+				// Process escape tags.
+				originalPos++
+				escapePos++
+			} else {
+				break
+			}
 		}
 
-		// Word-wrapped lines may have trailing whitespace. Remove it.
-		if t.wrap && t.wordWrap {
-			for _, line := range t.index {
-				str := t.buffer[line.Line][line.Pos:line.NextPos]
-				spaces := spacePattern.FindAllStringIndex(str, -1)
-				if spaces != nil && spaces[len(spaces)-1][1] == len(str) {
-					oldNextPos := line.NextPos
-					line.NextPos -= spaces[len(spaces)-1][1] - spaces[len(spaces)-1][0]
-					line.Width -= runewidth.StringWidth(t.buffer[line.Line][line.NextPos:oldNextPos])
-				}
+		// Advance to next line.
+		originalPos += lineLength
+
+		// Append this line.
+		subLine.NextPos = originalPos
+		subLine.Width = runewidth.StringWidth(splitLine)
+		wrapped = append(wrapped, subLine)
+	}
+
+	// Word-wrapped lines may have trailing whitespace. Remove it.
+	if t.wrap && t.wordWrap {
+		for _, subLine := range wrapped {
+			str := t.buffer[index][subLine.Pos:subLine.NextPos]
+			spaces := spacePattern.FindAllStringIndex(str, -1)
+			if spaces != nil && spaces[len(spaces)-1][1] == len(str) {
+				oldNextPos := subLine.NextPos
+				subLine.NextPos -= spaces[len(spaces)-1][1] - spaces[len(spaces)-1][0]
+				subLine.Width -= runewidth.StringWidth(t.buffer[index][subLine.NextPos:oldNextPos])
 			}
 		}
 	}
 
-	// Calculate longest line.
-	t.longestLine = 0
-	for _, line := range t.index {
-		if line.Width > t.longestLine {
-			t.longestLine = line.Width
+	line.wrapped, line.wrapWidth, line.wrapGeneration = wrapped, width, t.wrapGeneration
+	return wrapped
+}
+
+// scroll moves the top of the text view down by the given number of displayed
+// lines (up, if negative), but not beyond the first or the last line.
+func (t *TextView) scroll(lines, width int) {
+	for ; lines > 0; lines-- {
+		if t.subLineOffset+1 < len(t.wrappedLines(t.lineOffset, width)) {
+			t.subLineOffset++
+		} else if t.lineOffset+1 < len(t.buffer) {
+			t.lineOffset++
+			t.subLineOffset = 0
+		} else {
+			break
+		}
+	}
+	for ; lines < 0; lines++ {
+		if t.subLineOffset > 0 {
+			t.subLineOffset--
+		} else if t.lineOffset > 0 {
+			t.lineOffset--
+			t.subLineOffset = len(t.wrappedLines(t.lineOffset, width)) - 1
+		} else {
+			break
 		}
 	}
 }
 
+// visibleLines returns the displayed lines starting at the top of the text
+// view, at most the given number of them.
+func (t *TextView) visibleLines(width, height int) []*textViewIndex {
+	var visible []*textViewIndex
+	subLine := t.subLineOffset
+	for index := t.lineOffset; index < len(t.buffer) && len(visible) < height; index++ {
+		for _, line := range t.wrappedLines(index, width)[subLine:] {
+			if len(visible) >= height {
+				break
+			}
+			visible = append(visible, line)
+		}
+		subLine = 0
+	}
+	return visible
+}
+
+// scrollToHighlight moves the top of the text view to the first line with
+// highlighted regions. If all lines with highlighted regions fit into the
+// text view, they are centered.
+func (t *TextView) scrollToHighlight(width, height int) {
+	from, to := -1, -1
+	for index, str := range t.buffer {
+		_, highlighted := t.highlights[t.lines[index].start.Region]
+		if !highlighted {
+			for _, region := range regionPattern.FindAllStringSubmatch(str, -1) {
+				if _, highlighted = t.highlights[region[1]]; highlighted {
+					break
+				}
+			}
+		}
+		if highlighted {
+			if from < 0 {
+				from = index
+			}
+			to = index
+		}
+	}
+	if from < 0 {
+		return // Nothing is highlighted.
+	}
+
+	t.lineOffset, t.subLineOffset = from, 0
+
+	// Do we fit the entire height?
+	var count int
+	for index := from; index <= to && count < height; index++ {
+		count += len(t.wrappedLines(index, width))
+	}
+	if count < height {
+		// Yes, let's center the highlights.
+		t.scroll(-(height-count)/2, width)
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (t *TextView) Draw(screen tcell.Screen) {
 	t.Box.Draw(screen)
 
-	t.RLock()
-	defer t.RUnlock()
+	t.Lock()
+	defer t.Unlock()
 
 	// Get the available size.
 	x, y, width, height := t.GetInnerRect()
 	t.pageSize = height
 
-	// If the width has changed, we need to reindex.
-	if width != t.lastWidth {
-		t.index = nil
-	}
-	t.lastWidth = width
+	// Index any lines which were not indexed yet.
+	t.indexLines()
+	t.rows = t.rows[:0]
 
-	// Re-index.
-	t.reindexBuffer(width)
+	// The search bar takes up the last line.
+	if (t.searchPrompt || t.searchPattern != "") && height > 1 {
+		height--
+		t.drawSearchBar(screen, x, y+height, width)
+	}
 
-	// If we don't have an index, there's nothing to draw.
-	if t.index == nil {
+	// If there's no space or no text, there's nothing to draw.
+	if width < 1 || height < 1 || len(t.buffer) == 0 {
 		return
 	}
 
+	// Keep the top line within the buffer.
+	if t.lineOffset >= len(t.buffer) {
+		t.lineOffset, t.subLineOffset = len(t.buffer)-1, 0
+	}
+	if t.lineOffset < 0 {
+		t.lineOffset, t.subLineOffset = 0, 0
+	}
+	if lines := t.wrappedLines(t.lineOffset, width); t.subLineOffset >= len(lines) {
+		t.subLineOffset = len(lines) - 1
+	}
+
 	// Move to highlighted regions.
-	if t.regions && t.scrollToHighlights && t.fromHighlight >= 0 {
-		// Do we fit the entire height?
-		if t.toHighlight-t.fromHighlight+1 < height {
-			// Yes, let's center the highlights.
-			t.lineOffset = (t.fromHighlight + t.toHighlight - height) / 2
-		} else {
-			// No, let's move to the start of the highlights.
-			t.lineOffset = t.fromHighlight
-		}
+	if t.regions && t.scrollToHighlights && len(t.highlights) > 0 {
+		t.scrollToHighlight(width, height)
 	}
 	t.scrollToHighlights = false
 
+	// Move to the current search match.
+	if t.scrollToMatch && t.searchLine >= 0 && t.searchLine < len(t.lines) {
+		if matches := t.lineMatches(t.searchLine); t.searchMatch < len(matches) {
+			match := matches[t.searchMatch]
+			t.scrollToPosition(textViewPosition{line: t.searchLine, pos: match.start}, match.column, match.width, width, height)
+		}
+	}
+	t.scrollToMatch = false
+
+	// Move to the selection cursor.
+	if t.scrollToCursor && t.selecting && t.selectCursor.line < len(t.lines) {
+		text, positions := t.plainText(t.selectCursor.line)
+		var column int
+		for index, pos := range positions {
+			if pos >= t.selectCursor.pos {
+				column = runewidth.StringWidth(string(text[:index]))
+				break
+			}
+		}
+		t.scrollToPosition(t.selectCursor, column, 1, width, height)
+	}
+	t.scrollToCursor = false
+
 	// Adjust line offset.
-	if t.lineOffset+height > len(t.index) {
+	t.scroll(t.scrollLines, width)
+	t.scrollLines = 0
+	visible := t.visibleLines(width, height)
+	if len(visible) < height {
 		t.trackEnd = true
 	}
 	if t.trackEnd {
-		t.lineOffset = len(t.index) - height
+		t.lineOffset = len(t.buffer) - 1
+		t.subLineOffset = len(t.wrappedLines(t.lineOffset, width)) - 1
+		t.scroll(-(height - 1), width)
+		visible = t.visibleLines(width, height)
 	}
-	if t.lineOffset < 0 {
-		t.lineOffset = 0
+
+	// Calculate longest line.
+	t.longestLine = 0
+	for _, line := range visible {
+		if line.Width > t.longestLine {
+			t.longestLine = line.Width
+		}
 	}
 
 	// Adjust column offset.
@@ -709,17 +1764,26 @@ func (t *TextView) Draw(screen tcell.Screen) {
 	}
 
 	// Draw the buffer.
-	for line := t.lineOffset; line < len(t.index); line++ {
-		// Are we done?
-		if line-t.lineOffset >= height {
-			break
-		}
-
+	selectFrom, selectTo, selected := t.selection()
+	for line, index := range visible {
 		// Get the text for this line.
-		index := t.index[line]
 		text := t.buffer[index.Line][index.Pos:index.NextPos]
-		color := index.Color
+		foregroundColor := index.ForegroundColor
+		backgroundColor := index.BackgroundColor
+		attributes := index.Attributes
 		regionID := index.Region
+		matches := t.lineMatches(index.Line)
+
+		// Remember which positions are drawn where.
+		row := textViewRow{
+			line:      index.Line,
+			start:     index.Pos,
+			positions: make([]int, width),
+		}
+		for column := range row.positions {
+			row.positions[column] = -1
+		}
+		t.rows = append(t.rows, row)
 
 		// Get color tags.
 		var (
@@ -762,12 +1826,12 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		}
 
 		// Print the line.
-		var currentTag, currentRegion, currentEscapeTag, skipped int
+		var currentTag, currentRegion, currentEscapeTag, currentMatch, skipped int
 		for pos, ch := range text {
 			// Get the color.
 			if currentTag < len(colorTags) && pos >= colorTagIndices[currentTag][0] && pos < colorTagIndices[currentTag][1] {
 				if pos == colorTagIndices[currentTag][1]-1 {
-					color = tcell.GetColor(colorTags[currentTag][1])
+					foregroundColor, backgroundColor, attributes = styleFromTag(foregroundColor, backgroundColor, attributes, colorTags[currentTag])
 					currentTag++
 				}
 				continue
@@ -809,28 +1873,58 @@ func (t *TextView) Draw(screen tcell.Screen) {
 			}
 
 			// Do we highlight this character?
-			style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(color)
+			style := overlayStyle(tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.textColor), foregroundColor, backgroundColor, attributes)
 			if len(regionID) > 0 {
 				if _, ok := t.highlights[regionID]; ok {
-					style = tcell.StyleDefault.Background(color).Foreground(t.backgroundColor)
+					fg, bg, _ := style.Decompose()
+					style = style.Background(fg).Foreground(bg)
 				}
 			}
 
+			// Is this character part of a search match?
+			for currentMatch < len(matches) && matches[currentMatch].end <= index.Pos+pos {
+				currentMatch++
+			}
+			if currentMatch < len(matches) && matches[currentMatch].start <= index.Pos+pos {
+				if index.Line == t.searchLine && currentMatch == t.searchMatch {
+					style = style.Background(t.searchCurrentColor)
+				} else {
+					style = style.Background(t.searchMatchColor)
+				}
+			}
+
+			// Is this character selected?
+			if selected && t.isSelected(index.Line, index.Pos+pos, selectFrom, selectTo) {
+				style = style.Reverse(true)
+			}
+			if t.selecting && index.Line == t.selectCursor.line && index.Pos+pos == t.selectCursor.pos && t.focus.HasFocus() {
+				screen.ShowCursor(x+posX, y+line)
+			}
+
 			// Draw the character.
 			for offset := 0; offset < chWidth; offset++ {
-				screen.SetContent(x+posX+offset, y+line-t.lineOffset, ch, nil, style)
+				screen.SetContent(x+posX+offset, y+line, ch, nil, style)
+				row.positions[posX+offset] = index.Pos + pos
 			}
 
 			// Advance.
 			posX += chWidth
 		}
+
+		// Show the selection cursor on empty lines, too.
+		if t.selecting && index.Line == t.selectCursor.line && index.Pos == index.NextPos && t.focus.HasFocus() {
+			screen.ShowCursor(x+posX, y+line)
+		}
 	}
 
 	// If this view is not scrollable, we'll purge the buffer of lines that have
 	// scrolled out of view.
 	if !t.scrollable && t.lineOffset > 0 {
-		t.buffer = t.buffer[t.index[t.lineOffset].Line:]
-		t.index = nil
+		t.firstState = t.lines[t.lineOffset].start
+		t.buffer = t.buffer[t.lineOffset:]
+		t.lines = t.lines[t.lineOffset:]
+		t.shiftLines(t.lineOffset)
+		t.lineOffset = 0
 	}
 }
 
@@ -841,6 +1935,37 @@ func (t *TextView) InputHandler() func(tcell.Event, func(Primitive)) {
 		case *tcell.EventKey:
 			key := evt.Key()
 
+			// Enter the search pattern.
+			if t.searchPrompt {
+				t.searchInput(evt)
+				return
+			}
+			if key == tcell.KeyEscape && t.searchPattern != "" {
+				t.ClearSearch()
+				return
+			}
+
+			// Select text.
+			if t.selecting {
+				t.selectionInput(evt)
+				return
+			}
+			if t.selectable && key == tcell.KeyRune {
+				switch evt.Rune() {
+				case 'v', 'V':
+					t.startSelection(evt.Rune() == 'V')
+					return
+				case 'y':
+					t.Copy()
+					t.ClearSelection()
+					return
+				}
+			}
+			if key == tcell.KeyEscape && t.selectAnchor.line >= 0 {
+				t.ClearSelection()
+				return
+			}
+
 			if key == tcell.KeyEscape || key == tcell.KeyEnter || key == tcell.KeyTab || key == tcell.KeyBacktab {
 				if t.done != nil {
 					t.done(key)
@@ -857,44 +1982,138 @@ func (t *TextView) InputHandler() func(tcell.Event, func(Primitive)) {
 				switch evt.Rune() {
 				case 'g': // Home.
 					t.trackEnd = false
-					t.lineOffset = 0
+					t.lineOffset, t.subLineOffset, t.scrollLines = 0, 0, 0
 					t.columnOffset = 0
 				case 'G': // End.
 					t.trackEnd = true
 					t.columnOffset = 0
 				case 'j': // Down.
-					t.lineOffset++
+					t.scrollLines++
 				case 'k': // Up.
 					t.trackEnd = false
-					t.lineOffset--
+					t.scrollLines--
 				case 'h': // Left.
 					t.columnOffset--
 				case 'l': // Right.
 					t.columnOffset++
+				case '/', '?': // Search.
+					if t.searchable {
+						t.startSearch(evt.Rune() == '?')
+					}
+				case 'n': // Next match.
+					t.nextMatch(false)
+				case 'N': // Previous match.
+					t.nextMatch(true)
 				}
 			case tcell.KeyHome:
 				t.trackEnd = false
-				t.lineOffset = 0
+				t.lineOffset, t.subLineOffset, t.scrollLines = 0, 0, 0
 				t.columnOffset = 0
 			case tcell.KeyEnd:
 				t.trackEnd = true
 				t.columnOffset = 0
 			case tcell.KeyUp:
 				t.trackEnd = false
-				t.lineOffset--
+				t.scrollLines--
 			case tcell.KeyDown:
-				t.lineOffset++
+				t.scrollLines++
 			case tcell.KeyLeft:
 				t.columnOffset--
 			case tcell.KeyRight:
 				t.columnOffset++
 			case tcell.KeyPgDn, tcell.KeyCtrlF:
-				t.lineOffset += t.pageSize
+				t.scrollLines += t.pageSize
 			case tcell.KeyPgUp, tcell.KeyCtrlB:
 				t.trackEnd = false
-				t.lineOffset -= t.pageSize
+				t.scrollLines -= t.pageSize
+			}
+		}
+
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive. The text can be
+// scrolled with the mouse wheel or by dragging it with the left mouse button.
+// If the text view is selectable (see SetSelectable()), dragging selects text
+// instead.
+func (t *TextView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.wrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !t.InRect(x, y) && action != MouseLeftDrag && action != MouseLeftUp {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(t)
+			t.dragY = y
+			if t.selectable {
+				t.ClearSelection()
+				t.dragStart, _ = t.positionAt(x, y)
+			}
+			capture = t
+			consumed = true
+		case MouseLeftDrag:
+			if t.selectable {
+				// Extend the selection, scroll if we're outside the text view.
+				if position, ok := t.positionAt(x, y); ok {
+					if t.selectAnchor.line < 0 {
+						t.selectAnchor, t.selectLines = t.dragStart, false
+					}
+					t.selectCursor = position
+				}
+				_, rectY, _, height := t.GetInnerRect()
+				if t.scrollable && y < rectY {
+					t.trackEnd = false
+					t.scrollLines--
+				} else if t.scrollable && y >= rectY+height {
+					t.scrollLines++
+				}
+			} else if t.scrollable && y != t.dragY {
+				t.trackEnd = false
+				t.scrollLines += t.dragY - y
+				t.dragY = y
+			}
+			capture = t
+			consumed = true
+		case MouseLeftUp:
+			if t.selectable && t.selectAnchor.line >= 0 {
+				t.Copy()
+			}
+			consumed = true
+		case MouseLeftDoubleClick:
+			if t.selectable {
+				if position, ok := t.positionAt(x, y); ok {
+					t.selecting = false
+					t.selectAnchor, t.selectCursor, t.selectLines = position, position, true
+					t.Copy()
+				}
+			}
+			consumed = true
+		case MouseScrollUp:
+			if t.scrollable {
+				t.trackEnd = false
+				t.scrollLines--
+			}
+			consumed = true
+		case MouseScrollDown:
+			if t.scrollable {
+				t.scrollLines++
+			}
+			consumed = true
+		case MouseScrollLeft:
+			if t.scrollable {
+				t.columnOffset--
 			}
+			consumed = true
+		case MouseScrollRight:
+			if t.scrollable {
+				t.columnOffset++
+			}
+			consumed = true
 		}
 
+		return
 	})
 }
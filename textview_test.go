@@ -0,0 +1,218 @@
+package tview_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+	"github.com/verdverm/tview/tviewtest"
+)
+
+func TestTextViewMaxLines(t *testing.T) {
+	textView := tview.NewTextView().SetDynamicColors(true).SetMaxLines(100).SetFollow(true)
+	for line := 0; line < 1000; line++ {
+		fmt.Fprintf(textView, "[red]line %d\n", line)
+	}
+	h := tviewtest.New(textView, 20, 5)
+	defer h.Stop()
+
+	if !strings.Contains(h.Text(), "line 999") {
+		t.Errorf("text view does not follow its content:\n%s", h.Text())
+	}
+	var text string
+	h.Do(func() {
+		text = textView.GetText()
+	})
+	if lines := strings.Count(text, "line "); lines > 100 {
+		t.Errorf("text view keeps %d lines, want at most 100", lines)
+	}
+	if !strings.HasPrefix(text, "[red]line 90") {
+		t.Errorf("text view starts with %q, want the newest lines", text[:20])
+	}
+}
+
+func TestTextViewFollow(t *testing.T) {
+	textView := tview.NewTextView().SetFollow(true)
+	for line := 0; line < 20; line++ {
+		fmt.Fprintf(textView, "line %d\n", line)
+	}
+	h := tviewtest.New(textView, 20, 5)
+	defer h.Stop()
+
+	// Scrolling up stops following.
+	h.KeyPress(tcell.KeyUp, 0, tcell.ModNone)
+	h.KeyPress(tcell.KeyUp, 0, tcell.ModNone)
+	var following bool
+	h.Do(func() {
+		following = textView.IsFollowing()
+		fmt.Fprintln(textView, "new")
+	})
+	h.Sync()
+	if following {
+		t.Error("text view still follows its content after scrolling up")
+	}
+	if strings.Contains(h.Text(), "new") {
+		t.Errorf("new text was scrolled into view:\n%s", h.Text())
+	}
+
+	// Scrolling to the end follows again.
+	h.KeyPress(tcell.KeyEnd, 0, tcell.ModNone)
+	if !strings.Contains(h.Text(), "new") {
+		t.Errorf("new text is not visible after scrolling to the end:\n%s", h.Text())
+	}
+}
+
+// searchMatches returns the current and total number of search matches.
+func searchMatches(h *tviewtest.Harness, textView *tview.TextView) (current, total int) {
+	h.Do(func() {
+		current, total = textView.GetSearchMatches()
+	})
+	return
+}
+
+func TestTextViewSearch(t *testing.T) {
+	textView := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetSearchable(true).SetWrap(false)
+	for line := 0; line < 30; line++ {
+		fmt.Fprintf(textView, "line [red]%d[-] f[\"r\"]oo[\"\"] bar [x[]\n", line)
+	}
+	h := tviewtest.New(textView, 30, 6)
+	defer h.Stop()
+
+	// Tags do not interrupt matches.
+	h.KeyPress(tcell.KeyHome, 0, tcell.ModNone)
+	h.Type("/foo")
+	if current, total := searchMatches(h, textView); current != 1 || total != 30 {
+		t.Fatalf("search found match %d of %d, want 1 of 30", current, total)
+	}
+
+	// Navigate between the matches.
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	h.Type(strings.Repeat("n", 12))
+	if current, _ := searchMatches(h, textView); current != 13 {
+		t.Fatalf("n moved to match %d, want 13", current)
+	}
+	if !strings.Contains(h.Text(), "line 12 foo") {
+		t.Errorf("current match is not visible:\n%s", h.Text())
+	}
+	h.Type("N")
+	if current, _ := searchMatches(h, textView); current != 12 {
+		t.Fatalf("N moved to match %d, want 12", current)
+	}
+	h.KeyPress(tcell.KeyEscape, 0, tcell.ModNone)
+
+	// Regular expressions.
+	var errs [2]error
+	h.Do(func() {
+		textView.SetSearchRegexp(true)
+		errs[0] = textView.Search(`\[x\]`)
+		errs[1] = textView.Search(`(`)
+	})
+	if errs[0] != nil {
+		t.Fatal(errs[0])
+	}
+	if errs[1] == nil {
+		t.Error("Search() accepted an invalid regular expression")
+	}
+}
+
+func TestTextViewSelection(t *testing.T) {
+	clipboard := tview.NewMemoryClipboard()
+	textView := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetSelectable(true).SetClipboard(clipboard)
+	for line := 0; line < 10; line++ {
+		fmt.Fprintf(textView, "line [red]%d[-] f[\"r\"]oo[\"\"] [x[]\n", line)
+	}
+	h := tviewtest.New(textView, 30, 5)
+	defer h.Stop()
+
+	// paste returns the text on the clipboard.
+	paste := func() string {
+		text, _ := clipboard.Paste()
+		return text
+	}
+
+	// Select characters with the keyboard.
+	h.KeyPress(tcell.KeyHome, 0, tcell.ModNone)
+	h.Type("vllllll")
+	h.KeyPress(tcell.KeyDown, 0, tcell.ModNone)
+	var selected string
+	h.Do(func() {
+		selected = textView.GetSelectedText()
+	})
+	if selected != "line 0 foo [x]\nline 1 " {
+		t.Errorf("selected text is %q", selected)
+	}
+	h.Type("y")
+	if text := paste(); text != "line 0 foo [x]\nline 1 " {
+		t.Errorf("copied text is %q", text)
+	}
+
+	// Select lines.
+	h.Type("Vj")
+	h.KeyPress(tcell.KeyEnter, 0, tcell.ModNone)
+	if text := paste(); text != "line 0 foo [x]\nline 1 foo [x]" {
+		t.Errorf("copied lines are %q", text)
+	}
+
+	// Select with the mouse.
+	h.Do(func() {
+		h.App.EnableMouse(true)
+	})
+	h.Mouse(5, 1, tcell.Button1, tcell.ModNone)
+	h.Mouse(8, 2, tcell.Button1, tcell.ModNone)
+	h.Mouse(8, 2, tcell.ButtonNone, tcell.ModNone)
+	if text := paste(); text != "1 foo [x]\nline 2 fo" {
+		t.Errorf("text copied with the mouse is %q", text)
+	}
+
+	// Evicting lines clears the selection.
+	h.Do(func() {
+		textView.SetMaxLines(8)
+		selected = textView.GetSelectedText()
+	})
+	if selected != "" {
+		t.Errorf("selected text is %q after evicting lines", selected)
+	}
+}
+
+func TestTextViewConcurrentWrites(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(20, 5)
+
+	textView := tview.NewTextView().SetDynamicColors(true).SetMaxLines(50).SetFollow(true)
+	textView.SetRect(0, 0, 20, 5)
+
+	// Write from a separate goroutine while the text view is drawn, as an
+	// application does when it streams output into a text view.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for line := 0; line < 500; line++ {
+			fmt.Fprintf(textView, "[red]line %d\n", line)
+		}
+	}()
+	for drawing := true; drawing; {
+		select {
+		case <-done:
+			drawing = false
+		default:
+		}
+		textView.Draw(screen)
+	}
+
+	textView.Draw(screen)
+	screen.Show()
+	cells, width, _ := screen.GetContents()
+	var last string
+	for _, cell := range cells[3*width : 4*width] {
+		last += string(cell.Runes)
+	}
+	if !strings.HasPrefix(last, "line 499") {
+		t.Errorf("last line is %q, want line 499", last)
+	}
+}
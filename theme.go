@@ -0,0 +1,180 @@
+package tview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Themable is implemented by primitives whose colors and attributes are taken
+// from a Theme. All primitives of this package implement it through Box.
+type Themable interface {
+	// ApplyTheme sets the primitive's colors and attributes to those of the
+	// given theme. It does not change the primitive's children.
+	ApplyTheme(theme *Theme)
+
+	// GetTheme returns the theme which overrides the application's theme for
+	// this primitive and its children, or nil if there is none.
+	GetTheme() *Theme
+}
+
+// attributeNames maps the names of text attributes used in theme files to
+// tcell's attributes.
+var attributeNames = map[string]tcell.AttrMask{
+	"bold":      tcell.AttrBold,
+	"blink":     tcell.AttrBlink,
+	"dim":       tcell.AttrDim,
+	"italic":    tcell.AttrItalic,
+	"reverse":   tcell.AttrReverse,
+	"underline": tcell.AttrUnderline,
+}
+
+// LoadTheme parses a theme from JSON. The JSON object's keys are the names of
+// the Theme's fields (case-insensitive). Colors are W3C color names or six
+// hexadecimal digits following a hash tag. Attributes are a comma-separated
+// list of "bold", "blink", "dim", "italic", "reverse", and "underline". Fields
+// which are not specified are taken from DefaultTheme. Example:
+//
+//   {
+//     "name": "light",
+//     "primitiveBackgroundColor": "white",
+//     "primaryTextColor": "black",
+//     "titleColor": "#005f87",
+//     "titleAttributes": "bold, underline"
+//   }
+func LoadTheme(data []byte) (*Theme, error) {
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid theme: %s", err)
+	}
+
+	theme := DefaultTheme
+	themeValue := reflect.ValueOf(&theme).Elem()
+	themeType := themeValue.Type()
+	for key, text := range values {
+		index := -1
+		for field := 0; field < themeType.NumField(); field++ {
+			if strings.EqualFold(themeType.Field(field).Name, key) {
+				index = field
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("unknown theme field %q", key)
+		}
+
+		field := themeValue.Field(index)
+		switch field.Interface().(type) {
+		case string:
+			field.SetString(text)
+		case tcell.Color:
+			color, err := parseThemeColor(text)
+			if err != nil {
+				return nil, fmt.Errorf("theme field %q: %s", key, err)
+			}
+			field.Set(reflect.ValueOf(color))
+		case tcell.AttrMask:
+			attributes, err := parseAttributes(text)
+			if err != nil {
+				return nil, fmt.Errorf("theme field %q: %s", key, err)
+			}
+			field.Set(reflect.ValueOf(attributes))
+		}
+	}
+
+	return &theme, nil
+}
+
+// LoadThemeFile reads the file with the given name and parses it with
+// LoadTheme().
+func LoadThemeFile(path string) (*Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	theme, err := LoadTheme(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return theme, nil
+}
+
+// parseThemeColor returns the color with the given name or hex code.
+func parseThemeColor(name string) (tcell.Color, error) {
+	name = strings.TrimSpace(name)
+	color := tcell.GetColor(strings.ToLower(name))
+	if color == tcell.ColorDefault && !strings.EqualFold(name, "default") {
+		return color, fmt.Errorf("unknown color %q", name)
+	}
+	return color, nil
+}
+
+// parseAttributes returns the attributes of a comma-separated list of
+// attribute names.
+func parseAttributes(text string) (tcell.AttrMask, error) {
+	var attributes tcell.AttrMask
+	for _, name := range strings.Split(text, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		attribute, ok := attributeNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown attribute %q", name)
+		}
+		attributes |= attribute
+	}
+	return attributes, nil
+}
+
+// addAttributes returns the given style with the given attributes turned on.
+// Attributes which are already turned on in the style remain on.
+func addAttributes(style tcell.Style, attributes tcell.AttrMask) tcell.Style {
+	if attributes&tcell.AttrBold != 0 {
+		style = style.Bold(true)
+	}
+	if attributes&tcell.AttrBlink != 0 {
+		style = style.Blink(true)
+	}
+	if attributes&tcell.AttrDim != 0 {
+		style = style.Dim(true)
+	}
+	if attributes&tcell.AttrItalic != 0 {
+		style = style.Italic(true)
+	}
+	if attributes&tcell.AttrReverse != 0 {
+		style = style.Reverse(true)
+	}
+	if attributes&tcell.AttrUnderline != 0 {
+		style = style.Underline(true)
+	}
+	return style
+}
+
+// applyTheme applies the given theme to the tree of primitives starting at
+// root. Themes set on primitives with Box.SetTheme() take precedence for their
+// subtrees. Children are themed before their parents so that containers which
+// style their children (e.g. Modal) have the last word.
+func applyTheme(root Primitive, theme *Theme) {
+	if root == nil {
+		return
+	}
+	themable, ok := root.(Themable)
+	if ok {
+		if override := themable.GetTheme(); override != nil {
+			theme = override
+		}
+	}
+	if container, ok := root.(Container); ok {
+		for _, child := range container.Children() {
+			applyTheme(child, theme)
+		}
+	}
+	if ok {
+		themable.ApplyTheme(theme)
+	}
+}
@@ -0,0 +1,84 @@
+package tview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme([]byte(`{"name": "light", "primitiveBackgroundColor": "white", "titleColor": "#ff0000", "titleAttributes": "bold, underline"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Name != "light" || theme.PrimitiveBackgroundColor != tcell.ColorWhite {
+		t.Errorf("theme has name %q and background color %v", theme.Name, theme.PrimitiveBackgroundColor)
+	}
+	if theme.TitleColor != tcell.NewHexColor(0xff0000) || theme.TitleAttributes != tcell.AttrBold|tcell.AttrUnderline {
+		t.Errorf("theme has title color %v and attributes %v", theme.TitleColor, theme.TitleAttributes)
+	}
+	if theme.PrimaryTextColor != DefaultTheme.PrimaryTextColor {
+		t.Errorf("missing color is %v, want the default %v", theme.PrimaryTextColor, DefaultTheme.PrimaryTextColor)
+	}
+
+	for _, data := range []string{
+		`{"unknown": "white"}`,
+		`{"titleColor": "nocolor"}`,
+		`{"titleAttributes": "fat"}`,
+	} {
+		if _, err := LoadTheme([]byte(data)); err == nil {
+			t.Errorf("LoadTheme() accepted %s", data)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	theme := DefaultTheme
+	theme.PrimitiveBackgroundColor = tcell.ColorWhite
+	own := DefaultTheme
+	own.PrimitiveBackgroundColor = tcell.ColorNavy
+
+	box, other := NewBox(), NewBox()
+	other.SetTheme(&own)
+	app := NewApplication().SetRoot(NewFlex().AddItem(box, 0, 1, false).AddItem(other, 0, 1, false), true)
+	app.SetTheme(&theme)
+
+	if box.backgroundColor != tcell.ColorWhite {
+		t.Errorf("box has background color %v, want white", box.backgroundColor)
+	}
+	if other.backgroundColor != tcell.ColorNavy {
+		t.Errorf("box with its own theme has background color %v, want navy", other.backgroundColor)
+	}
+	if Styles != DefaultTheme {
+		t.Error("SetTheme() changed Styles")
+	}
+
+	// A new root receives the application's theme.
+	root := NewBox()
+	app.SetRoot(root, true)
+	if root.backgroundColor != tcell.ColorWhite {
+		t.Errorf("new root has background color %v, want white", root.backgroundColor)
+	}
+
+	app.SetTheme(nil)
+	if app.GetTheme() != &theme {
+		t.Error("SetTheme(nil) replaced the theme")
+	}
+}
+
+func TestSetThemeTableCells(t *testing.T) {
+	theme := DefaultTheme
+	theme.PrimaryTextColor = tcell.ColorBlack
+
+	table := NewTable().
+		SetCell(0, 0, NewTableCell("default")).
+		SetCell(0, 1, NewTableCell("red").SetTextColor(tcell.ColorRed))
+	NewApplication().SetRoot(table, true).SetTheme(&theme)
+
+	if color := table.GetCell(0, 0).Color; color != tcell.ColorBlack {
+		t.Errorf("cell with the default color has color %v, want black", color)
+	}
+	if color := table.GetCell(0, 1).Color; color != tcell.ColorRed {
+		t.Errorf("cell with its own color has color %v, want red", color)
+	}
+}
@@ -0,0 +1,118 @@
+package tview
+
+import "reflect"
+
+// Container is implemented by primitives which contain other primitives, such
+// as Flex, Pages, Form, Frame, and Modal. It allows the primitive tree to be
+// walked (see Walk()).
+type Container interface {
+	Primitive
+
+	// Children returns the primitives directly contained in this primitive, in
+	// the order in which they are drawn.
+	Children() []Primitive
+}
+
+// Walk visits the primitive tree starting at root, depth-first, calling the
+// visit function for every primitive along with its parent (nil for the root).
+// The children of primitives which implement Container are visited after the
+// primitive itself. If the visit function returns false, the walk stops.
+//
+// Walk returns false if it was stopped.
+func Walk(root Primitive, visit func(p, parent Primitive) bool) bool {
+	return walk(root, nil, visit)
+}
+
+// walk is the recursive implementation of Walk().
+func walk(p, parent Primitive, visit func(p, parent Primitive) bool) bool {
+	if p == nil {
+		return true
+	}
+	if !visit(p, parent) {
+		return false
+	}
+	if container, ok := p.(Container); ok {
+		for _, child := range container.Children() {
+			if !walk(child, p, visit) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Find returns the first primitive in the tree starting at root (in the order
+// of Walk()) for which the match function returns true, or nil if there is no
+// such primitive.
+func Find(root Primitive, match func(p Primitive) bool) Primitive {
+	var found Primitive
+	Walk(root, func(p, parent Primitive) bool {
+		if match(p) {
+			found = p
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// FindAll returns all primitives in the tree starting at root (in the order of
+// Walk()) for which the match function returns true.
+func FindAll(root Primitive, match func(p Primitive) bool) []Primitive {
+	var found []Primitive
+	Walk(root, func(p, parent Primitive) bool {
+		if match(p) {
+			found = append(found, p)
+		}
+		return true
+	})
+	return found
+}
+
+// FindById returns the primitive with the given Id() in the tree starting at
+// root or nil if there is no such primitive.
+func FindById(root Primitive, id string) Primitive {
+	return Find(root, func(p Primitive) bool {
+		return p.Id() == id
+	})
+}
+
+// FindByName returns the first primitive with the given Name() in the tree
+// starting at root or nil if there is no such primitive. Unnamed primitives
+// are never found, i.e. an empty name always returns nil.
+func FindByName(root Primitive, name string) Primitive {
+	if name == "" {
+		return nil
+	}
+	return Find(root, func(p Primitive) bool {
+		return p.Name() == name
+	})
+}
+
+// FindByType returns all primitives in the tree starting at root which have
+// the same type as the given example, e.g.:
+//
+//   for _, p := range FindByType(root, (*InputField)(nil)) {
+//     p.(*InputField).SetText("")
+//   }
+func FindByType(root Primitive, example Primitive) []Primitive {
+	t := reflect.TypeOf(example)
+	return FindAll(root, func(p Primitive) bool {
+		return reflect.TypeOf(p) == t
+	})
+}
+
+// Parent returns the container which directly contains the given primitive in
+// the tree starting at root. It returns nil if the primitive is the root or if
+// it is not part of the tree.
+func Parent(root Primitive, child Primitive) Primitive {
+	var found Primitive
+	Walk(root, func(p, parent Primitive) bool {
+		if p == child {
+			found = parent
+			return false
+		}
+		return true
+	})
+	return found
+}
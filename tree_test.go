@@ -0,0 +1,56 @@
+package tview
+
+import "testing"
+
+// newTree returns a tree of primitives containing a named input field.
+func newTree() (root Primitive, form *Form, pages *Pages, input *InputField) {
+	input = NewInputField()
+	input.SetName("user")
+	form = NewForm().AddFormItem(input).AddButton("OK", nil)
+	modal := NewModal().AddButtons([]string{"Yes"})
+	pages = NewPages().
+		AddPage("form", form, true, true).
+		AddPage("modal", modal, true, false)
+	root = NewFlex().
+		AddItem(pages, 0, 1, true).
+		AddItem(NewFrame(NewBox()), 0, 1, false)
+	return
+}
+
+func TestFind(t *testing.T) {
+	root, _, _, input := newTree()
+
+	if found := FindByName(root, "user"); found != input {
+		t.Errorf("FindByName() = %v, want the input field", found)
+	}
+	if found := FindById(root, input.Id()); found != input {
+		t.Errorf("FindById() = %v, want the input field", found)
+	}
+	if found := FindByName(root, "nobody"); found != nil {
+		t.Errorf("FindByName() found %v for an unknown name", found)
+	}
+	if buttons := FindByType(root, (*Button)(nil)); len(buttons) != 2 {
+		t.Errorf("FindByType() found %d buttons, want 2", len(buttons))
+	}
+}
+
+func TestFindByEmptyName(t *testing.T) {
+	root, _, _, _ := newTree()
+	if found := FindByName(root, ""); found != nil {
+		t.Errorf("FindByName() found %v for an empty name", found)
+	}
+}
+
+func TestParent(t *testing.T) {
+	root, form, pages, input := newTree()
+
+	if parent := Parent(root, input); parent != form {
+		t.Errorf("parent of the input field is %v, want the form", parent)
+	}
+	if parent := Parent(root, form); parent != pages {
+		t.Errorf("parent of the form is %v, want the pages", parent)
+	}
+	if parent := Parent(root, root); parent != nil {
+		t.Errorf("parent of the root is %v, want nil", parent)
+	}
+}
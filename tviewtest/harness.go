@@ -0,0 +1,333 @@
+/*
+Package tviewtest runs tview applications on a simulated screen so that they
+can be tested without a terminal.
+
+A Harness starts an application with a tcell.SimulationScreen, injects key,
+mouse, and resize events into its event loop, and waits for the resulting
+redraw before returning. The screen contents can then be inspected as plain
+text, as a grid of cells, or as a snapshot of text and styles which can be
+compared against a golden file:
+
+  func TestLoginForm(t *testing.T) {
+  	form := tview.NewForm().
+  		AddInputField("Name", "", 20, nil, nil).
+  		AddButton("Login", nil)
+  	h := tviewtest.New(form, 40, 10)
+  	defer h.Stop()
+
+  	h.Type("alice")
+  	h.KeyPress(tcell.KeyTab, 0, tcell.ModNone)
+  	h.Golden(t, "testdata/login.golden")
+  }
+
+Golden files are (re)written instead of compared when the environment variable
+TVIEWTEST_UPDATE is set to a non-empty value.
+*/
+package tviewtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+)
+
+// Timeout is the maximum time the harness waits for the application to
+// process an event or an update before it panics.
+var Timeout = 5 * time.Second
+
+// UpdateEnv is the name of the environment variable which, when set to a
+// non-empty value, causes Golden() to write golden files instead of comparing
+// against them.
+const UpdateEnv = "TVIEWTEST_UPDATE"
+
+// Cell is the content of one screen cell.
+type Cell struct {
+	Runes []rune      // The runes displayed in the cell. Empty for the right half of wide characters.
+	Style tcell.Style // The cell's style.
+}
+
+// Harness runs an application on a simulated screen.
+type Harness struct {
+	// The application under test.
+	App *tview.Application
+
+	// The simulated screen the application draws on.
+	Screen tcell.SimulationScreen
+
+	// Receives the return value of the application's Run() function.
+	done chan error
+}
+
+// New starts a new application with the given root primitive on a simulated
+// screen of the given size. The root primitive is resized to fill the screen
+// and receives focus. The function returns once the first screen was drawn.
+func New(root tview.Primitive, width, height int) *Harness {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	h := &Harness{
+		App:    tview.NewApplication().SetScreen(screen).SetRoot(root, true),
+		Screen: screen,
+		done:   make(chan error, 1),
+	}
+	go func() {
+		h.done <- h.App.Run()
+	}()
+	h.Resize(width, height)
+	return h
+}
+
+// Do executes the given function on the application's event loop and waits
+// for it to return. Use it to modify primitives while the application is
+// running. The screen is not redrawn, call Sync() for that.
+func (h *Harness) Do(f func()) {
+	finished := make(chan struct{})
+	h.App.QueueUpdate(func() {
+		f()
+		close(finished)
+	})
+	h.wait(finished)
+}
+
+// Sync waits until all previously injected events have been processed and
+// then redraws the screen.
+func (h *Harness) Sync() {
+	h.Do(func() {
+		h.App.Draw()
+	})
+}
+
+// wait blocks until the given channel is closed. It panics if this does not
+// happen within the Timeout or if the application stopped in the meantime.
+func (h *Harness) wait(finished chan struct{}) {
+	select {
+	case <-finished:
+	case err := <-h.done:
+		h.done <- err
+		panic(fmt.Sprintf("tviewtest: application is not running (error: %v)", err))
+	case <-time.After(Timeout):
+		panic("tviewtest: timeout waiting for the application")
+	}
+}
+
+// Resize changes the size of the simulated screen, sends a resize event to the
+// application, and waits for the redraw.
+func (h *Harness) Resize(width, height int) {
+	h.Do(func() {
+		h.Screen.SetSize(width, height)
+	})
+	h.App.QueueEvent(tcell.NewEventResize(width, height))
+	h.Sync()
+}
+
+// KeyPress sends a key event to the application and waits for the redraw.
+// For printable characters, key must be tcell.KeyRune.
+func (h *Harness) KeyPress(key tcell.Key, ch rune, mod tcell.ModMask) {
+	h.App.QueueEvent(tcell.NewEventKey(key, ch, mod))
+	h.Sync()
+}
+
+// Type sends one key event per rune of the given text to the application and
+// waits for the redraw.
+func (h *Harness) Type(text string) {
+	for _, ch := range text {
+		h.App.QueueEvent(tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone))
+	}
+	h.Sync()
+}
+
+// Mouse sends a mouse event with the given button state to the application and
+// waits for the redraw.
+func (h *Harness) Mouse(x, y int, buttons tcell.ButtonMask, mod tcell.ModMask) {
+	h.App.QueueEvent(tcell.NewEventMouse(x, y, buttons, mod))
+	h.Sync()
+}
+
+// Click sends a left button press and release at the given position to the
+// application and waits for the redraw.
+func (h *Harness) Click(x, y int) {
+	h.App.QueueEvent(tcell.NewEventMouse(x, y, tcell.Button1, tcell.ModNone))
+	h.App.QueueEvent(tcell.NewEventMouse(x, y, tcell.ButtonNone, tcell.ModNone))
+	h.Sync()
+}
+
+// Cells returns the current screen contents, row by row.
+func (h *Harness) Cells() [][]Cell {
+	var (
+		contents      []tcell.SimCell
+		width, height int
+	)
+	h.Do(func() {
+		contents, width, height = h.Screen.GetContents()
+	})
+
+	rows := make([][]Cell, height)
+	for y := range rows {
+		rows[y] = make([]Cell, width)
+		for x := range rows[y] {
+			cell := contents[y*width+x]
+			rows[y][x] = Cell{Runes: cell.Runes, Style: cell.Style}
+		}
+	}
+	return rows
+}
+
+// Text returns the current screen contents as text, one line per row. Trailing
+// spaces are removed from every line.
+func (h *Harness) Text() string {
+	return text(h.Cells())
+}
+
+// text returns the given cells as text, one line per row, without trailing
+// spaces.
+func text(rows [][]Cell) string {
+	lines := make([]string, len(rows))
+	for y, row := range rows {
+		var line []rune
+		for _, cell := range row {
+			line = append(line, cell.Runes...)
+		}
+		lines[y] = strings.TrimRight(string(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Snapshot returns the current screen contents as text followed by the cells'
+// styles. Each distinct style is assigned a letter. The style section contains
+// one line per screen row with the letter of each cell's style, followed by a
+// legend describing the styles. The result is suitable for golden files.
+func (h *Harness) Snapshot() string {
+	rows := h.Cells()
+
+	var buffer bytes.Buffer
+	buffer.WriteString(text(rows))
+	buffer.WriteString("\n--\n")
+
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	var styles []tcell.Style
+	keys := make(map[tcell.Style]byte)
+	for _, row := range rows {
+		for _, cell := range row {
+			key, ok := keys[cell.Style]
+			if !ok {
+				key = '?'
+				if len(styles) < len(letters) {
+					key = letters[len(styles)]
+				}
+				keys[cell.Style] = key
+				styles = append(styles, cell.Style)
+			}
+			buffer.WriteByte(key)
+		}
+		buffer.WriteByte('\n')
+	}
+
+	buffer.WriteString("--\n")
+	for _, style := range styles {
+		fmt.Fprintf(&buffer, "%c: %s\n", keys[style], describeStyle(style))
+	}
+
+	return buffer.String()
+}
+
+// Golden compares the current snapshot (see Snapshot()) with the contents of
+// the golden file at the given path and reports an error to t if they differ.
+// If the UpdateEnv environment variable is set, the golden file is written
+// instead.
+func (h *Harness) Golden(t testing.TB, path string) {
+	t.Helper()
+	snapshot := h.Snapshot()
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := ioutil.WriteFile(path, []byte(snapshot), 0644); err != nil {
+			t.Fatalf("tviewtest: could not write golden file: %s", err)
+		}
+		return
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("tviewtest: could not read golden file (set %s=1 to create it): %s", UpdateEnv, err)
+	}
+	if string(golden) != snapshot {
+		t.Errorf("tviewtest: screen does not match golden file %s\n--- want:\n%s\n--- got:\n%s", path, golden, snapshot)
+	}
+}
+
+// Stop stops the application and returns the error returned by its Run()
+// function.
+func (h *Harness) Stop() error {
+	h.App.Stop()
+	select {
+	case err := <-h.done:
+		h.done <- err
+		return err
+	case <-time.After(Timeout):
+		panic("tviewtest: timeout waiting for the application to stop")
+	}
+}
+
+// describeStyle returns a human-readable description of a style.
+func describeStyle(style tcell.Style) string {
+	fg, bg, attr := style.Decompose()
+	description := fmt.Sprintf("fg=%s bg=%s", colorName(fg), colorName(bg))
+
+	var attrs []string
+	for _, a := range []struct {
+		mask tcell.AttrMask
+		name string
+	}{
+		{tcell.AttrBold, "bold"},
+		{tcell.AttrBlink, "blink"},
+		{tcell.AttrReverse, "reverse"},
+		{tcell.AttrUnderline, "underline"},
+		{tcell.AttrDim, "dim"},
+		{tcell.AttrItalic, "italic"},
+	} {
+		if attr&a.mask != 0 {
+			attrs = append(attrs, a.name)
+		}
+	}
+	if len(attrs) > 0 {
+		description += " attrs=" + strings.Join(attrs, ",")
+	}
+
+	return description
+}
+
+// colorNames maps colors to their names. If a color has multiple names, the
+// alphabetically first one is used.
+var colorNames map[tcell.Color]string
+
+func init() {
+	names := make([]string, 0, len(tcell.ColorNames))
+	for name := range tcell.ColorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	colorNames = make(map[tcell.Color]string)
+	for _, name := range names {
+		color := tcell.ColorNames[name]
+		if _, ok := colorNames[color]; !ok {
+			colorNames[color] = name
+		}
+	}
+}
+
+// colorName returns the name of a color or its hexadecimal RGB value if it has
+// no name.
+func colorName(color tcell.Color) string {
+	if color == tcell.ColorDefault {
+		return "default"
+	}
+	if name, ok := colorNames[color]; ok {
+		return name
+	}
+	return fmt.Sprintf("#%06x", color.Hex())
+}
@@ -0,0 +1,197 @@
+package tviewtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/verdverm/tview"
+)
+
+// recorder is a testing.TB which records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+// textView returns a new text view with the given text.
+func textView(text string) *tview.TextView {
+	textView := tview.NewTextView()
+	fmt.Fprint(textView, text)
+	return textView
+}
+
+// layout returns a small layout with a border, a form, and a text view.
+func layout() tview.Primitive {
+	form := tview.NewForm().
+		AddInputField("Name", "", 10, nil, nil).
+		AddButton("OK", nil)
+	text := textView("Hello")
+	flex := tview.NewFlex().
+		AddItem(form, 0, 1, true).
+		AddItem(text, 7, 0, false)
+	flex.SetBorder(true).SetTitle("Layout")
+	return flex
+}
+
+func TestText(t *testing.T) {
+	h := New(textView("first\nsecond"), 10, 3)
+	defer h.Stop()
+
+	if text, want := h.Text(), "first\nsecond\n"; text != want {
+		t.Errorf("Text() = %q, want %q", text, want)
+	}
+}
+
+func TestCells(t *testing.T) {
+	h := New(textView("[red]x").SetDynamicColors(true), 4, 2)
+	defer h.Stop()
+
+	cells := h.Cells()
+	if len(cells) != 2 || len(cells[0]) != 4 {
+		t.Fatalf("Cells() returned %d rows, want 2 rows of 4 cells", len(cells))
+	}
+	if string(cells[0][0].Runes) != "x" {
+		t.Errorf("cell (0,0) = %q, want %q", string(cells[0][0].Runes), "x")
+	}
+	if fg, _, _ := cells[0][0].Style.Decompose(); fg != tcell.ColorRed {
+		t.Errorf("cell (0,0) has foreground color %v, want red", fg)
+	}
+}
+
+func TestResize(t *testing.T) {
+	h := New(tview.NewBox().SetBorder(true), 10, 3)
+	defer h.Stop()
+
+	h.Resize(6, 4)
+	cells := h.Cells()
+	if len(cells) != 4 || len(cells[0]) != 6 {
+		t.Fatalf("screen is %dx%d after Resize(), want 6x4", len(cells[0]), len(cells))
+	}
+	if lines := strings.Split(h.Text(), "\n"); lines[3] != "╚════╝" {
+		t.Errorf("bottom border is %q after Resize()", lines[3])
+	}
+}
+
+func TestTypeAndKeyPress(t *testing.T) {
+	input := tview.NewInputField()
+	h := New(input, 20, 1)
+	defer h.Stop()
+
+	h.Type("abc")
+	h.KeyPress(tcell.KeyBackspace2, 0, tcell.ModNone)
+	var text string
+	h.Do(func() {
+		text = input.GetText()
+	})
+	if text != "ab" {
+		t.Errorf("input field contains %q, want %q", text, "ab")
+	}
+	if screen := h.Text(); screen != "ab" {
+		t.Errorf("screen shows %q, want %q", screen, "ab")
+	}
+}
+
+func TestClick(t *testing.T) {
+	var clicked bool
+	button := tview.NewButton("OK").SetSelectedFunc(func() {
+		clicked = true
+	})
+	h := New(button, 10, 1)
+	defer h.Stop()
+
+	h.Click(2, 0)
+	if !clicked {
+		t.Error("button was not selected by a click")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	h := New(textView("a[red]b").SetDynamicColors(true), 3, 1)
+	defer h.Stop()
+
+	want := "ab\n--\nabc\n--\na: fg=white bg=black\nb: fg=red bg=black\nc: fg=default bg=black\n"
+	if snapshot := h.Snapshot(); snapshot != want {
+		t.Errorf("Snapshot() = %q, want %q", snapshot, want)
+	}
+}
+
+func TestDescribeStyle(t *testing.T) {
+	for _, test := range []struct {
+		style       tcell.Style
+		description string
+	}{
+		{tcell.StyleDefault, "fg=default bg=default"},
+		{tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true), "fg=red bg=default attrs=bold"},
+		{tcell.StyleDefault.Italic(true), "fg=default bg=default attrs=italic"},
+		{tcell.StyleDefault.Underline(true).Dim(true).Italic(true), "fg=default bg=default attrs=underline,dim,italic"},
+	} {
+		if description := describeStyle(test.style); description != test.description {
+			t.Errorf("describeStyle() = %q, want %q", description, test.description)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	h := New(layout(), 30, 8)
+	defer h.Stop()
+
+	h.Type("Ann")
+	h.Golden(t, "testdata/layout.golden")
+
+	// A different screen must not match.
+	if os.Getenv(UpdateEnv) == "" {
+		h.Type("e")
+		r := &recorder{TB: t}
+		h.Golden(r, "testdata/layout.golden")
+		if !r.failed {
+			t.Error("Golden() did not report a changed screen")
+		}
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tviewtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "update.golden")
+
+	h := New(textView("update"), 8, 1)
+	defer h.Stop()
+
+	os.Setenv(UpdateEnv, "1")
+	h.Golden(t, path)
+	os.Unsetenv(UpdateEnv)
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(golden) != h.Snapshot() {
+		t.Errorf("golden file contains %q, want %q", golden, h.Snapshot())
+	}
+	h.Golden(t, path)
+}
+
+func TestStop(t *testing.T) {
+	h := New(tview.NewBox(), 5, 5)
+	if err := h.Stop(); err != nil {
+		t.Errorf("Stop() returned %v", err)
+	}
+}
@@ -37,15 +37,18 @@ const (
 	GraphicsBottomT             = '\u2534'
 	GraphicsCross               = '\u253c'
 	GraphicsEllipsis            = '\u2026'
+	GraphicsSortAscending       = '\u25b2'
+	GraphicsSortDescending      = '\u25bc'
 )
 
 // Common regular expressions.
 var (
-	colorPattern    = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6})\]`)
+	colorPattern    = regexp.MustCompile(`\[(?:([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)|([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?:([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(?::([bdilru]+|\-)?)?)\]`)
 	regionPattern   = regexp.MustCompile(`\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
-	escapePattern   = regexp.MustCompile(`\[("[a-zA-Z0-9_,;: \-\.]*"|[a-zA-Z]+|#[0-9a-zA-Z]{6})\[(\[*)\]`)
+	escapePattern   = regexp.MustCompile(`\[([a-zA-Z0-9_,;: \-\."#]+)\[(\[*)\]`)
 	boundaryPattern = regexp.MustCompile("([[:punct:]]\\s*|\\s+)")
 	spacePattern    = regexp.MustCompile(`\s+`)
+	tagPattern      = regexp.MustCompile(`(\[[a-zA-Z0-9_,;: \-\."#]+\[*)\]`)
 )
 
 // Predefined InputField acceptance functions.
@@ -89,14 +92,21 @@ func init() {
 
 // Print prints text onto the screen into the given box at (x,y,maxWidth,1),
 // not exceeding that box. "align" is one of AlignLeft, AlignCenter, or
-// AlignRight. The screen's background color will not be changed.
+// AlignRight. The screen's background color will not be changed unless a color
+// tag sets it.
 //
-// You can change the text color mid-text by inserting a color tag. See the
-// package description for details.
+// You can change the text color, the background color, and the text attributes
+// mid-text by inserting color tags. See the package description for details.
 //
 // Returns the number of actual runes printed (not including color tags) and the
 // actual width used for the printed runes.
 func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tcell.Color) (int, int) {
+	return printWithAttributes(screen, text, x, y, maxWidth, align, color, 0)
+}
+
+// printWithAttributes works like Print() but also turns on the given text
+// attributes for the printed runes.
+func printWithAttributes(screen tcell.Screen, text string, x, y, maxWidth, align int, color tcell.Color, attributes tcell.AttrMask) (int, int) {
 	if maxWidth < 0 {
 		return 0, 0
 	}
@@ -110,17 +120,18 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 	// We deal with runes, not with bytes.
 	runes := []rune(strippedText)
 
-	// This helper function takes positions for a substring of "runes" and a start
-	// color and returns the substring with the original tags and the new start
-	// color.
-	substring := func(from, to int, color tcell.Color) (string, tcell.Color) {
+	// This helper function takes positions for a substring of "runes" and
+	// returns the substring with the original tags, preceded by a color tag
+	// which restores the style in effect at its start.
+	substring := func(from, to int) string {
+		var foregroundColor, backgroundColor, tagAttributes string
 		var colorPos, escapePos, runePos, startPos int
 		for pos := range text {
 			// Handle color tags.
 			if colorPos < len(colorIndices) && pos >= colorIndices[colorPos][0] && pos < colorIndices[colorPos][1] {
 				if pos == colorIndices[colorPos][1]-1 {
 					if runePos <= from {
-						color = tcell.GetColor(colors[colorPos][1])
+						foregroundColor, backgroundColor, tagAttributes = styleFromTag(foregroundColor, backgroundColor, tagAttributes, colors[colorPos])
 					}
 					colorPos++
 				}
@@ -140,13 +151,13 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 			if runePos == from {
 				startPos = pos
 			} else if runePos >= to {
-				return text[startPos:pos], color
+				return styleTag(foregroundColor, backgroundColor, tagAttributes) + text[startPos:pos]
 			}
 
 			runePos++
 		}
 
-		return text[startPos:len(text)], color
+		return styleTag(foregroundColor, backgroundColor, tagAttributes) + text[startPos:len(text)]
 	}
 
 	// We want to reduce everything to AlignLeft.
@@ -161,17 +172,17 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 			width += w
 			start = index
 		}
-		text, color = substring(start, len(runes), color)
-		return Print(screen, text, x+maxWidth-width, y, width, AlignLeft, color)
+		text = substring(start, len(runes))
+		return printWithAttributes(screen, text, x+maxWidth-width, y, width, AlignLeft, color, attributes)
 	} else if align == AlignCenter {
 		width := runewidth.StringWidth(strippedText)
 		if width == maxWidth {
 			// Use the exact space.
-			return Print(screen, text, x, y, maxWidth, AlignLeft, color)
+			return printWithAttributes(screen, text, x, y, maxWidth, AlignLeft, color, attributes)
 		} else if width < maxWidth {
 			// We have more space than we need.
 			half := (maxWidth - width) / 2
-			return Print(screen, text, x+half, y, maxWidth-half, AlignLeft, color)
+			return printWithAttributes(screen, text, x+half, y, maxWidth-half, AlignLeft, color, attributes)
 		} else {
 			// Chop off runes until we have a perfect fit.
 			var choppedLeft, choppedRight, leftIndex, rightIndex int
@@ -187,20 +198,21 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 					rightIndex--
 				}
 			}
-			text, color = substring(leftIndex, rightIndex, color)
-			return Print(screen, text, x, y, maxWidth, AlignLeft, color)
+			text = substring(leftIndex, rightIndex)
+			return printWithAttributes(screen, text, x, y, maxWidth, AlignLeft, color, attributes)
 		}
 	}
 
 	// Draw text.
 	drawn := 0
 	drawnWidth := 0
+	var foregroundColor, backgroundColor, tagAttributes string
 	var colorPos, escapePos int
 	for pos, ch := range text {
 		// Handle color tags.
 		if colorPos < len(colorIndices) && pos >= colorIndices[colorPos][0] && pos < colorIndices[colorPos][1] {
 			if pos == colorIndices[colorPos][1]-1 {
-				color = tcell.GetColor(colors[colorPos][1])
+				foregroundColor, backgroundColor, tagAttributes = styleFromTag(foregroundColor, backgroundColor, tagAttributes, colors[colorPos])
 				colorPos++
 			}
 			continue
@@ -224,7 +236,7 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 
 		// Print the rune.
 		_, _, style, _ := screen.GetContent(finalX, y)
-		style = style.Foreground(color)
+		style = overlayStyle(addAttributes(style.Foreground(color), attributes), foregroundColor, backgroundColor, tagAttributes)
 		for offset := 0; offset < chWidth; offset++ {
 			// To avoid undesired effects, we place the same character in all cells.
 			screen.SetContent(finalX+offset, y, ch, nil, style)
@@ -237,11 +249,80 @@ func Print(screen tcell.Screen, text string, x, y, maxWidth, align int, color tc
 	return drawn, drawnWidth
 }
 
+// styleFromTag takes the style defined by a foreground color, a background
+// color, and attributes (as found in color tags) and modifies it based on the
+// substrings of a color tag matched by colorPattern. The components of the
+// resulting style are returned. Empty components of the tag leave the style's
+// components unchanged.
+func styleFromTag(foregroundColor, backgroundColor, attributes string, tagSubstrings []string) (newForegroundColor, newBackgroundColor, newAttributes string) {
+	if color := tagSubstrings[1] + tagSubstrings[2]; color != "" {
+		foregroundColor = color
+	}
+	if tagSubstrings[3] != "" {
+		backgroundColor = tagSubstrings[3]
+	}
+	if tagSubstrings[4] != "" {
+		attributes = tagSubstrings[4]
+	}
+	return foregroundColor, backgroundColor, attributes
+}
+
+// styleTag returns a color tag which sets the given style components or an
+// empty string if all components are empty.
+func styleTag(foregroundColor, backgroundColor, attributes string) string {
+	if foregroundColor == "" && backgroundColor == "" && attributes == "" {
+		return ""
+	}
+	return "[" + foregroundColor + ":" + backgroundColor + ":" + attributes + "]"
+}
+
+// overlayStyle applies the style components of color tags (see styleFromTag())
+// to the given default style. Empty components and components set to "-" leave
+// the default style's components unchanged.
+func overlayStyle(defaultStyle tcell.Style, foregroundColor, backgroundColor, attributes string) tcell.Style {
+	style := defaultStyle
+	if foregroundColor != "" && foregroundColor != "-" {
+		style = style.Foreground(tcell.GetColor(foregroundColor))
+	}
+	if backgroundColor != "" && backgroundColor != "-" {
+		style = style.Background(tcell.GetColor(backgroundColor))
+	}
+	if attributes != "" && attributes != "-" {
+		style = style.Normal()
+		for _, letter := range attributes {
+			switch letter {
+			case 'b':
+				style = style.Bold(true)
+			case 'd':
+				style = style.Dim(true)
+			case 'i':
+				style = style.Italic(true)
+			case 'l':
+				style = style.Blink(true)
+			case 'r':
+				style = style.Reverse(true)
+			case 'u':
+				style = style.Underline(true)
+			}
+		}
+	}
+	return style
+}
+
 // PrintSimple prints white text to the screen at the given position.
 func PrintSimple(screen tcell.Screen, text string, x, y int) {
 	Print(screen, text, x, y, math.MaxInt32, AlignLeft, Styles.PrimaryTextColor)
 }
 
+// Escape escapes the given text such that color and region tags are not
+// recognized and substituted by the print functions of this package. For
+// example, to include a tag-like string in a box title, use:
+//
+//   box.SetTitle(tview.Escape("[squarebrackets]"))
+func Escape(text string) string {
+	return tagPattern.ReplaceAllString(text, "$1[]")
+}
+
 // StringWidth returns the width of the given string needed to print it on
 // screen. The text may contain color tags which are not counted.
 func StringWidth(text string) int {
@@ -252,19 +333,55 @@ func StringWidth(text string) int {
 // given screen width. Possible split points are after any punctuation or
 // whitespace. Whitespace after split points will be dropped.
 //
-// This function considers color tags to have no width.
+// This function considers color tags to have no width. Lines start with a
+// color tag restoring the style of the color tags of the lines before them.
 //
 // Text is always split at newline characters ('\n').
 func WordWrap(text string, width int) (lines []string) {
+	text = strings.TrimSpace(text)
+	positions := wordWrapPositions(text, width, true)
+	var foregroundColor, backgroundColor, attributes string
+	for index, position := range positions {
+		line := text[position[0]:position[1]]
+		if index == len(positions)-1 {
+			// Process remaining text.
+			line = strings.TrimSpace(line)
+			if len(line) == 0 {
+				break
+			}
+		}
+
+		// Lines continue with the style of the color tags before them,
+		// including those in whitespace dropped at the split point.
+		if index > 0 {
+			for _, tag := range colorPattern.FindAllStringSubmatch(text[positions[index-1][0]:position[0]], -1) {
+				foregroundColor, backgroundColor, attributes = styleFromTag(foregroundColor, backgroundColor, attributes, tag)
+			}
+			line = styleTag(foregroundColor, backgroundColor, attributes) + line
+		}
+		lines = append(lines, line)
+	}
+
+	return
+}
+
+// wordWrapPositions returns the start and end byte positions of the lines of
+// the given text when it is split as described for WordWrap(). Whitespace
+// after split points and newline characters lie between the end of one line
+// and the start of the next. The last line ends at the end of the text. If
+// "tags" is true, color tags and escaped tags are taken into account.
+func wordWrapPositions(text string, width int, tags bool) (positions [][2]int) {
 	x := 0
 	start := 0
 	candidate := -1 // -1 = no candidate yet.
 	startAfterCandidate := 0
 	countAfterCandidate := 0
 	var evaluatingCandidate bool
-	text = strings.TrimSpace(text)
-	colorIndices := colorPattern.FindAllStringIndex(text, -1)
-	escapeIndices := escapePattern.FindAllStringIndex(text, -1)
+	var colorIndices, escapeIndices [][]int
+	if tags {
+		colorIndices = colorPattern.FindAllStringIndex(text, -1)
+		escapeIndices = escapePattern.FindAllStringIndex(text, -1)
+	}
 
 	var colorPos, escapePos int
 	for pos, ch := range text {
@@ -291,11 +408,11 @@ func WordWrap(text string, width int) (lines []string) {
 		if !evaluatingCandidate && x >= width {
 			// We've exceeded the width, we must split.
 			if candidate >= 0 {
-				lines = append(lines, text[start:candidate])
+				positions = append(positions, [2]int{start, candidate})
 				start = startAfterCandidate
 				x = countAfterCandidate
 			} else {
-				lines = append(lines, text[start:pos])
+				positions = append(positions, [2]int{start, pos})
 				start = pos
 				x = 0
 			}
@@ -318,8 +435,9 @@ func WordWrap(text string, width int) (lines []string) {
 			}
 			// Split in any case.
 		case ch == '\n':
-			lines = append(lines, text[start:pos])
+			positions = append(positions, [2]int{start, pos})
 			start = pos + 1
+			candidate = -1
 			evaluatingCandidate = false
 			countAfterCandidate = 0
 			x = 0
@@ -336,11 +454,6 @@ func WordWrap(text string, width int) (lines []string) {
 		countAfterCandidate += chWidth
 	}
 
-	// Process remaining text.
-	text = strings.TrimSpace(text[start:])
-	if len(text) > 0 {
-		lines = append(lines, text)
-	}
-
-	return
+	// Add the remaining text.
+	return append(positions, [2]int{start, len(text)})
 }
@@ -0,0 +1,134 @@
+package tview
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestColorPattern(t *testing.T) {
+	for _, test := range []struct {
+		text string
+		tag  []string // The submatches of the tag, nil if it is not a tag.
+	}{
+		{"[red]", []string{"[red]", "red", "", "", ""}},
+		{"[#ff0000]", []string{"[#ff0000]", "#ff0000", "", "", ""}},
+		{"[-]", []string{"[-]", "-", "", "", ""}},
+		{"[red:blue]", []string{"[red:blue]", "", "red", "blue", ""}},
+		{"[:blue]", []string{"[:blue]", "", "", "blue", ""}},
+		{"[red::bu]", []string{"[red::bu]", "", "red", "", "bu"}},
+		{"[::i]", []string{"[::i]", "", "", "", "i"}},
+		{"[-:-:-]", []string{"[-:-:-]", "", "-", "-", "-"}},
+		{"[::]", []string{"[::]", "", "", "", ""}},
+		{"[]", nil},
+		{"[red blue]", nil},
+		{"[red:blue:x]", nil},
+		{"[#ff00]", nil},
+		{`["region"]`, nil},
+	} {
+		tag := colorPattern.FindStringSubmatch(test.text)
+		if !reflect.DeepEqual(tag, test.tag) {
+			t.Errorf("%q: matched %q, want %q", test.text, tag, test.tag)
+		}
+	}
+}
+
+func TestStyleFromTag(t *testing.T) {
+	for _, test := range []struct {
+		tag                      string
+		foreground, background   string
+		attributes               string
+		wantFg, wantBg, wantAttr string
+	}{
+		{"[red]", "", "", "", "red", "", ""},
+		{"[red]", "blue", "green", "b", "red", "green", "b"},
+		{"[:yellow]", "blue", "green", "b", "blue", "yellow", "b"},
+		{"[::u]", "blue", "green", "b", "blue", "green", "u"},
+		{"[::]", "blue", "green", "b", "blue", "green", "b"},
+		{"[-]", "blue", "green", "b", "-", "green", "b"},
+		{"[-:-:-]", "blue", "green", "b", "-", "-", "-"},
+	} {
+		fg, bg, attr := styleFromTag(test.foreground, test.background, test.attributes, colorPattern.FindStringSubmatch(test.tag))
+		if fg != test.wantFg || bg != test.wantBg || attr != test.wantAttr {
+			t.Errorf("%q on %q:%q:%q: got %q:%q:%q, want %q:%q:%q", test.tag, test.foreground, test.background, test.attributes, fg, bg, attr, test.wantFg, test.wantBg, test.wantAttr)
+		}
+	}
+}
+
+func TestStyleTag(t *testing.T) {
+	for _, test := range []struct {
+		foreground, background, attributes string
+		tag                                string
+	}{
+		{"", "", "", ""},
+		{"red", "", "", "[red::]"},
+		{"", "blue", "b", "[:blue:b]"},
+		{"-", "-", "-", "[-:-:-]"},
+	} {
+		if tag := styleTag(test.foreground, test.background, test.attributes); tag != test.tag {
+			t.Errorf("%q:%q:%q: got %q, want %q", test.foreground, test.background, test.attributes, tag, test.tag)
+		}
+	}
+}
+
+func TestOverlayStyle(t *testing.T) {
+	base := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack).Bold(true)
+	for _, test := range []struct {
+		foreground, background, attributes string
+		want                               tcell.Style
+	}{
+		{"", "", "", base},
+		{"-", "-", "-", base},
+		{"red", "", "", base.Foreground(tcell.ColorRed)},
+		{"", "#0000ff", "", base.Background(tcell.NewHexColor(0x0000ff))},
+		{"", "", "u", base.Normal().Foreground(tcell.ColorWhite).Background(tcell.ColorBlack).Underline(true)},
+		{"", "", "bdilru", base.Dim(true).Italic(true).Blink(true).Reverse(true).Underline(true)},
+	} {
+		if style := overlayStyle(base, test.foreground, test.background, test.attributes); style != test.want {
+			t.Errorf("%q:%q:%q: got %v, want %v", test.foreground, test.background, test.attributes, style, test.want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	for _, test := range []struct {
+		text, escaped string
+		width         int
+	}{
+		{"plain", "plain", 5},
+		{"[red]", "[red[]", 5},
+		{"[red:blue:b]", "[red:blue:b[]", 12},
+		{`["region"]`, `["region"[]`, 10},
+		{"[a] and [b]", "[a[] and [b[]", 11},
+	} {
+		escaped := Escape(test.text)
+		if escaped != test.escaped {
+			t.Errorf("Escape(%q) = %q, want %q", test.text, escaped, test.escaped)
+		}
+		if width := StringWidth(escaped); width != test.width {
+			t.Errorf("escaped %q has width %d, want %d", test.text, width, test.width)
+		}
+	}
+	if width := StringWidth("[red]text[-]"); width != 4 {
+		t.Errorf("color tags have width %d", width-4)
+	}
+}
+
+func TestWordWrapStyles(t *testing.T) {
+	for _, test := range []struct {
+		text  string
+		width int
+		lines []string
+	}{
+		{"one two", 4, []string{"one", "two"}},
+		{"[red]one two", 4, []string{"[red]one", "[red::]two"}},
+		{"[red]one [::b]two three", 5, []string{"[red]one", "[red::b]two", "[red::b]three"}},
+		{"[red]one[-] two", 4, []string{"[red]one[-]", "[-::]two"}},
+		{"[:blue]one\ntwo", 10, []string{"[:blue]one", "[:blue:]two"}},
+	} {
+		if lines := WordWrap(test.text, test.width); !reflect.DeepEqual(lines, test.lines) {
+			t.Errorf("WordWrap(%q, %d) = %q, want %q", test.text, test.width, lines, test.lines)
+		}
+	}
+}